@@ -1,6 +1,7 @@
 package rawtopic
 
 import (
+	"bytes"
 	"fmt"
 	"time"
 
@@ -98,15 +99,29 @@ type PartitionInfo struct {
 	Active             bool
 	ChildPartitionIDs  []int64
 	ParentPartitionIDs []int64
+	KeyRange           PartitionKeyRange
 	PartitionStats     PartitionStats
 }
 
+// PartitionKeyRange is a range of keys [FromBound, ToBound) of partition in autopartitioned topic.
+// Empty FromBound mean unbounded start, empty ToBound mean unbounded end.
+type PartitionKeyRange struct {
+	FromBound []byte
+	ToBound   []byte
+}
+
+func (r *PartitionKeyRange) FromProto(proto *Ydb_Topic.PartitionKeyRange) {
+	r.FromBound = bytes.Clone(proto.GetFromBound())
+	r.ToBound = bytes.Clone(proto.GetToBound())
+}
+
 func (pi *PartitionInfo) FromProto(proto *Ydb_Topic.DescribeTopicResult_PartitionInfo) error {
 	pi.PartitionID = proto.GetPartitionId()
 	pi.Active = proto.GetActive()
 
 	pi.ChildPartitionIDs = clone.Int64Slice(proto.GetChildPartitionIds())
 	pi.ParentPartitionIDs = clone.Int64Slice(proto.GetParentPartitionIds())
+	pi.KeyRange.FromProto(proto.GetKeyRange())
 
 	return pi.PartitionStats.FromProto(proto.GetPartitionStats())
 }
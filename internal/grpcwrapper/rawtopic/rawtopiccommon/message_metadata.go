@@ -4,3 +4,13 @@ type MetadataItem struct {
 	Key   string
 	Value []byte
 }
+
+// MetadataSize returns total size of keys and values of the metadata
+func MetadataSize(metadata map[string][]byte) int {
+	size := 0
+	for key, value := range metadata {
+		size += len(key) + len(value)
+	}
+
+	return size
+}
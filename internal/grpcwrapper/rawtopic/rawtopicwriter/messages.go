@@ -281,7 +281,9 @@ func (r *WriteResult) GetAcks() (res traceAck) {
 	res.AcksCount = len(r.Acks)
 	if res.AcksCount > 0 {
 		res.SeqNoMin = r.Acks[0].SeqNo
+		res.SeqNoMax = r.Acks[0].SeqNo
 		res.WrittenOffsetMin = r.Acks[0].MessageWriteStatus.WrittenOffset
+		res.WrittenOffsetMax = r.Acks[0].MessageWriteStatus.WrittenOffset
 	}
 	for i := range r.Acks {
 		ack := &r.Acks[i]
@@ -300,7 +302,7 @@ func (r *WriteResult) GetAcks() (res traceAck) {
 			res.SeqNoMax = ack.SeqNo
 		}
 
-		if ack.MessageWriteStatus.WrittenOffset < res.SeqNoMin {
+		if ack.MessageWriteStatus.WrittenOffset < res.WrittenOffsetMin {
 			res.WrittenOffsetMin = ack.MessageWriteStatus.WrittenOffset
 		} else if ack.MessageWriteStatus.WrittenOffset > res.WrittenOffsetMax {
 			res.WrittenOffsetMax = ack.MessageWriteStatus.WrittenOffset
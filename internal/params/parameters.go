@@ -34,6 +34,9 @@ type (
 
 func (p *Params) Range() xiter.Seq2[string, value.Value] {
 	return func(yield func(name string, v value.Value) bool) {
+		if p == nil {
+			return
+		}
 		for _, param := range *p {
 			cont := yield(param.name, param.value)
 			if !cont {
@@ -2,6 +2,9 @@ package params
 
 import (
 	"strconv"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/pg"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/value"
@@ -22,6 +25,14 @@ func (p pgParam) Value(oid uint32, val string) Builder {
 	return p.param.parent
 }
 
+func (p pgParam) Bool(val bool) Builder {
+	return p.Value(pg.OIDBool, pg.FormatBool(val))
+}
+
+func (p pgParam) Int2(val int16) Builder {
+	return p.Value(pg.OIDInt2, strconv.FormatInt(int64(val), 10))
+}
+
 func (p pgParam) Int4(val int32) Builder {
 	return p.Value(pg.OIDInt4, strconv.FormatInt(int64(val), 10))
 }
@@ -29,3 +40,91 @@ func (p pgParam) Int4(val int32) Builder {
 func (p pgParam) Int8(val int64) Builder {
 	return p.Value(pg.OIDInt8, strconv.FormatInt(val, 10))
 }
+
+func (p pgParam) Float4(val float32) Builder {
+	return p.Value(pg.OIDFloat4, strconv.FormatFloat(float64(val), 'g', -1, 32))
+}
+
+func (p pgParam) Float8(val float64) Builder {
+	return p.Value(pg.OIDFloat8, strconv.FormatFloat(val, 'g', -1, 64))
+}
+
+// Numeric makes numeric param from text representation like "123.456"
+func (p pgParam) Numeric(val string) Builder {
+	return p.Value(pg.OIDNumeric, val)
+}
+
+func (p pgParam) Text(val string) Builder {
+	return p.Value(pg.OIDText, val)
+}
+
+func (p pgParam) Varchar(val string) Builder {
+	return p.Value(pg.OIDVarchar, val)
+}
+
+func (p pgParam) Bytea(val []byte) Builder {
+	return p.Value(pg.OIDBytea, pg.FormatBytea(val))
+}
+
+func (p pgParam) Date(val time.Time) Builder {
+	return p.Value(pg.OIDDate, pg.FormatDate(val))
+}
+
+// Timestamp makes timestamp without time zone param from UTC representation of val
+func (p pgParam) Timestamp(val time.Time) Builder {
+	return p.Value(pg.OIDTimestamp, pg.FormatTimestamp(val))
+}
+
+func (p pgParam) Timestamptz(val time.Time) Builder {
+	return p.Value(pg.OIDTimestamptz, pg.FormatTimestamptz(val))
+}
+
+func (p pgParam) JSON(val string) Builder {
+	return p.Value(pg.OIDJSON, val)
+}
+
+func (p pgParam) JSONB(val string) Builder {
+	return p.Value(pg.OIDJSONB, val)
+}
+
+func (p pgParam) UUID(val uuid.UUID) Builder {
+	return p.Value(pg.OIDUUID, val.String())
+}
+
+// Array makes one-dimensional array param from text representations of items.
+// If array type for elemOID is unknown then param has unknown type
+func (p pgParam) Array(elemOID uint32, items ...string) Builder {
+	ptrs := make([]*string, len(items))
+	for i := range items {
+		ptrs[i] = &items[i]
+	}
+
+	oid, ok := pg.ArrayOID(elemOID)
+	if !ok {
+		oid = pg.OIDUnknown
+	}
+
+	return p.Value(oid, pg.FormatArray(ptrs))
+}
+
+func (p pgParam) Int4Array(val []int32) Builder {
+	items := make([]string, len(val))
+	for i := range val {
+		items[i] = strconv.FormatInt(int64(val[i]), 10)
+	}
+
+	return p.Array(pg.OIDInt4, items...)
+}
+
+func (p pgParam) Int8Array(val []int64) Builder {
+	items := make([]string, len(val))
+	for i := range val {
+		items[i] = strconv.FormatInt(val[i], 10)
+	}
+
+	return p.Array(pg.OIDInt8, items...)
+}
+
+func (p pgParam) TextArray(val []string) Builder {
+	return p.Array(pg.OIDText, val...)
+}
@@ -2,7 +2,9 @@ package params
 
 import (
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb"
 
@@ -73,7 +75,210 @@ func TestPg(t *testing.T) {
 				},
 			},
 		},
-	}
+		{
+			method: "Bool",
+			args:   []any{true},
+
+			expected: expected{
+				Type: &Ydb.Type{
+					Type: &Ydb.Type_PgType{
+						PgType: &Ydb.PgType{
+							Oid: pg.OIDBool,
+						},
+					},
+				},
+				Value: &Ydb.Value{
+					Value: &Ydb.Value_TextValue{TextValue: "true"},
+				},
+			},
+		},
+		{
+			method: "Int2",
+			args:   []any{int16(123)},
+
+			expected: expected{
+				Type: &Ydb.Type{
+					Type: &Ydb.Type_PgType{
+						PgType: &Ydb.PgType{
+							Oid: pg.OIDInt2,
+						},
+					},
+				},
+				Value: &Ydb.Value{
+					Value: &Ydb.Value_TextValue{TextValue: "123"},
+				},
+			},
+		},
+		{
+			method: "Float8",
+			args:   []any{float64(1.5)},
+
+			expected: expected{
+				Type: &Ydb.Type{
+					Type: &Ydb.Type_PgType{
+						PgType: &Ydb.PgType{
+							Oid: pg.OIDFloat8,
+						},
+					},
+				},
+				Value: &Ydb.Value{
+					Value: &Ydb.Value_TextValue{TextValue: "1.5"},
+				},
+			},
+		},
+		{
+			method: "Numeric",
+			args:   []any{"123.456"},
+
+			expected: expected{
+				Type: &Ydb.Type{
+					Type: &Ydb.Type_PgType{
+						PgType: &Ydb.PgType{
+							Oid: pg.OIDNumeric,
+						},
+					},
+				},
+				Value: &Ydb.Value{
+					Value: &Ydb.Value_TextValue{TextValue: "123.456"},
+				},
+			},
+		},
+		{
+			method: "Text",
+			args:   []any{"test"},
+
+			expected: expected{
+				Type: &Ydb.Type{
+					Type: &Ydb.Type_PgType{
+						PgType: &Ydb.PgType{
+							Oid: pg.OIDText,
+						},
+					},
+				},
+				Value: &Ydb.Value{
+					Value: &Ydb.Value_TextValue{TextValue: "test"},
+				},
+			},
+		},
+		{
+			method: "Bytea",
+			args:   []any{[]byte{0x01, 0xab}},
+
+			expected: expected{
+				Type: &Ydb.Type{
+					Type: &Ydb.Type_PgType{
+						PgType: &Ydb.PgType{
+							Oid: pg.OIDBytea,
+						},
+					},
+				},
+				Value: &Ydb.Value{
+					Value: &Ydb.Value_TextValue{TextValue: `\x01ab`},
+				},
+			},
+		},
+		{
+			method: "Date",
+			args:   []any{time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+
+			expected: expected{
+				Type: &Ydb.Type{
+					Type: &Ydb.Type_PgType{
+						PgType: &Ydb.PgType{
+							Oid: pg.OIDDate,
+						},
+					},
+				},
+				Value: &Ydb.Value{
+					Value: &Ydb.Value_TextValue{TextValue: "2024-01-02"},
+				},
+			},
+		},
+		{
+			method: "Timestamp",
+			args:   []any{time.Date(2024, 1, 2, 3, 4, 5, 123000, time.UTC)},
+
+			expected: expected{
+				Type: &Ydb.Type{
+					Type: &Ydb.Type_PgType{
+						PgType: &Ydb.PgType{
+							Oid: pg.OIDTimestamp,
+						},
+					},
+				},
+				Value: &Ydb.Value{
+					Value: &Ydb.Value_TextValue{TextValue: "2024-01-02 03:04:05.000123"},
+				},
+			},
+		},
+		{
+			method: "JSONB",
+			args:   []any{`{"a":1}`},
+
+			expected: expected{
+				Type: &Ydb.Type{
+					Type: &Ydb.Type_PgType{
+						PgType: &Ydb.PgType{
+							Oid: pg.OIDJSONB,
+						},
+					},
+				},
+				Value: &Ydb.Value{
+					Value: &Ydb.Value_TextValue{TextValue: `{"a":1}`},
+				},
+			},
+		},
+		{
+			method: "UUID",
+			args:   []any{uuid.MustParse("6e73b41c-4ede-4d08-9cfb-b7462d9e498b")},
+
+			expected: expected{
+				Type: &Ydb.Type{
+					Type: &Ydb.Type_PgType{
+						PgType: &Ydb.PgType{
+							Oid: pg.OIDUUID,
+						},
+					},
+				},
+				Value: &Ydb.Value{
+					Value: &Ydb.Value_TextValue{TextValue: "6e73b41c-4ede-4d08-9cfb-b7462d9e498b"},
+				},
+			},
+		},
+		{
+			method: "Int4Array",
+			args:   []any{[]int32{1, 2, 3}},
+
+			expected: expected{
+				Type: &Ydb.Type{
+					Type: &Ydb.Type_PgType{
+						PgType: &Ydb.PgType{
+							Oid: pg.OIDInt4Array,
+						},
+					},
+				},
+				Value: &Ydb.Value{
+					Value: &Ydb.Value_TextValue{TextValue: "{1,2,3}"},
+				},
+			},
+		},
+		{
+			method: "TextArray",
+			args:   []any{[]string{"a", "b c", ""}},
+
+			expected: expected{
+				Type: &Ydb.Type{
+					Type: &Ydb.Type_PgType{
+						PgType: &Ydb.PgType{
+							Oid: pg.OIDTextArray,
+						},
+					},
+				},
+				Value: &Ydb.Value{
+					Value: &Ydb.Value_TextValue{TextValue: `{a,"b c",""}`},
+				},
+			},
+		}}
 
 	for _, tc := range tests {
 		t.Run(tc.method, func(t *testing.T) {
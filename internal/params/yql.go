@@ -0,0 +1,153 @@
+package params
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/types"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/value"
+	"github.com/ydb-platform/ydb-go-sdk/v3/pkg/xstring"
+)
+
+const redactedText = "<redacted>"
+
+type (
+	// Redactor masks values of sensitive parameters on rendering parameters into YQL.
+	// Redactor returns replacement of value and true if value must be masked
+	Redactor func(name string, v value.Value) (_ value.Value, redacted bool)
+
+	yqlOptions struct {
+		redactors []Redactor
+	}
+
+	YQLOption func(opts *yqlOptions)
+)
+
+// WithRedactor appends redactor for masking values of sensitive parameters
+func WithRedactor(r Redactor) YQLOption {
+	return func(opts *yqlOptions) {
+		if r != nil {
+			opts.redactors = append(opts.redactors, r)
+		}
+	}
+}
+
+// RedactNames makes redactor which masks parameters with given names.
+// Masked value keeps the type of original value, so rendered script is still executable
+func RedactNames(names ...string) Redactor {
+	redacted := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		redacted[paramName(name)] = struct{}{}
+	}
+
+	return func(name string, v value.Value) (value.Value, bool) {
+		if _, has := redacted[paramName(name)]; !has {
+			return v, false
+		}
+
+		return RedactedValue(v.Type()), true
+	}
+}
+
+// RedactedValue returns placeholder value of type t
+func RedactedValue(t types.Type) value.Value {
+	switch t {
+	case types.Text:
+		return value.TextValue(redactedText)
+	case types.Bytes:
+		return value.BytesValue(xstring.ToBytes(redactedText))
+	}
+
+	if optional, ok := t.(types.Optional); ok {
+		return value.NullValue(optional.InnerType())
+	}
+
+	if value.HasZeroValue(t) {
+		return value.ZeroValue(t)
+	}
+
+	// typed YQL expression for types without zero value
+	return value.YQLExpressionValue(t, "Unwrap(Nothing(Optional<"+t.Yql()+">))")
+}
+
+func paramName(name string) string {
+	if strings.HasPrefix(name, "$") {
+		return name
+	}
+
+	return "$" + name
+}
+
+// ToYQL renders parameters into YQL script with named expressions which can be prepended
+// to the query text instead of passing parameters, for example for reproducing query in YDB CLI.
+// Type of each parameter is written into comment after expression
+func ToYQL(p Parameters, opts ...YQLOption) string {
+	var options yqlOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+
+	if p == nil {
+		return ""
+	}
+
+	buffer := xstring.Buffer()
+	defer buffer.Free()
+
+	p.Range()(func(name string, v value.Value) bool {
+		redacted := false
+		for _, redactor := range options.redactors {
+			if vv, ok := redactor(name, v); ok {
+				v, redacted = vv, true
+
+				break
+			}
+		}
+		buffer.WriteString(paramName(name))
+		buffer.WriteString(" = ")
+		buffer.WriteString(v.Yql())
+		buffer.WriteString("; -- ")
+		buffer.WriteString(v.Type().Yql())
+		if redacted {
+			buffer.WriteString(" (redacted)")
+		}
+		buffer.WriteByte('\n')
+
+		return true
+	})
+
+	return buffer.String()
+}
+
+// Inline prepends parameters rendered by ToYQL to the query text and removes
+// declarations of rendered parameters from query text
+func Inline(sql string, p Parameters, opts ...YQLOption) string {
+	yql := ToYQL(p, opts...)
+	if yql == "" {
+		return sql
+	}
+
+	names := make(map[string]struct{})
+	p.Range()(func(name string, _ value.Value) bool {
+		names[paramName(name)] = struct{}{}
+
+		return true
+	})
+
+	return yql + "\n" + removeDeclares(sql, names)
+}
+
+var declareRe = regexp.MustCompile(`(?i)DECLARE\s+(\$[^\s;]+)\s+AS\s+[^;]*;[\r\n]*`)
+
+// removeDeclares removes statements `DECLARE $name AS Type;` of the names from query text
+func removeDeclares(sql string, names map[string]struct{}) string {
+	return declareRe.ReplaceAllStringFunc(sql, func(declare string) string {
+		if _, has := names[declareRe.FindStringSubmatch(declare)[1]]; has {
+			return ""
+		}
+
+		return declare
+	})
+}
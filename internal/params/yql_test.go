@@ -0,0 +1,85 @@
+package params
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/types"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/value"
+)
+
+func TestToYQL(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		params Parameters
+		opts   []YQLOption
+		exp    string
+	}{
+		{
+			name:   "Nil",
+			params: nil,
+			exp:    "",
+		},
+		{
+			name: "Values",
+			params: Builder{}.
+				Param("$id").Uint64(1).
+				Param("name").Text("test").
+				Param("$tags").BeginList().EndList().
+				Build(),
+			exp: "$id = 1ul; -- Uint64\n" +
+				"$name = \"test\"u; -- Utf8\n" +
+				"$tags = []; -- EmptyList\n",
+		},
+		{
+			name: "TypedEmptyList",
+			params: &Params{
+				Named("$ids", value.ZeroValue(types.NewList(types.Uint64))),
+			},
+			exp: "$ids = ListCreate(Uint64); -- List<Uint64>\n",
+		},
+		{
+			name: "Redacted",
+			params: Builder{}.
+				Param("$login").Text("user").
+				Param("$password").Text("secret").
+				Param("$pin").Int32(1234).
+				Param("$token").BeginOptional().Bytes(func(v []byte) *[]byte { return &v }([]byte("token"))).EndOptional().
+				Build(),
+			opts: []YQLOption{
+				WithRedactor(RedactNames("password", "$pin", "$token")),
+			},
+			exp: "$login = \"user\"u; -- Utf8\n" +
+				"$password = \"<redacted>\"u; -- Utf8 (redacted)\n" +
+				"$pin = 0; -- Int32 (redacted)\n" +
+				"$token = Nothing(Optional<String>); -- Optional<String> (redacted)\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.exp, ToYQL(tt.params, tt.opts...))
+		})
+	}
+}
+
+func TestRedactedValue(t *testing.T) {
+	v := RedactedValue(types.NewVariantTuple(types.Int32, types.Text))
+	require.Equal(t, "Unwrap(Nothing(Optional<Variant<Int32,Utf8>>))", v.Yql())
+	require.True(t, types.Equal(types.NewVariantTuple(types.Int32, types.Text), v.Type()))
+
+	structType := types.NewStruct(types.StructField{Name: "v", T: types.NewVariantTuple(types.Int32)})
+	v = RedactedValue(structType)
+	require.Equal(t, "Unwrap(Nothing(Optional<Struct<'v':Variant<Int32>>>))", v.Yql())
+
+	var dst string
+	require.ErrorIs(t, value.CastTo(v, &dst), value.ErrCannotCast)
+
+	require.Equal(t, "0", RedactedValue(types.Int32).Yql())
+}
+
+func TestInline(t *testing.T) {
+	sql := "DECLARE $id AS Uint64;\ndeclare $id2 as Uint64;\nSELECT $id, $id2;"
+	params := Builder{}.Param("$id").Uint64(1).Build()
+	require.Equal(t, "$id = 1ul; -- Uint64\n\ndeclare $id2 as Uint64;\nSELECT $id, $id2;", Inline(sql, params))
+	require.Equal(t, sql, Inline(sql, nil))
+}
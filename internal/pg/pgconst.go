@@ -3,7 +3,80 @@ package pg
 const (
 	// https://github.com/postgres/postgres/blob/master/src/include/catalog/pg_type.dat
 
-	OIDInt4    = 23
-	OIDInt8    = 20
-	OIDUnknown = 705
+	OIDBool        = 16
+	OIDBytea       = 17
+	OIDInt8        = 20
+	OIDInt2        = 21
+	OIDInt4        = 23
+	OIDText        = 25
+	OIDJSON        = 114
+	OIDFloat4      = 700
+	OIDFloat8      = 701
+	OIDUnknown     = 705
+	OIDVarchar     = 1043
+	OIDDate        = 1082
+	OIDTimestamp   = 1114
+	OIDTimestamptz = 1184
+	OIDNumeric     = 1700
+	OIDUUID        = 2950
+	OIDJSONB       = 3802
+
+	OIDJSONArray        = 199
+	OIDBoolArray        = 1000
+	OIDByteaArray       = 1001
+	OIDInt2Array        = 1005
+	OIDInt4Array        = 1007
+	OIDTextArray        = 1009
+	OIDVarcharArray     = 1015
+	OIDInt8Array        = 1016
+	OIDFloat4Array      = 1021
+	OIDFloat8Array      = 1022
+	OIDTimestampArray   = 1115
+	OIDDateArray        = 1182
+	OIDTimestamptzArray = 1185
+	OIDNumericArray     = 1231
+	OIDUUIDArray        = 2951
+	OIDJSONBArray       = 3807
 )
+
+var arrayOIDs = map[uint32]uint32{
+	OIDBool:        OIDBoolArray,
+	OIDBytea:       OIDByteaArray,
+	OIDInt2:        OIDInt2Array,
+	OIDInt4:        OIDInt4Array,
+	OIDInt8:        OIDInt8Array,
+	OIDText:        OIDTextArray,
+	OIDVarchar:     OIDVarcharArray,
+	OIDJSON:        OIDJSONArray,
+	OIDJSONB:       OIDJSONBArray,
+	OIDFloat4:      OIDFloat4Array,
+	OIDFloat8:      OIDFloat8Array,
+	OIDDate:        OIDDateArray,
+	OIDTimestamp:   OIDTimestampArray,
+	OIDTimestamptz: OIDTimestamptzArray,
+	OIDNumeric:     OIDNumericArray,
+	OIDUUID:        OIDUUIDArray,
+}
+
+var elemOIDs = func() map[uint32]uint32 {
+	m := make(map[uint32]uint32, len(arrayOIDs))
+	for elem, array := range arrayOIDs {
+		m[array] = elem
+	}
+
+	return m
+}()
+
+// ArrayOID returns OID of array type with given element type
+func ArrayOID(elemOID uint32) (oid uint32, ok bool) {
+	oid, ok = arrayOIDs[elemOID]
+
+	return oid, ok
+}
+
+// ElemOID returns OID of element type for given array type
+func ElemOID(arrayOID uint32) (oid uint32, ok bool) {
+	oid, ok = elemOIDs[arrayOID]
+
+	return oid, ok
+}
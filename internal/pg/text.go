@@ -0,0 +1,240 @@
+package pg
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Text representations of postgres values
+// https://www.postgresql.org/docs/current/datatype.html
+
+const (
+	dateLayout      = "2006-01-02"
+	timestampLayout = "2006-01-02 15:04:05.999999"
+)
+
+var timestamptzLayouts = []string{
+	"2006-01-02 15:04:05.999999Z07:00:00",
+	"2006-01-02 15:04:05.999999Z07:00",
+	"2006-01-02 15:04:05.999999Z07",
+}
+
+var (
+	errSyntax        = errors.New("invalid text representation")
+	errMultiDimArray = errors.New("multidimensional arrays are not supported")
+)
+
+func syntaxError(typeName, s string) error {
+	return fmt.Errorf("%w of %s: %q", errSyntax, typeName, s)
+}
+
+func FormatBool(v bool) string {
+	if v {
+		return "true"
+	}
+
+	return "false"
+}
+
+func ParseBool(s string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "t", "true", "y", "yes", "on", "1":
+		return true, nil
+	case "f", "false", "n", "no", "off", "0":
+		return false, nil
+	default:
+		return false, syntaxError("bool", s)
+	}
+}
+
+// FormatBytea formats bytes in hex format
+func FormatBytea(v []byte) string {
+	return `\x` + hex.EncodeToString(v)
+}
+
+// ParseBytea parses bytes in hex or escape format
+func ParseBytea(s string) ([]byte, error) {
+	if strings.HasPrefix(s, `\x`) {
+		v, err := hex.DecodeString(s[2:])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", syntaxError("bytea", s), err)
+		}
+
+		return v, nil
+	}
+
+	v := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			v = append(v, s[i])
+
+			continue
+		}
+		switch {
+		case i+1 < len(s) && s[i+1] == '\\':
+			v = append(v, '\\')
+			i++
+		case i+3 < len(s):
+			b, err := strconv.ParseUint(s[i+1:i+4], 8, 8)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", syntaxError("bytea", s), err)
+			}
+			v = append(v, byte(b))
+			i += 3
+		default:
+			return nil, syntaxError("bytea", s)
+		}
+	}
+
+	return v, nil
+}
+
+func FormatDate(v time.Time) string {
+	return v.Format(dateLayout)
+}
+
+func ParseDate(s string) (time.Time, error) {
+	v, err := time.ParseInLocation(dateLayout, s, time.UTC)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %w", syntaxError("date", s), err)
+	}
+
+	return v, nil
+}
+
+// FormatTimestamp formats time as timestamp without time zone in UTC
+func FormatTimestamp(v time.Time) string {
+	return v.UTC().Format(timestampLayout)
+}
+
+func ParseTimestamp(s string) (time.Time, error) {
+	v, err := time.ParseInLocation(timestampLayout, s, time.UTC)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %w", syntaxError("timestamp", s), err)
+	}
+
+	return v, nil
+}
+
+func FormatTimestamptz(v time.Time) string {
+	return v.Format(timestamptzLayouts[1])
+}
+
+func ParseTimestamptz(s string) (v time.Time, err error) {
+	for _, layout := range timestamptzLayouts {
+		v, err = time.Parse(layout, s)
+		if err == nil {
+			return v, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%w: %w", syntaxError("timestamptz", s), err)
+}
+
+// FormatArray formats one-dimensional array from text representations of items.
+// Nil item is formatted as NULL
+func FormatArray(items []*string) string {
+	var buf strings.Builder
+	buf.WriteByte('{')
+	for i, item := range items {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if item == nil {
+			buf.WriteString("NULL")
+
+			continue
+		}
+		if !arrayItemNeedsQuotes(*item) {
+			buf.WriteString(*item)
+
+			continue
+		}
+		buf.WriteByte('"')
+		for j := 0; j < len(*item); j++ {
+			if c := (*item)[j]; c == '"' || c == '\\' {
+				buf.WriteByte('\\')
+			}
+			buf.WriteByte((*item)[j])
+		}
+		buf.WriteByte('"')
+	}
+	buf.WriteByte('}')
+
+	return buf.String()
+}
+
+func arrayItemNeedsQuotes(s string) bool {
+	if s == "" || strings.EqualFold(s, "NULL") {
+		return true
+	}
+
+	return strings.ContainsAny(s, "{}\",\\ \t\n\r\v\f")
+}
+
+// ParseArray parses one-dimensional array into text representations of items.
+// NULL item is parsed as nil
+func ParseArray(s string) (items []*string, err error) {
+	s = strings.TrimSpace(s)
+	if idx := strings.Index(s, "="); idx >= 0 && strings.HasPrefix(s, "[") {
+		// skip explicit dimensions like [1:3]={1,2,3}
+		s = s[idx+1:]
+	}
+	if len(s) < 2 || s[0] != '{' || s[len(s)-1] != '}' { //nolint:gomnd
+		return nil, syntaxError("array", s)
+	}
+	body := s[1 : len(s)-1]
+	if strings.TrimSpace(body) == "" {
+		return []*string{}, nil
+	}
+
+	for i := 0; i <= len(body); {
+		for i < len(body) && body[i] == ' ' {
+			i++
+		}
+		switch {
+		case i < len(body) && body[i] == '{':
+			return nil, fmt.Errorf("%w: %q", errMultiDimArray, s)
+		case i < len(body) && body[i] == '"':
+			var item strings.Builder
+			i++
+			for ; i < len(body) && body[i] != '"'; i++ {
+				if body[i] == '\\' && i+1 < len(body) {
+					i++
+				}
+				item.WriteByte(body[i])
+			}
+			if i >= len(body) {
+				return nil, syntaxError("array", s)
+			}
+			i++
+			v := item.String()
+			items = append(items, &v)
+		default:
+			end := strings.IndexByte(body[i:], ',')
+			if end < 0 {
+				end = len(body) - i
+			}
+			v := strings.TrimSpace(body[i : i+end])
+			if strings.EqualFold(v, "NULL") {
+				items = append(items, nil)
+			} else {
+				items = append(items, &v)
+			}
+			i += end
+		}
+		for i < len(body) && body[i] == ' ' {
+			i++
+		}
+		if i < len(body) && body[i] != ',' {
+			return nil, syntaxError("array", s)
+		}
+		i++
+	}
+
+	return items, nil
+}
@@ -0,0 +1,80 @@
+package pg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func ptr(s string) *string {
+	return &s
+}
+
+func TestBytea(t *testing.T) {
+	require.Equal(t, `\x00ff10`, FormatBytea([]byte{0x00, 0xff, 0x10}))
+
+	for _, tt := range []struct {
+		src string
+		exp []byte
+	}{
+		{src: `\x00ff10`, exp: []byte{0x00, 0xff, 0x10}},
+		{src: `abc\\\001`, exp: []byte{'a', 'b', 'c', '\\', 0x01}},
+		{src: ``, exp: []byte{}},
+	} {
+		t.Run(tt.src, func(t *testing.T) {
+			v, err := ParseBytea(tt.src)
+			require.NoError(t, err)
+			require.Equal(t, tt.exp, v)
+		})
+	}
+
+	_, err := ParseBytea(`\xzz`)
+	require.ErrorIs(t, err, errSyntax)
+}
+
+func TestTimes(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 123000, time.UTC)
+
+	v, err := ParseDate(FormatDate(ts))
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), v)
+
+	v, err = ParseTimestamp(FormatTimestamp(ts))
+	require.NoError(t, err)
+	require.Equal(t, ts, v)
+
+	v, err = ParseTimestamptz("2024-01-02 06:04:05.000123+03")
+	require.NoError(t, err)
+	require.True(t, ts.Equal(v))
+
+	v, err = ParseTimestamptz(FormatTimestamptz(ts.In(time.FixedZone("", 5*60*60+30*60))))
+	require.NoError(t, err)
+	require.True(t, ts.Equal(v))
+}
+
+func TestArray(t *testing.T) {
+	for _, tt := range []struct {
+		text  string
+		items []*string
+	}{
+		{text: `{}`, items: []*string{}},
+		{text: `{1,2,3}`, items: []*string{ptr("1"), ptr("2"), ptr("3")}},
+		{text: `{a,NULL,"null","b \"c\"","d\\\\e",""}`, items: []*string{
+			ptr("a"), nil, ptr("null"), ptr(`b "c"`), ptr(`d\\e`), ptr(""),
+		}},
+	} {
+		t.Run(tt.text, func(t *testing.T) {
+			require.Equal(t, tt.text, FormatArray(tt.items))
+			items, err := ParseArray(tt.text)
+			require.NoError(t, err)
+			require.Equal(t, tt.items, items)
+		})
+	}
+
+	_, err := ParseArray(`{{1,2},{3,4}}`)
+	require.ErrorIs(t, err, errMultiDimArray)
+
+	_, err = ParseArray(`1,2`)
+	require.ErrorIs(t, err, errSyntax)
+}
@@ -0,0 +1,261 @@
+package arrow
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/decimal"
+)
+
+// Bitmap is a validity or boolean bitmap with least significant bit numbering
+type Bitmap []byte
+
+// Get returns bit with index i
+func (b Bitmap) Get(i int) bool {
+	return b[i>>3]&(1<<(i&7)) != 0 //nolint:gomnd
+}
+
+// Column is a decoded column of record batch.
+//
+// Values of column are stored in the typed vector which corresponds to Type:
+//   - Int values in Int8, Int16, Int32, Int64, Uint8, Uint16, Uint32 or Uint64 vector by BitWidth
+//   - FloatingPoint values in Float32 or Float64 vector
+//   - Bool values in Bools bitmap
+//   - Date values in Int32 (days) or Int64 (milliseconds) vector by DateUnit
+//   - Time values in Int32 or Int64 vector by BitWidth
+//   - Timestamp and Duration values in Int64 vector
+//   - Binary, Utf8, LargeBinary and LargeUtf8 values in Data by Offsets
+//   - FixedSizeBinary and Decimal values in Data with fixed item size
+//   - List, LargeList and Map values in Children[0] by Offsets
+//   - FixedSizeList values in Children[0] with fixed list size
+//   - Struct values in Children
+type Column struct {
+	Field
+
+	// Len is a number of values in column
+	Len int
+	// NullCount is a number of null values in column
+	NullCount int
+	// Validity is a bitmap of non-null values. Validity is nil if column has no null values
+	Validity Bitmap
+
+	Int8    []int8
+	Int16   []int16
+	Int32   []int32
+	Int64   []int64
+	Uint8   []uint8
+	Uint16  []uint16
+	Uint32  []uint32
+	Uint64  []uint64
+	Float32 []float32
+	Float64 []float64
+	Bools   Bitmap
+
+	// Offsets are the start offsets of variable size values. Offsets has Len+1 items
+	Offsets []int64
+	// Data contains values of binary types
+	Data []byte
+
+	Children []*Column
+}
+
+// IsNull checks value with index i is null
+func (c *Column) IsNull(i int) bool {
+	if c.Type.ID == TypeNull {
+		return true
+	}
+
+	return c.Validity != nil && !c.Validity.Get(i)
+}
+
+// Bytes returns value of binary column with index i without copy
+func (c *Column) Bytes(i int) []byte {
+	switch c.Type.ID {
+	case TypeFixedSizeBinary:
+		return c.Data[i*c.Type.ByteWidth : (i+1)*c.Type.ByteWidth]
+	case TypeDecimal:
+		size := c.Type.BitWidth / 8 //nolint:gomnd
+
+		return c.Data[i*size : (i+1)*size]
+	default:
+		return c.Data[c.Offsets[i]:c.Offsets[i+1]]
+	}
+}
+
+// String returns value of binary column with index i as string
+func (c *Column) String(i int) string {
+	return string(c.Bytes(i))
+}
+
+// Time returns value of Timestamp or Date column with index i
+func (c *Column) Time(i int) time.Time {
+	switch c.Type.ID {
+	case TypeDate:
+		if c.Type.DateUnit == DateDay {
+			return time.Unix(int64(c.Int32[i])*24*60*60, 0).UTC()
+		}
+
+		return time.UnixMilli(c.Int64[i]).UTC()
+	default:
+		t := time.Unix(0, c.Int64[i]*c.Type.Unit.nanoseconds()).UTC()
+		if c.Type.Timezone != "" {
+			if loc, err := time.LoadLocation(c.Type.Timezone); err == nil {
+				t = t.In(loc)
+			}
+		}
+
+		return t
+	}
+}
+
+// Duration returns value of Duration or Time column with index i
+func (c *Column) Duration(i int) time.Duration {
+	if c.Type.ID == TypeTime && c.Type.BitWidth == 32 { //nolint:gomnd
+		return time.Duration(int64(c.Int32[i]) * c.Type.Unit.nanoseconds())
+	}
+
+	return time.Duration(c.Int64[i] * c.Type.Unit.nanoseconds())
+}
+
+// Decimal returns value of Decimal column with index i
+func (c *Column) Decimal(i int) decimal.Decimal {
+	le := c.Bytes(i)
+	d := decimal.Decimal{
+		Precision: uint32(c.Type.Precision),
+		Scale:     uint32(c.Type.Scale),
+	}
+	// Arrow decimals are little-endian two's complement integers
+	for j := range d.Bytes {
+		if j < len(le) {
+			d.Bytes[len(d.Bytes)-1-j] = le[j]
+		} else if le[len(le)-1]&0x80 != 0 {
+			d.Bytes[len(d.Bytes)-1-j] = 0xff
+		}
+	}
+
+	return d
+}
+
+// Value returns value with index i as Go value or nil if value is null
+//
+//nolint:funlen,gocyclo
+func (c *Column) Value(i int) any {
+	if c.IsNull(i) {
+		return nil
+	}
+
+	switch c.Type.ID {
+	case TypeInt:
+		switch {
+		case c.Int8 != nil:
+			return c.Int8[i]
+		case c.Int16 != nil:
+			return c.Int16[i]
+		case c.Int32 != nil:
+			return c.Int32[i]
+		case c.Int64 != nil:
+			return c.Int64[i]
+		case c.Uint8 != nil:
+			return c.Uint8[i]
+		case c.Uint16 != nil:
+			return c.Uint16[i]
+		case c.Uint32 != nil:
+			return c.Uint32[i]
+		default:
+			return c.Uint64[i]
+		}
+	case TypeFloatingPoint:
+		if c.Float32 != nil {
+			return c.Float32[i]
+		}
+
+		return c.Float64[i]
+	case TypeBool:
+		return c.Bools.Get(i)
+	case TypeUtf8, TypeLargeUtf8:
+		return c.String(i)
+	case TypeBinary, TypeLargeBinary, TypeFixedSizeBinary:
+		return c.Bytes(i)
+	case TypeDecimal:
+		return c.Decimal(i)
+	case TypeDate, TypeTimestamp:
+		return c.Time(i)
+	case TypeDuration, TypeTime:
+		return c.Duration(i)
+	case TypeList, TypeLargeList, TypeMap, TypeFixedSizeList:
+		from, to := c.listBounds(i)
+		items := make([]any, 0, to-from)
+		for j := from; j < to; j++ {
+			items = append(items, c.Children[0].Value(j))
+		}
+
+		return items
+	case TypeStruct:
+		fields := make(map[string]any, len(c.Children))
+		for _, child := range c.Children {
+			fields[child.Name] = child.Value(i)
+		}
+
+		return fields
+	default:
+		return nil
+	}
+}
+
+func (c *Column) listBounds(i int) (from, to int) {
+	if c.Type.ID == TypeFixedSizeList {
+		return i * c.Type.ByteWidth, (i + 1) * c.Type.ByteWidth
+	}
+
+	return int(c.Offsets[i]), int(c.Offsets[i+1])
+}
+
+func (u TimeUnit) nanoseconds() int64 {
+	switch u {
+	case Second:
+		return int64(time.Second)
+	case Millisecond:
+		return int64(time.Millisecond)
+	case Microsecond:
+		return int64(time.Microsecond)
+	default:
+		return 1
+	}
+}
+
+func decodeInts[T int8 | int16 | int32 | int64 | uint8 | uint16 | uint32 | uint64](buf []byte, n, size int) []T {
+	values := make([]T, n)
+	for i := range values {
+		switch size {
+		case 1:
+			values[i] = T(buf[i])
+		case 2: //nolint:gomnd
+			values[i] = T(binary.LittleEndian.Uint16(buf[i*2:]))
+		case 4: //nolint:gomnd
+			values[i] = T(binary.LittleEndian.Uint32(buf[i*4:]))
+		default:
+			values[i] = T(binary.LittleEndian.Uint64(buf[i*8:]))
+		}
+	}
+
+	return values
+}
+
+func decodeFloat32(buf []byte, n int) []float32 {
+	values := make([]float32, n)
+	for i := range values {
+		values[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[i*4:]))
+	}
+
+	return values
+}
+
+func decodeFloat64(buf []byte, n int) []float64 {
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = math.Float64frombits(binary.LittleEndian.Uint64(buf[i*8:]))
+	}
+
+	return values
+}
@@ -0,0 +1,115 @@
+package arrow
+
+import (
+	"encoding/binary"
+)
+
+// fbTable is a minimal reader of flatbuffers tables which used in Arrow IPC metadata.
+// Out of range access panics, so callers must recover panics into errors
+type fbTable struct {
+	buf []byte
+	pos int
+}
+
+func fbRoot(buf []byte) fbTable {
+	return fbTable{buf: buf, pos: int(binary.LittleEndian.Uint32(buf))}
+}
+
+// fieldPos returns absolute position of field or 0 if field is absent
+func (t fbTable) fieldPos(field int) int {
+	vtable := t.pos - int(int32(binary.LittleEndian.Uint32(t.buf[t.pos:])))
+	vtableSize := int(binary.LittleEndian.Uint16(t.buf[vtable:]))
+	entry := 4 + 2*field //nolint:gomnd
+	if entry >= vtableSize {
+		return 0
+	}
+	offset := int(binary.LittleEndian.Uint16(t.buf[vtable+entry:]))
+	if offset == 0 {
+		return 0
+	}
+
+	return t.pos + offset
+}
+
+func (t fbTable) has(field int) bool {
+	return t.fieldPos(field) != 0
+}
+
+func (t fbTable) uint8(field int, def uint8) uint8 {
+	if pos := t.fieldPos(field); pos != 0 {
+		return t.buf[pos]
+	}
+
+	return def
+}
+
+func (t fbTable) bool(field int, def bool) bool {
+	if pos := t.fieldPos(field); pos != 0 {
+		return t.buf[pos] != 0
+	}
+
+	return def
+}
+
+func (t fbTable) int16(field int, def int16) int16 {
+	if pos := t.fieldPos(field); pos != 0 {
+		return int16(binary.LittleEndian.Uint16(t.buf[pos:]))
+	}
+
+	return def
+}
+
+func (t fbTable) int32(field int, def int32) int32 {
+	if pos := t.fieldPos(field); pos != 0 {
+		return int32(binary.LittleEndian.Uint32(t.buf[pos:]))
+	}
+
+	return def
+}
+
+func (t fbTable) int64(field int, def int64) int64 {
+	if pos := t.fieldPos(field); pos != 0 {
+		return int64(binary.LittleEndian.Uint64(t.buf[pos:]))
+	}
+
+	return def
+}
+
+func (t fbTable) indirect(pos int) int {
+	return pos + int(binary.LittleEndian.Uint32(t.buf[pos:]))
+}
+
+func (t fbTable) table(field int) (fbTable, bool) {
+	pos := t.fieldPos(field)
+	if pos == 0 {
+		return fbTable{}, false
+	}
+
+	return fbTable{buf: t.buf, pos: t.indirect(pos)}, true
+}
+
+func (t fbTable) string(field int) string {
+	pos := t.fieldPos(field)
+	if pos == 0 {
+		return ""
+	}
+	pos = t.indirect(pos)
+	size := int(binary.LittleEndian.Uint32(t.buf[pos:]))
+
+	return string(t.buf[pos+4 : pos+4+size])
+}
+
+// vector returns position of the first element and length of vector
+func (t fbTable) vector(field int) (start, n int) {
+	pos := t.fieldPos(field)
+	if pos == 0 {
+		return 0, 0
+	}
+	pos = t.indirect(pos)
+
+	return pos + 4, int(binary.LittleEndian.Uint32(t.buf[pos:])) //nolint:gomnd
+}
+
+func (t fbTable) vectorTable(start, i int) fbTable {
+	return fbTable{buf: t.buf, pos: t.indirect(start + 4*i)}
+}
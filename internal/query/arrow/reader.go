@@ -0,0 +1,529 @@
+package arrow
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+)
+
+var (
+	errUnsupported  = errors.New("unsupported arrow data")
+	errMalformed    = errors.New("malformed arrow data")
+	errSchemaNeeded = errors.New("arrow schema message expected")
+)
+
+const (
+	continuationMarker = 0xFFFFFFFF
+
+	headerSchema          = 1
+	headerDictionaryBatch = 2
+	headerRecordBatch     = 3
+
+	fieldNodeSize = 16
+	bufferSize    = 16
+
+	// maxMetadataSize and maxBodySize limit sizes of message parts, sizes are read from the stream
+	maxMetadataSize = 16 << 20
+	maxBodySize     = 1 << 30
+
+	// readChunkSize is max size of buffer, allocated before read of data
+	readChunkSize = 1 << 20
+)
+
+// RecordBatch is a decoded record batch
+type RecordBatch struct {
+	Schema  *Schema
+	NumRows int
+	Columns []*Column
+}
+
+// Column returns column by name or nil if column not found
+func (b *RecordBatch) Column(name string) *Column {
+	for _, c := range b.Columns {
+		if c.Name == name {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// Reader is a lightweight decoder of Arrow IPC stream into record batches
+type Reader struct {
+	r      io.Reader
+	schema *Schema
+}
+
+// NewReader makes Reader and reads schema from Arrow IPC stream (for example from [Part])
+func NewReader(r io.Reader) (*Reader, error) {
+	reader := &Reader{r: r}
+
+	header, meta, _, err := reader.readMessage()
+	if err != nil {
+		if xerrors.Is(err, io.EOF) {
+			return nil, xerrors.WithStackTrace(errSchemaNeeded)
+		}
+
+		return nil, xerrors.WithStackTrace(err)
+	}
+	if header != headerSchema {
+		return nil, xerrors.WithStackTrace(errSchemaNeeded)
+	}
+
+	if err = safely(func() (err error) {
+		reader.schema, err = parseSchema(meta)
+
+		return err
+	}); err != nil {
+		return nil, xerrors.WithStackTrace(err)
+	}
+
+	return reader, nil
+}
+
+// Schema returns schema of stream
+func (r *Reader) Schema() *Schema {
+	return r.schema
+}
+
+// Next reads next record batch. Next returns io.EOF at the end of stream
+func (r *Reader) Next() (*RecordBatch, error) {
+	for {
+		header, meta, body, err := r.readMessage()
+		if err != nil {
+			return nil, xerrors.WithStackTrace(err)
+		}
+
+		switch header {
+		case headerRecordBatch:
+			var batch *RecordBatch
+			if err = safely(func() (err error) {
+				batch, err = r.decodeRecordBatch(meta, body)
+
+				return err
+			}); err != nil {
+				return nil, xerrors.WithStackTrace(err)
+			}
+
+			return batch, nil
+		case headerDictionaryBatch:
+			return nil, xerrors.WithStackTrace(fmt.Errorf("%w: dictionary batch", errUnsupported))
+		default:
+			// skip other messages
+		}
+	}
+}
+
+// ReadAll reads all record batches from Arrow IPC stream
+func ReadAll(r io.Reader) (batches []*RecordBatch, _ error) {
+	reader, err := NewReader(r)
+	if err != nil {
+		return nil, xerrors.WithStackTrace(err)
+	}
+
+	for {
+		batch, err := reader.Next()
+		if err != nil {
+			if xerrors.Is(err, io.EOF) {
+				return batches, nil
+			}
+
+			return nil, xerrors.WithStackTrace(err)
+		}
+		batches = append(batches, batch)
+	}
+}
+
+func safely(f func() error) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("%w: %v", errMalformed, e)
+		}
+	}()
+
+	return f()
+}
+
+func (r *Reader) readMessage() (header uint8, meta fbTable, body []byte, err error) {
+	var prefix [4]byte
+	if _, err = io.ReadFull(r.r, prefix[:]); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return 0, meta, nil, fmt.Errorf("%w: %w", errMalformed, err)
+		}
+
+		return 0, meta, nil, err
+	}
+
+	size := binary.LittleEndian.Uint32(prefix[:])
+	if size == continuationMarker {
+		if _, err = io.ReadFull(r.r, prefix[:]); err != nil {
+			return 0, meta, nil, fmt.Errorf("%w: %w", errMalformed, err)
+		}
+		size = binary.LittleEndian.Uint32(prefix[:])
+	}
+	if size == 0 {
+		// end of stream
+		return 0, meta, nil, io.EOF
+	}
+
+	if size > maxMetadataSize {
+		return 0, meta, nil, fmt.Errorf("%w: metadata size %d exceeds limit %d", errMalformed, size, maxMetadataSize)
+	}
+
+	buf, err := readBytes(r.r, int64(size))
+	if err != nil {
+		return 0, meta, nil, err
+	}
+
+	var bodyLength int64
+	if err = safely(func() error {
+		message := fbRoot(buf)
+		header = message.uint8(1, 0)
+		meta, _ = message.table(2) //nolint:gomnd
+		bodyLength = message.int64(3, 0)
+
+		return nil
+	}); err != nil {
+		return 0, meta, nil, err
+	}
+	if bodyLength < 0 {
+		return 0, meta, nil, fmt.Errorf("%w: negative body length", errMalformed)
+	}
+	if bodyLength > maxBodySize {
+		return 0, meta, nil, fmt.Errorf("%w: body length %d exceeds limit %d", errMalformed, bodyLength, maxBodySize)
+	}
+
+	body, err = readBytes(r.r, bodyLength)
+	if err != nil {
+		return 0, meta, nil, err
+	}
+
+	return header, meta, body, nil
+}
+
+// readBytes reads n bytes. Buffer grows with read data, so broken size doesn't allocate memory before read
+func readBytes(r io.Reader, n int64) ([]byte, error) {
+	buf := make([]byte, 0, min(n, readChunkSize))
+	for int64(len(buf)) < n {
+		chunk := min(n-int64(len(buf)), readChunkSize)
+		buf = append(buf, make([]byte, chunk)...)
+		if _, err := io.ReadFull(r, buf[int64(len(buf))-chunk:]); err != nil {
+			return nil, fmt.Errorf("%w: %w", errMalformed, err)
+		}
+	}
+
+	return buf, nil
+}
+
+type recordBatchDecoder struct {
+	meta    fbTable
+	body    []byte
+	nodes   int
+	nNodes  int
+	buffers int
+	nBufs   int
+	node    int
+	buffer  int
+}
+
+func (r *Reader) decodeRecordBatch(meta fbTable, body []byte) (*RecordBatch, error) {
+	if meta.has(3) { //nolint:gomnd
+		return nil, fmt.Errorf("%w: compressed record batch", errUnsupported)
+	}
+
+	d := &recordBatchDecoder{meta: meta, body: body}
+	d.nodes, d.nNodes = meta.vector(1)
+	d.buffers, d.nBufs = meta.vector(2) //nolint:gomnd
+
+	batch := &RecordBatch{
+		Schema:  r.schema,
+		NumRows: int(meta.int64(0, 0)),
+		Columns: make([]*Column, len(r.schema.Fields)),
+	}
+	if batch.NumRows < 0 {
+		return nil, fmt.Errorf("%w: negative rows count %d", errMalformed, batch.NumRows)
+	}
+	for i := range r.schema.Fields {
+		// every column has value for every row, so values of rows can be got without checks of columns length
+		c, err := d.decodeColumn(r.schema.Fields[i], batch.NumRows, batch.NumRows)
+		if err != nil {
+			return nil, err
+		}
+		batch.Columns[i] = c
+	}
+
+	return batch, nil
+}
+
+func (d *recordBatchDecoder) nextNode() (length, nullCount int, err error) {
+	if d.node >= d.nNodes {
+		return 0, 0, fmt.Errorf("%w: not enough field nodes", errMalformed)
+	}
+	pos := d.nodes + d.node*fieldNodeSize
+	d.node++
+
+	return int(binary.LittleEndian.Uint64(d.meta.buf[pos:])),
+		int(binary.LittleEndian.Uint64(d.meta.buf[pos+8:])), nil
+}
+
+func (d *recordBatchDecoder) nextBuffer() ([]byte, error) {
+	if d.buffer >= d.nBufs {
+		return nil, fmt.Errorf("%w: not enough buffers", errMalformed)
+	}
+	pos := d.buffers + d.buffer*bufferSize
+	d.buffer++
+
+	offset := binary.LittleEndian.Uint64(d.meta.buf[pos:])
+	length := binary.LittleEndian.Uint64(d.meta.buf[pos+8:])
+	if offset+length > uint64(len(d.body)) {
+		return nil, fmt.Errorf("%w: buffer out of body", errMalformed)
+	}
+
+	return d.body[offset : offset+length], nil
+}
+
+//nolint:funlen,gocyclo
+func (d *recordBatchDecoder) decodeColumn(f Field, minLen, maxLen int) (_ *Column, err error) {
+	c := &Column{Field: f}
+	if c.Len, c.NullCount, err = d.nextNode(); err != nil {
+		return nil, err
+	}
+	if c.Len < minLen || c.Len > maxLen {
+		return nil, fmt.Errorf("%w: column %q length %d, expected from %d to %d",
+			errMalformed, f.Name, c.Len, minLen, maxLen)
+	}
+	if c.NullCount < 0 || c.NullCount > c.Len {
+		return nil, fmt.Errorf("%w: column %q null count %d", errMalformed, f.Name, c.NullCount)
+	}
+
+	if f.Type.ID == TypeNull {
+		c.NullCount = c.Len
+
+		return c, nil
+	}
+
+	validity, err := d.nextBuffer()
+	if err != nil {
+		return nil, err
+	}
+	if c.NullCount > 0 {
+		if err = checkBufferSize(c, "validity", validity, bitmapSize(c.Len)); err != nil {
+			return nil, err
+		}
+		c.Validity = validity
+	}
+
+	switch f.Type.ID {
+	case TypeInt, TypeDate, TypeTime, TypeTimestamp, TypeDuration:
+		values, err := d.nextBuffer()
+		if err != nil {
+			return nil, err
+		}
+		if err = decodeFixedWidth(c, values); err != nil {
+			return nil, err
+		}
+	case TypeFloatingPoint:
+		values, err := d.nextBuffer()
+		if err != nil {
+			return nil, err
+		}
+		switch f.Type.BitWidth {
+		case 32: //nolint:gomnd
+			if err = checkValuesSize(c, values, 4); err != nil { //nolint:gomnd
+				return nil, err
+			}
+			c.Float32 = decodeFloat32(values, c.Len)
+		case 64: //nolint:gomnd
+			if err = checkValuesSize(c, values, 8); err != nil { //nolint:gomnd
+				return nil, err
+			}
+			c.Float64 = decodeFloat64(values, c.Len)
+		default:
+			return nil, fmt.Errorf("%w: %s", errUnsupported, f.Type)
+		}
+	case TypeBool:
+		if c.Bools, err = d.nextBuffer(); err != nil {
+			return nil, err
+		}
+		if err = checkBufferSize(c, "values", c.Bools, bitmapSize(c.Len)); err != nil {
+			return nil, err
+		}
+	case TypeFixedSizeBinary, TypeDecimal:
+		if c.Data, err = d.nextBuffer(); err != nil {
+			return nil, err
+		}
+		size := f.Type.ByteWidth
+		if f.Type.ID == TypeDecimal {
+			size = f.Type.BitWidth / 8 //nolint:gomnd
+			if size <= 0 {
+				return nil, fmt.Errorf("%w: %s", errUnsupported, f.Type)
+			}
+		}
+		if err = checkValuesSize(c, c.Data, size); err != nil {
+			return nil, err
+		}
+	case TypeBinary, TypeUtf8, TypeLargeBinary, TypeLargeUtf8:
+		if err = d.decodeOffsets(c, f.Type.ID == TypeLargeBinary || f.Type.ID == TypeLargeUtf8); err != nil {
+			return nil, err
+		}
+		if c.Data, err = d.nextBuffer(); err != nil {
+			return nil, err
+		}
+		if last := c.Offsets[c.Len]; last > int64(len(c.Data)) {
+			return nil, fmt.Errorf("%w: column %q offset %d out of data size %d",
+				errMalformed, f.Name, last, len(c.Data))
+		}
+	case TypeList, TypeLargeList, TypeMap:
+		if err = d.decodeOffsets(c, f.Type.ID == TypeLargeList); err != nil {
+			return nil, err
+		}
+		if err = d.decodeChildren(c, int(c.Offsets[c.Len]), math.MaxInt); err != nil {
+			return nil, err
+		}
+	case TypeFixedSizeList:
+		if f.Type.ByteWidth < 0 || (f.Type.ByteWidth > 0 && c.Len > math.MaxInt/f.Type.ByteWidth) {
+			return nil, fmt.Errorf("%w: %s", errMalformed, f.Type)
+		}
+		if err = d.decodeChildren(c, c.Len*f.Type.ByteWidth, math.MaxInt); err != nil {
+			return nil, err
+		}
+	case TypeStruct:
+		if err = d.decodeChildren(c, c.Len, c.Len); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnsupported, f.Type)
+	}
+
+	return c, nil
+}
+
+func (d *recordBatchDecoder) decodeOffsets(c *Column, large bool) error {
+	buf, err := d.nextBuffer()
+	if err != nil {
+		return err
+	}
+	if c.Len == 0 && len(buf) == 0 {
+		c.Offsets = []int64{0}
+
+		return nil
+	}
+	size := 4
+	if large {
+		size = 8
+	}
+	if c.Len+1 > len(buf)/size {
+		return fmt.Errorf("%w: column %q offsets buffer size %d is less than %d",
+			errMalformed, c.Name, len(buf), (c.Len+1)*size)
+	}
+	if large {
+		c.Offsets = decodeInts[int64](buf, c.Len+1, 8) //nolint:gomnd
+	} else {
+		c.Offsets = decodeInts[int64](buf, c.Len+1, 4) //nolint:gomnd
+		// 32-bit offsets are signed
+		for i := range c.Offsets {
+			c.Offsets[i] = int64(int32(c.Offsets[i]))
+		}
+	}
+	if c.Offsets[0] < 0 {
+		return fmt.Errorf("%w: column %q negative offset", errMalformed, c.Name)
+	}
+	for i := 1; i < len(c.Offsets); i++ {
+		if c.Offsets[i] < c.Offsets[i-1] {
+			return fmt.Errorf("%w: column %q offsets are not monotonic", errMalformed, c.Name)
+		}
+	}
+
+	return nil
+}
+
+func (d *recordBatchDecoder) decodeChildren(c *Column, minLen, maxLen int) error {
+	c.Children = make([]*Column, len(c.Field.Children))
+	for i := range c.Field.Children {
+		child, err := d.decodeColumn(c.Field.Children[i], minLen, maxLen)
+		if err != nil {
+			return err
+		}
+		c.Children[i] = child
+	}
+
+	return nil
+}
+
+func bitmapSize(n int) int {
+	return (n + 7) / 8 //nolint:gomnd
+}
+
+// checkBufferSize checks buffer has enough bytes for values of column before decode of the values
+func checkBufferSize(c *Column, name string, buf []byte, size int) error {
+	if len(buf) < size {
+		return fmt.Errorf("%w: column %q %s buffer size %d is less than %d",
+			errMalformed, c.Name, name, len(buf), size)
+	}
+
+	return nil
+}
+
+// checkValuesSize checks buffer has itemSize bytes for every value of column, size of buffer is limited
+// by size of message body, so the check prevents allocations by broken length of column
+func checkValuesSize(c *Column, buf []byte, itemSize int) error {
+	if itemSize < 0 {
+		return fmt.Errorf("%w: column %q item size %d", errMalformed, c.Name, itemSize)
+	}
+	if itemSize > 0 && c.Len > len(buf)/itemSize {
+		return fmt.Errorf("%w: column %q values buffer size %d is less than %d",
+			errMalformed, c.Name, len(buf), c.Len*itemSize)
+	}
+
+	return nil
+}
+
+func decodeFixedWidth(c *Column, buf []byte) error {
+	bitWidth := c.Type.BitWidth
+	signed := c.Type.Signed
+	switch c.Type.ID {
+	case TypeDate:
+		bitWidth, signed = 64, true
+		if c.Type.DateUnit == DateDay {
+			bitWidth = 32
+		}
+	case TypeTime:
+		signed = true
+	case TypeTimestamp, TypeDuration:
+		bitWidth, signed = 64, true
+	}
+
+	switch bitWidth {
+	case 8, 16, 32, 64: //nolint:gomnd
+	default:
+		return fmt.Errorf("%w: %s", errUnsupported, c.Type)
+	}
+	if err := checkValuesSize(c, buf, bitWidth/8); err != nil { //nolint:gomnd
+		return err
+	}
+
+	switch {
+	case signed && bitWidth == 8:
+		c.Int8 = decodeInts[int8](buf, c.Len, 1)
+	case signed && bitWidth == 16:
+		c.Int16 = decodeInts[int16](buf, c.Len, 2)
+	case signed && bitWidth == 32:
+		c.Int32 = decodeInts[int32](buf, c.Len, 4)
+	case signed:
+		c.Int64 = decodeInts[int64](buf, c.Len, 8)
+	case bitWidth == 8:
+		c.Uint8 = decodeInts[uint8](buf, c.Len, 1)
+	case bitWidth == 16:
+		c.Uint16 = decodeInts[uint16](buf, c.Len, 2)
+	case bitWidth == 32:
+		c.Uint32 = decodeInts[uint32](buf, c.Len, 4)
+	default:
+		c.Uint64 = decodeInts[uint64](buf, c.Len, 8)
+	}
+
+	return nil
+}
@@ -0,0 +1,310 @@
+package arrow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+//go:generate sh -c "cd testdata/gen && go run . ../all_types.arrow"
+
+func readTestdata(t *testing.T) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile("testdata/all_types.arrow")
+	require.NoError(t, err)
+
+	return data
+}
+
+func TestReaderSchema(t *testing.T) {
+	r, err := NewReader(bytes.NewReader(readTestdata(t)))
+	require.NoError(t, err)
+
+	types := make(map[string]string)
+	for _, f := range r.Schema().Fields {
+		types[f.Name] = f.Type.String()
+	}
+	require.Equal(t, map[string]string{
+		"id":   "Int64",
+		"u8":   "Uint8",
+		"f":    "Float64",
+		"name": "Utf8",
+		"data": "Binary",
+		"flag": "Bool",
+		"ts":   "Timestamp",
+		"d":    "Date",
+		"dur":  "Duration",
+		"dec":  "Decimal(22,9)",
+		"tags": "List",
+		"st":   "Struct",
+	}, types)
+	require.True(t, r.Schema().Fields[2].Nullable)
+	require.Len(t, r.Schema().Fields[11].Children, 2)
+}
+
+func TestReaderColumns(t *testing.T) {
+	batches, err := ReadAll(bytes.NewReader(readTestdata(t)))
+	require.NoError(t, err)
+	require.Len(t, batches, 2)
+
+	b := batches[1]
+	require.Equal(t, 3, b.NumRows)
+	require.Equal(t, []int64{3, 4, 5}, b.Column("id").Int64)
+	require.Equal(t, []uint8{203, 204, 205}, b.Column("u8").Uint8)
+
+	f := b.Column("f")
+	require.Equal(t, 2, f.NullCount)
+	require.True(t, f.IsNull(0))
+	require.False(t, f.IsNull(1))
+	require.True(t, f.IsNull(2))
+	require.Equal(t, 4.5, f.Float64[1])
+
+	name := b.Column("name")
+	require.Equal(t, []int64{0, 0, 5, 5}, name.Offsets)
+	require.Equal(t, "name4", name.String(1))
+	require.Nil(t, name.Value(0))
+
+	require.Equal(t, []byte{5, 0xff}, b.Column("data").Bytes(2))
+	require.Nil(t, b.Column("id").Validity)
+
+	base := time.Date(2024, 5, 6, 7, 8, 9, 123456000, time.UTC)
+	require.Equal(t, []any{true, false, false}, []any{
+		b.Column("flag").Value(0), b.Column("flag").Value(1), b.Column("flag").Value(2),
+	})
+	require.Equal(t, base.Add(4*time.Hour), b.Column("ts").Value(1))
+	require.Equal(t, time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC), b.Column("d").Value(1))
+	require.Equal(t, 5*time.Second, b.Column("dur").Value(2))
+	dec := b.Column("dec").Decimal(1)
+	require.Equal(t, "2.500000000", dec.String())
+	dec = batches[0].Column("dec").Decimal(0)
+	require.Equal(t, "-1.500000000", dec.String())
+	require.Equal(t, []any{"t0", "t1"}, b.Column("tags").Value(2))
+	require.Equal(t, []any{}, b.Column("tags").Value(0))
+	require.Equal(t, map[string]any{"a": int32(-4), "b": "b"}, b.Column("st").Value(1))
+	require.Equal(t, map[string]any{"a": int32(0), "b": nil}, batches[0].Column("st").Value(0))
+}
+
+func TestReaderNext(t *testing.T) {
+	r, err := NewReader(bytes.NewReader(readTestdata(t)))
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = r.Next()
+		require.NoError(t, err)
+	}
+	_, err = r.Next()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestReaderErrors(t *testing.T) {
+	data := readTestdata(t)
+
+	t.Run("Empty", func(t *testing.T) {
+		_, err := NewReader(bytes.NewReader(nil))
+		require.ErrorIs(t, err, errSchemaNeeded)
+	})
+	t.Run("Truncated", func(t *testing.T) {
+		for _, size := range []int{3, 50, len(data) - 100} {
+			_, err := ReadAll(bytes.NewReader(data[:size]))
+			require.ErrorIs(t, err, errMalformed, size)
+		}
+	})
+	t.Run("MetadataSizeLimit", func(t *testing.T) {
+		_, err := NewReader(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff, 0xfe, 0xff, 0xff, 0x7f}))
+		require.ErrorIs(t, err, errMalformed)
+	})
+	t.Run("Garbage", func(t *testing.T) {
+		garbage := append([]byte{}, data...)
+		for i := 12; i < 200; i++ {
+			garbage[i] = 0xff
+		}
+		_, err := ReadAll(bytes.NewReader(garbage))
+		require.Error(t, err)
+	})
+}
+
+// testRecordBatchLayout allows to corrupt metadata and body of the first record batch of testdata.
+// Columns of testdata has field nodes:
+// id, u8, f, name, data, flag, ts, d, dur, dec, tags, tags item, st, st.a, st.b
+// and buffers (validity and values or offsets and data):
+// id 0-1, u8 2-3, f 4-5, name 6-8, data 9-11, flag 12-13, ts 14-15, d 16-17, dur 18-19, dec 20-21,
+// tags 22-23, tags item 24-26, st 27, st.a 28-29, st.b 30-32
+type testRecordBatchLayout struct {
+	data    []byte
+	numRows int
+	nodes   int
+	buffers int
+	body    int
+}
+
+func newTestRecordBatchLayout(t *testing.T) *testRecordBatchLayout {
+	t.Helper()
+
+	data := readTestdata(t)
+	pos := 0
+	for {
+		size := int(binary.LittleEndian.Uint32(data[pos:]))
+		pos += 4
+		if size == continuationMarker {
+			size = int(binary.LittleEndian.Uint32(data[pos:]))
+			pos += 4
+		}
+		require.NotZero(t, size, "record batch not found")
+
+		message := fbRoot(data[pos : pos+size])
+		bodyLength := int(message.int64(3, 0))
+		if message.uint8(1, 0) == headerRecordBatch {
+			meta, _ := message.table(2)
+			nodes, _ := meta.vector(1)
+			buffers, _ := meta.vector(2)
+
+			return &testRecordBatchLayout{
+				data:    data,
+				numRows: pos + meta.fieldPos(0),
+				nodes:   pos + nodes,
+				buffers: pos + buffers,
+				body:    pos + size,
+			}
+		}
+		pos += size + bodyLength
+	}
+}
+
+func (l *testRecordBatchLayout) setNumRows(n uint64) {
+	binary.LittleEndian.PutUint64(l.data[l.numRows:], n)
+}
+
+func (l *testRecordBatchLayout) setNode(i int, length, nullCount uint64) {
+	binary.LittleEndian.PutUint64(l.data[l.nodes+i*fieldNodeSize:], length)
+	binary.LittleEndian.PutUint64(l.data[l.nodes+i*fieldNodeSize+8:], nullCount)
+}
+
+func (l *testRecordBatchLayout) setBufferLength(i int, length uint64) {
+	binary.LittleEndian.PutUint64(l.data[l.buffers+i*bufferSize+8:], length)
+}
+
+func (l *testRecordBatchLayout) setOffset(buffer, i int, offset int32) {
+	start := int(binary.LittleEndian.Uint64(l.data[l.buffers+buffer*bufferSize:]))
+	binary.LittleEndian.PutUint32(l.data[l.body+start+i*4:], uint32(offset))
+}
+
+func TestReaderMalformedRecordBatch(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		corrupt func(l *testRecordBatchLayout)
+	}{
+		{name: "RowsCountMismatch", corrupt: func(l *testRecordBatchLayout) { l.setNumRows(4) }},
+		{name: "NegativeRowsCount", corrupt: func(l *testRecordBatchLayout) { l.setNumRows(1 << 63) }},
+		{name: "HugeLength", corrupt: func(l *testRecordBatchLayout) {
+			l.setNumRows(1 << 33)
+			l.setNode(0, 1<<33, 0)
+		}},
+		{name: "NullCountMoreThanLength", corrupt: func(l *testRecordBatchLayout) { l.setNode(2, 3, 4) }},
+		{name: "ShortValidity", corrupt: func(l *testRecordBatchLayout) { l.setBufferLength(4, 0) }},
+		{name: "ShortIntValues", corrupt: func(l *testRecordBatchLayout) { l.setBufferLength(1, 16) }},
+		{name: "ShortUintValues", corrupt: func(l *testRecordBatchLayout) { l.setBufferLength(3, 2) }},
+		{name: "ShortFloatValues", corrupt: func(l *testRecordBatchLayout) { l.setBufferLength(5, 16) }},
+		{name: "ShortDateValues", corrupt: func(l *testRecordBatchLayout) { l.setBufferLength(17, 8) }},
+		{name: "ShortBools", corrupt: func(l *testRecordBatchLayout) { l.setBufferLength(13, 0) }},
+		{name: "ShortDecimalValues", corrupt: func(l *testRecordBatchLayout) { l.setBufferLength(21, 40) }},
+		{name: "ShortOffsets", corrupt: func(l *testRecordBatchLayout) { l.setBufferLength(7, 12) }},
+		{name: "NegativeOffset", corrupt: func(l *testRecordBatchLayout) { l.setOffset(7, 0, -1) }},
+		{name: "NotMonotonicOffsets", corrupt: func(l *testRecordBatchLayout) { l.setOffset(7, 1, 6) }},
+		{name: "OffsetOutOfData", corrupt: func(l *testRecordBatchLayout) { l.setOffset(7, 3, 11) }},
+		{name: "ShortListItems", corrupt: func(l *testRecordBatchLayout) { l.setNode(11, 2, 0) }},
+		{name: "StructFieldLengthMismatch", corrupt: func(l *testRecordBatchLayout) { l.setNode(13, 2, 0) }},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newTestRecordBatchLayout(t)
+			tt.corrupt(l)
+
+			_, err := ReadAll(bytes.NewReader(l.data))
+			require.ErrorIs(t, err, errMalformed)
+		})
+	}
+	t.Run("NotCorrupted", func(t *testing.T) {
+		_, err := ReadAll(bytes.NewReader(newTestRecordBatchLayout(t).data))
+		require.NoError(t, err)
+	})
+}
+
+func TestRecordBatchScanStruct(t *testing.T) {
+	batches, err := ReadAll(bytes.NewReader(readTestdata(t)))
+	require.NoError(t, err)
+
+	var row struct {
+		ID    int       `sql:"id"`
+		F     *float64  `sql:"f"`
+		Name  string    `sql:"name"`
+		Data  string    `sql:"data"`
+		Flag  bool      `sql:"flag"`
+		TS    time.Time `sql:"ts"`
+		Tags  []string  `sql:"tags"`
+		Skip  int       `sql:"-"`
+		other int
+	}
+
+	require.NoError(t, batches[0].ScanStruct(2, &row))
+	require.Equal(t, 2, row.ID)
+	require.NotNil(t, row.F)
+	require.Equal(t, 2.5, *row.F)
+	require.Equal(t, "name2", row.Name)
+	require.Equal(t, "\x02\xff", row.Data)
+	require.Equal(t, []string{"t0", "t1"}, row.Tags)
+
+	require.NoError(t, batches[0].ScanStruct(1, &row))
+	require.Nil(t, row.F)
+	require.Equal(t, "", row.Name)
+
+	require.ErrorIs(t, batches[0].ScanStruct(3, &row), errRowOutOfRange)
+	require.ErrorIs(t, batches[0].ScanStruct(0, row), errDstTypeIsNotAPointerToStruct)
+
+	var missing struct {
+		Unknown int
+	}
+	require.ErrorIs(t, batches[0].ScanStruct(0, &missing), errColumnsNotFound)
+
+	var incompatible struct {
+		Flag time.Time `sql:"flag"`
+	}
+	require.ErrorIs(t, batches[0].ScanStruct(0, &incompatible), errCannotScan)
+}
+
+func TestAssign(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		src  any
+		dst  any
+		exp  any
+	}{
+		{name: "int64 to int8", src: int64(-128), dst: new(int8), exp: int8(-128)},
+		{name: "int64 overflows int8", src: int64(128), dst: new(int8)},
+		{name: "negative to uint", src: int64(-1), dst: new(uint64)},
+		{name: "uint64 overflows int64", src: uint64(1 << 63), dst: new(int64)},
+		{name: "uint8 to int", src: uint8(200), dst: new(int), exp: 200},
+		{name: "integral float to int", src: 2.0, dst: new(int32), exp: int32(2)},
+		{name: "fractional float to int", src: 2.5, dst: new(int32)},
+		{name: "float64 overflows float32", src: 1e300, dst: new(float32)},
+		{name: "bytes to string", src: []byte("abc"), dst: new(string), exp: "abc"},
+		{name: "list to string", src: []any{"a"}, dst: new(string)},
+		{name: "list with overflow", src: []any{int64(1), int64(1000)}, dst: new([]int8)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := assign(reflect.ValueOf(tt.dst).Elem(), tt.src)
+			if tt.exp == nil {
+				require.ErrorIs(t, err, errCannotScan)
+
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.exp, reflect.ValueOf(tt.dst).Elem().Interface())
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package arrow
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+)
+
+var (
+	errDstTypeIsNotAPointerToStruct = errors.New("dst type is not a pointer to struct")
+	errColumnsNotFound              = errors.New("some columns not found in record batch")
+	errRowOutOfRange                = errors.New("row index out of range")
+	errCannotScan                   = errors.New("cannot scan value")
+)
+
+// Row returns values of row with index i by column names
+func (b *RecordBatch) Row(i int) map[string]any {
+	row := make(map[string]any, len(b.Columns))
+	for _, c := range b.Columns {
+		row[c.Name] = c.Value(i)
+	}
+
+	return row
+}
+
+// ScanStruct scans row with index i into struct pointed by dst.
+// Struct fields are matched to columns by `sql` tag or by field name. Fields with tag `sql:"-"` are skipped.
+// Null values are scanned into pointer fields as nil and into other fields as zero values
+func (b *RecordBatch) ScanStruct(i int, dst any) error {
+	if i < 0 || i >= b.NumRows {
+		return xerrors.WithStackTrace(fmt.Errorf("%w: %d of %d", errRowOutOfRange, i, b.NumRows))
+	}
+
+	ptr := reflect.ValueOf(dst)
+	if ptr.Kind() != reflect.Pointer || ptr.Elem().Kind() != reflect.Struct {
+		return xerrors.WithStackTrace(fmt.Errorf("%w: %T", errDstTypeIsNotAPointerToStruct, dst))
+	}
+
+	var (
+		v              = ptr.Elem()
+		tt             = v.Type()
+		missingColumns []string
+	)
+	for j := 0; j < tt.NumField(); j++ {
+		f := tt.Field(j)
+		if !f.IsExported() {
+			continue
+		}
+		name := f.Name
+		if tag, has := f.Tag.Lookup("sql"); has {
+			name = tag
+		}
+		if name == "-" {
+			continue
+		}
+
+		c := b.Column(name)
+		if c == nil {
+			missingColumns = append(missingColumns, name)
+
+			continue
+		}
+		if err := assign(v.Field(j), c.Value(i)); err != nil {
+			return xerrors.WithStackTrace(fmt.Errorf("scan error on struct field name '%s': %w", name, err))
+		}
+	}
+
+	if len(missingColumns) > 0 {
+		return xerrors.WithStackTrace(
+			fmt.Errorf("%w: '%v'", errColumnsNotFound, strings.Join(missingColumns, "','")),
+		)
+	}
+
+	return nil
+}
+
+func assign(dst reflect.Value, src any) error {
+	if src == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+
+		return nil
+	}
+
+	if dst.Kind() == reflect.Pointer {
+		v := reflect.New(dst.Type().Elem())
+		if err := assign(v.Elem(), src); err != nil {
+			return err
+		}
+		dst.Set(v)
+
+		return nil
+	}
+
+	sv := reflect.ValueOf(src)
+	switch {
+	case sv.Type().AssignableTo(dst.Type()):
+		dst.Set(sv)
+	case dst.Kind() == reflect.Slice && sv.Kind() == reflect.Slice && sv.Type().Elem().Kind() == reflect.Interface:
+		items := reflect.MakeSlice(dst.Type(), sv.Len(), sv.Len())
+		for i := 0; i < sv.Len(); i++ {
+			if err := assign(items.Index(i), sv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		dst.Set(items)
+	case isNumber(sv.Kind()) && isNumber(dst.Kind()):
+		return assignNumber(dst, sv)
+	case sv.Kind() == reflect.String && dst.Kind() == reflect.String,
+		sv.Kind() == reflect.Slice && sv.Type().Elem().Kind() == reflect.Uint8 && dst.Kind() == reflect.String:
+		dst.Set(sv.Convert(dst.Type()))
+	default:
+		return xerrors.WithStackTrace(fmt.Errorf("%w: %T into %s", errCannotScan, src, dst.Type()))
+	}
+
+	return nil
+}
+
+// assignNumber converts number without loss: values out of range of dst and fractional values
+// for integer dst are errors
+func assignNumber(dst, sv reflect.Value) error {
+	overflow := false
+	switch {
+	case isInt(sv.Kind()):
+		v := sv.Int()
+		switch {
+		case isInt(dst.Kind()):
+			overflow = dst.OverflowInt(v)
+		case isUint(dst.Kind()):
+			overflow = v < 0 || dst.OverflowUint(uint64(v))
+		}
+	case isUint(sv.Kind()):
+		v := sv.Uint()
+		switch {
+		case isInt(dst.Kind()):
+			overflow = v > math.MaxInt64 || dst.OverflowInt(int64(v))
+		case isUint(dst.Kind()):
+			overflow = dst.OverflowUint(v)
+		}
+	default:
+		v := sv.Float()
+		switch {
+		case isInt(dst.Kind()):
+			overflow = v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 || dst.OverflowInt(int64(v))
+		case isUint(dst.Kind()):
+			overflow = v != math.Trunc(v) || v < 0 || v >= math.MaxUint64 || dst.OverflowUint(uint64(v))
+		default:
+			overflow = dst.OverflowFloat(v)
+		}
+	}
+	if overflow {
+		return xerrors.WithStackTrace(fmt.Errorf("%w: %v overflows %s", errCannotScan, sv.Interface(), dst.Type()))
+	}
+
+	dst.Set(sv.Convert(dst.Type()))
+
+	return nil
+}
+
+func isInt(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	default:
+		return false
+	}
+}
+
+func isUint(k reflect.Kind) bool {
+	switch k {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+func isNumber(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
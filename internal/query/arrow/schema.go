@@ -0,0 +1,227 @@
+package arrow
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// TypeID is an identifier of Arrow data type
+type TypeID int
+
+// Arrow data types (values are the same as in Arrow flatbuffers schema)
+const (
+	TypeNull            = TypeID(1)
+	TypeInt             = TypeID(2)
+	TypeFloatingPoint   = TypeID(3)
+	TypeBinary          = TypeID(4)
+	TypeUtf8            = TypeID(5)
+	TypeBool            = TypeID(6)
+	TypeDecimal         = TypeID(7)
+	TypeDate            = TypeID(8)
+	TypeTime            = TypeID(9)
+	TypeTimestamp       = TypeID(10)
+	TypeInterval        = TypeID(11)
+	TypeList            = TypeID(12)
+	TypeStruct          = TypeID(13)
+	TypeUnion           = TypeID(14)
+	TypeFixedSizeBinary = TypeID(15)
+	TypeFixedSizeList   = TypeID(16)
+	TypeMap             = TypeID(17)
+	TypeDuration        = TypeID(18)
+	TypeLargeBinary     = TypeID(19)
+	TypeLargeUtf8       = TypeID(20)
+	TypeLargeList       = TypeID(21)
+)
+
+var typeNames = map[TypeID]string{
+	TypeNull:            "Null",
+	TypeInt:             "Int",
+	TypeFloatingPoint:   "FloatingPoint",
+	TypeBinary:          "Binary",
+	TypeUtf8:            "Utf8",
+	TypeBool:            "Bool",
+	TypeDecimal:         "Decimal",
+	TypeDate:            "Date",
+	TypeTime:            "Time",
+	TypeTimestamp:       "Timestamp",
+	TypeInterval:        "Interval",
+	TypeList:            "List",
+	TypeStruct:          "Struct",
+	TypeUnion:           "Union",
+	TypeFixedSizeBinary: "FixedSizeBinary",
+	TypeFixedSizeList:   "FixedSizeList",
+	TypeMap:             "Map",
+	TypeDuration:        "Duration",
+	TypeLargeBinary:     "LargeBinary",
+	TypeLargeUtf8:       "LargeUtf8",
+	TypeLargeList:       "LargeList",
+}
+
+func (id TypeID) String() string {
+	if name, has := typeNames[id]; has {
+		return name
+	}
+
+	return "TypeID(" + strconv.Itoa(int(id)) + ")"
+}
+
+// TimeUnit is a unit of Time, Timestamp and Duration values
+type TimeUnit int
+
+const (
+	Second      = TimeUnit(0)
+	Millisecond = TimeUnit(1)
+	Microsecond = TimeUnit(2)
+	Nanosecond  = TimeUnit(3)
+)
+
+// DateUnit is a unit of Date values
+type DateUnit int
+
+const (
+	DateDay         = DateUnit(0)
+	DateMillisecond = DateUnit(1)
+)
+
+type (
+	// DataType describes Arrow data type with its parameters
+	DataType struct {
+		ID TypeID
+
+		// BitWidth is a width of Int, FloatingPoint, Time and Decimal values
+		BitWidth int
+		// Signed is a signedness of Int values
+		Signed bool
+
+		// Unit is a unit of Time, Timestamp and Duration values
+		Unit TimeUnit
+		// DateUnit is a unit of Date values
+		DateUnit DateUnit
+		// Timezone of Timestamp values
+		Timezone string
+
+		// Precision and Scale of Decimal values
+		Precision int
+		Scale     int
+
+		// ByteWidth is a size of FixedSizeBinary values or size of FixedSizeList lists
+		ByteWidth int
+	}
+
+	// Field describes column of record batch
+	Field struct {
+		Name     string
+		Type     DataType
+		Nullable bool
+		Children []Field
+	}
+
+	// Schema describes columns of record batches
+	Schema struct {
+		Fields []Field
+	}
+)
+
+func (t DataType) String() string {
+	switch t.ID {
+	case TypeInt:
+		if t.Signed {
+			return "Int" + strconv.Itoa(t.BitWidth)
+		}
+
+		return "Uint" + strconv.Itoa(t.BitWidth)
+	case TypeFloatingPoint:
+		return "Float" + strconv.Itoa(t.BitWidth)
+	case TypeDecimal:
+		return fmt.Sprintf("Decimal(%d,%d)", t.Precision, t.Scale)
+	case TypeFixedSizeBinary:
+		return fmt.Sprintf("FixedSizeBinary(%d)", t.ByteWidth)
+	default:
+		return t.ID.String()
+	}
+}
+
+const (
+	floatHalf   = 0
+	floatSingle = 1
+	floatDouble = 2
+)
+
+func parseSchema(t fbTable) (*Schema, error) {
+	if t.int16(0, 0) != 0 {
+		return nil, fmt.Errorf("%w: big endian data", errUnsupported)
+	}
+
+	fields, err := parseFields(t, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Schema{Fields: fields}, nil
+}
+
+func parseFields(t fbTable, field int) ([]Field, error) {
+	start, n := t.vector(field)
+	fields := make([]Field, n)
+	for i := range fields {
+		f, err := parseField(t.vectorTable(start, i))
+		if err != nil {
+			return nil, err
+		}
+		fields[i] = f
+	}
+
+	return fields, nil
+}
+
+func parseField(t fbTable) (f Field, err error) {
+	f.Name = t.string(0)
+	f.Nullable = t.bool(1, false)
+	if t.has(4) { //nolint:gomnd
+		return f, fmt.Errorf("%w: dictionary encoded field %q", errUnsupported, f.Name)
+	}
+
+	f.Type.ID = TypeID(t.uint8(2, 0)) //nolint:gomnd
+	tt, _ := t.table(3)               //nolint:gomnd
+	switch f.Type.ID {
+	case TypeInt:
+		f.Type.BitWidth = int(tt.int32(0, 0))
+		f.Type.Signed = tt.bool(1, false)
+	case TypeFloatingPoint:
+		switch tt.int16(0, floatHalf) {
+		case floatHalf:
+			f.Type.BitWidth = 16
+		case floatSingle:
+			f.Type.BitWidth = 32
+		case floatDouble:
+			f.Type.BitWidth = 64
+		}
+	case TypeDecimal:
+		f.Type.Precision = int(tt.int32(0, 0))
+		f.Type.Scale = int(tt.int32(1, 0))
+		f.Type.BitWidth = int(tt.int32(2, 128)) //nolint:gomnd
+	case TypeDate:
+		f.Type.DateUnit = DateUnit(tt.int16(0, int16(DateMillisecond)))
+	case TypeTime:
+		f.Type.Unit = TimeUnit(tt.int16(0, int16(Millisecond)))
+		f.Type.BitWidth = int(tt.int32(1, 32)) //nolint:gomnd
+	case TypeTimestamp:
+		f.Type.Unit = TimeUnit(tt.int16(0, 0))
+		f.Type.Timezone = tt.string(1)
+	case TypeDuration:
+		f.Type.Unit = TimeUnit(tt.int16(0, int16(Millisecond)))
+	case TypeFixedSizeBinary, TypeFixedSizeList:
+		f.Type.ByteWidth = int(tt.int32(0, 0))
+	case TypeNull, TypeBinary, TypeUtf8, TypeBool, TypeList, TypeStruct, TypeMap,
+		TypeLargeBinary, TypeLargeUtf8, TypeLargeList:
+	default:
+		return f, fmt.Errorf("%w: type %s of field %q", errUnsupported, f.Type.ID, f.Name)
+	}
+
+	f.Children, err = parseFields(t, 5) //nolint:gomnd
+	if err != nil {
+		return f, err
+	}
+
+	return f, nil
+}
@@ -0,0 +1,87 @@
+// Command gen writes testdata/all_types.arrow: Arrow IPC stream with two record batches of all supported
+// column types. It is separate module, so the SDK doesn't depend on arrow-go.
+//
+//	cd testdata/gen && go run . ../all_types.arrow
+package main
+
+import (
+	"os"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func main() {
+	mem := memory.NewGoAllocator()
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "u8", Type: arrow.PrimitiveTypes.Uint8},
+		{Name: "f", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "data", Type: arrow.BinaryTypes.Binary},
+		{Name: "flag", Type: arrow.FixedWidthTypes.Boolean},
+		{Name: "ts", Type: &arrow.TimestampType{Unit: arrow.Microsecond}},
+		{Name: "d", Type: arrow.FixedWidthTypes.Date32},
+		{Name: "dur", Type: arrow.FixedWidthTypes.Duration_ns},
+		{Name: "dec", Type: &arrow.Decimal128Type{Precision: 22, Scale: 9}},
+		{Name: "tags", Type: arrow.ListOf(arrow.BinaryTypes.String)},
+		{Name: "st", Type: arrow.StructOf(
+			arrow.Field{Name: "a", Type: arrow.PrimitiveTypes.Int32},
+			arrow.Field{Name: "b", Type: arrow.BinaryTypes.String, Nullable: true},
+		)},
+	}, nil)
+
+	f, err := os.Create(os.Args[1])
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+
+	w := ipc.NewWriter(f, ipc.WithSchema(schema), ipc.WithAllocator(mem))
+	base := time.Date(2024, 5, 6, 7, 8, 9, 123456000, time.UTC)
+	for batch := 0; batch < 2; batch++ {
+		b := array.NewRecordBuilder(mem, schema)
+		for i := 0; i < 3; i++ {
+			n := batch*3 + i
+			b.Field(0).(*array.Int64Builder).Append(int64(n))
+			b.Field(1).(*array.Uint8Builder).Append(uint8(200 + n))
+			if n%2 == 1 {
+				b.Field(2).AppendNull()
+				b.Field(3).AppendNull()
+			} else {
+				b.Field(2).(*array.Float64Builder).Append(float64(n) + 0.5)
+				b.Field(3).(*array.StringBuilder).Append("name" + string(rune('0'+n)))
+			}
+			b.Field(4).(*array.BinaryBuilder).Append([]byte{byte(n), 0xff})
+			b.Field(5).(*array.BooleanBuilder).Append(n%3 == 0)
+			b.Field(6).(*array.TimestampBuilder).Append(arrow.Timestamp(base.Add(time.Duration(n) * time.Hour).UnixMicro()))
+			b.Field(7).(*array.Date32Builder).Append(arrow.Date32FromTime(base.AddDate(0, 0, n)))
+			b.Field(8).(*array.DurationBuilder).Append(arrow.Duration(time.Duration(n) * time.Second))
+			b.Field(9).(*array.Decimal128Builder).Append(decimal128.FromI64(int64(n*1000000000 - 1500000000)))
+			lb := b.Field(10).(*array.ListBuilder)
+			lb.Append(true)
+			for j := 0; j < n%3; j++ {
+				lb.ValueBuilder().(*array.StringBuilder).Append("t" + string(rune('0'+j)))
+			}
+			sb := b.Field(11).(*array.StructBuilder)
+			sb.Append(true)
+			sb.FieldBuilder(0).(*array.Int32Builder).Append(int32(-n))
+			if n == 0 {
+				sb.FieldBuilder(1).AppendNull()
+			} else {
+				sb.FieldBuilder(1).(*array.StringBuilder).Append("b")
+			}
+		}
+		rec := b.NewRecord()
+		if err := w.Write(rec); err != nil {
+			panic(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		panic(err)
+	}
+}
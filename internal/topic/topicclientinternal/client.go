@@ -1,6 +1,7 @@
 package topicclientinternal
 
 import (
+	"bytes"
 	"context"
 	"errors"
 
@@ -273,6 +274,7 @@ func (c *Client) StartListener(
 
 	cfg.Consumer = consumer
 	cfg.Tracer = c.cfg.Trace // Set tracer from client config
+	cfg.DeadLetter.StartWriter = c.startDeadLetterWriter
 
 	cfg.Selectors = make([]*topicreadercommon.PublicReadSelector, len(readSelectors))
 	for i := range readSelectors {
@@ -316,6 +318,7 @@ func (c *Client) StartReader(
 		topicreaderinternal.WithCredentials(c.cred),
 		topicreaderinternal.WithTrace(c.cfg.Trace),
 		topicoptions.WithReaderStartTimeout(topic.DefaultStartTimeout),
+		topicreaderinternal.WithDeadLetterWriter(c.startDeadLetterWriter),
 	}
 	opts = append(defaultOpts, opts...)
 
@@ -342,6 +345,113 @@ func (c *Client) StartWriter(topicPath string, opts ...topicoptions.WriterOption
 	return topicwriter.NewWriter(writer), nil
 }
 
+func (c *Client) startDeadLetterWriter(topicPath string) (topicreadercommon.DeadLetterWriter, error) {
+	cfg := c.createWriterConfig(topicPath, []topicoptions.WriterOption{
+		topicwriterinternal.WithMaxGrpcMessageBytes(c.cfg.MaxGrpcMessageSize),
+	})
+	writer, err := topicwriterinternal.NewWriterReconnector(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return deadLetterWriter{writer: writer}, nil
+}
+
+// deadLetterWriter adapts topic writer for dead letter queue of readers and listeners
+type deadLetterWriter struct {
+	writer *topicwriterinternal.WriterReconnector
+}
+
+func (w deadLetterWriter) Write(ctx context.Context, messages []topicreadercommon.DeadLetterMessage) error {
+	writerMessages := make([]topicwriterinternal.PublicMessage, len(messages))
+	for i := range messages {
+		writerMessages[i] = topicwriterinternal.PublicMessage{
+			CreatedAt: messages[i].CreatedAt,
+			Data:      bytes.NewReader(messages[i].Data),
+			Metadata:  messages[i].Metadata,
+		}
+	}
+
+	if err := w.writer.Write(ctx, writerMessages); err != nil {
+		return err
+	}
+
+	return w.writer.Flush(ctx)
+}
+
+func (w deadLetterWriter) Close(ctx context.Context) error {
+	return w.writer.Close(ctx)
+}
+
+// StartKeyedWriter describes topic partitions and create writer, which routes messages to partitions by key
+func (c *Client) StartKeyedWriter(
+	ctx context.Context,
+	topicPath string,
+	opts ...topicoptions.KeyedWriterOption,
+) (*topicwriter.KeyedWriter, error) {
+	describe := func(ctx context.Context) ([]rawtopic.PartitionInfo, error) {
+		description, err := c.Describe(ctx, topicPath)
+		if err != nil {
+			return nil, err
+		}
+
+		partitions := make([]rawtopic.PartitionInfo, len(description.Partitions))
+		for i := range description.Partitions {
+			p := &description.Partitions[i]
+			partitions[i] = rawtopic.PartitionInfo{
+				PartitionID: p.PartitionID,
+				Active:      p.Active,
+				KeyRange: rawtopic.PartitionKeyRange{
+					FromBound: p.KeyRange.FromBound,
+					ToBound:   p.KeyRange.ToBound,
+				},
+			}
+		}
+
+		return partitions, nil
+	}
+
+	cfg := topicwriterinternal.NewKeyedWriterConfig(describe, opts...)
+	cfg.WriterOptions = append([]topicoptions.WriterOption{
+		topicwriterinternal.WithRawClient(&c.rawClient),
+		topicwriterinternal.WithTopic(topicPath),
+		topicwriterinternal.WithCommonConfig(c.cfg.Common),
+		topicwriterinternal.WithTrace(c.cfg.Trace),
+		topicwriterinternal.WithCredentials(c.cred),
+		topicwriterinternal.WithMaxGrpcMessageBytes(c.cfg.MaxGrpcMessageSize),
+	}, cfg.WriterOptions...)
+
+	writer, err := topicwriterinternal.NewKeyedWriter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return topicwriter.NewKeyedWriter(writer), nil
+}
+
+// StartWriterPool create pool of writers with distinct producer ids
+func (c *Client) StartWriterPool(
+	topicPath string,
+	opts ...topicoptions.WriterPoolOption,
+) (*topicwriter.Pool, error) {
+	cfg := topicwriterinternal.NewWriterPoolConfig(opts...)
+	cfg.WriterOptions = append([]topicoptions.WriterOption{
+		topicwriterinternal.WithRawClient(&c.rawClient),
+		topicwriterinternal.WithTopic(topicPath),
+		topicwriterinternal.WithCommonConfig(c.cfg.Common),
+		topicwriterinternal.WithTrace(c.cfg.Trace),
+		topicwriterinternal.WithCredentials(c.cred),
+		topicwriterinternal.WithMaxGrpcMessageBytes(c.cfg.MaxGrpcMessageSize),
+	}, cfg.WriterOptions...)
+
+	pool, err := topicwriterinternal.NewWriterPool(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return topicwriter.NewPool(pool), nil
+}
+
 func (c *Client) StartTransactionalWriter(
 	transaction tx.Identifier,
 	topicpath string,
@@ -12,11 +12,17 @@ import (
 type StreamListenerConfig struct {
 	BufferSize             int
 	Decoders               topicreadercommon.DecoderMap
+	Middlewares            []topicreadercommon.PublicMiddleware
 	Selectors              []*topicreadercommon.PublicReadSelector
 	Consumer               string
 	ConnectWithoutConsumer bool
 	readerID               int64
 	Tracer                 *trace.Topic
+	DeadLetter             topicreadercommon.DeadLetterConfig
+	KeyedProcessing        KeyedProcessingConfig
+
+	// deadLetterQueue shared between stream listeners, for save failure counters across reconnects
+	deadLetterQueue *topicreadercommon.DeadLetterQueue
 }
 
 func NewStreamListenerConfig() StreamListenerConfig {
@@ -27,6 +33,9 @@ func NewStreamListenerConfig() StreamListenerConfig {
 		Consumer:   "",
 		readerID:   topicreadercommon.NextReaderID(),
 		Tracer:     &trace.Topic{},
+		KeyedProcessing: KeyedProcessingConfig{
+			MaxInFlightMessages: DefaultKeyedMaxInFlightMessages,
+		},
 	}
 }
 
@@ -51,6 +60,12 @@ func (cfg *StreamListenerConfig) Validate() error {
 			cfg.BufferSize,
 		))
 	}
+	if err := cfg.DeadLetter.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := cfg.KeyedProcessing.Validate(); err != nil {
+		errs = append(errs, err)
+	}
 
 	if len(errs) > 0 {
 		return xerrors.WithStackTrace(xerrors.Wrap(fmt.Errorf(
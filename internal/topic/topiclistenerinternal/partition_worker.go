@@ -3,8 +3,11 @@ package topiclistenerinternal
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/background"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/backoff"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/grpcwrapper/rawtopic/rawtopiccommon"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/grpcwrapper/rawtopic/rawtopicreader"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/topic/topicreadercommon"
@@ -45,6 +48,10 @@ type PartitionWorker struct {
 	messageSender      MessageSender
 	userHandler        EventHandler
 	onStopped          WorkerStoppedCallback
+	deadLetter         *topicreadercommon.DeadLetterQueue
+	keyed              KeyedProcessingConfig
+	keyedProcessor     *keyedProcessor
+	stopOnce           sync.Once
 
 	// Tracing and logging fields
 	tracer     *trace.Topic
@@ -95,6 +102,10 @@ func (w *PartitionWorker) Start(ctx context.Context) {
 	)
 
 	w.bgWorker = background.NewWorker(ctx, "partition worker")
+	if w.keyed.Enabled() {
+		commitHandler, _ := w.messageSender.(CommitHandler)
+		w.keyedProcessor = newKeyedProcessor(w.bgWorker.Context(), w, commitHandler, w.keyed)
+	}
 	w.bgWorker.Start("partition worker message loop", func(bgCtx context.Context) {
 		w.receiveMessagesLoop(bgCtx)
 	})
@@ -133,12 +144,19 @@ func (w *PartitionWorker) Close(ctx context.Context, reason error) error {
 	return nil
 }
 
+// notifyStopped calls onStopped callback once, the worker may be stopped by message loop or by keyed lane
+func (w *PartitionWorker) notifyStopped(reason error) {
+	w.stopOnce.Do(func() {
+		w.onStopped(w.partitionSessionID, reason)
+	})
+}
+
 // receiveMessagesLoop is the main message processing loop
 func (w *PartitionWorker) receiveMessagesLoop(ctx context.Context) {
 	defer func() {
 		if r := recover(); r != nil {
 			reason := xerrors.WithStackTrace(fmt.Errorf("ydb: partition worker panic: %v", r))
-			w.onStopped(w.partitionSessionID, reason)
+			w.notifyStopped(reason)
 		}
 	}()
 
@@ -154,7 +172,7 @@ func (w *PartitionWorker) receiveMessagesLoop(ctx context.Context) {
 					w.partitionSession.PartitionID,
 					w.partitionSessionID,
 				)))
-				w.onStopped(w.partitionSessionID, reason)
+				w.notifyStopped(reason)
 			} else {
 				reason := xerrors.WithStackTrace(xerrors.Wrap(fmt.Errorf(
 					"partition worker message queue context error: topic=%s, partition=%d, partitionSession=%d: %w",
@@ -163,7 +181,7 @@ func (w *PartitionWorker) receiveMessagesLoop(ctx context.Context) {
 					w.partitionSessionID,
 					err,
 				)))
-				w.onStopped(w.partitionSessionID, reason)
+				w.notifyStopped(reason)
 			}
 
 			return
@@ -176,13 +194,13 @@ func (w *PartitionWorker) receiveMessagesLoop(ctx context.Context) {
 				w.partitionSession.PartitionID,
 				w.partitionSessionID,
 			)))
-			w.onStopped(w.partitionSessionID, reason)
+			w.notifyStopped(reason)
 
 			return
 		}
 
 		if err := w.processUnifiedMessage(ctx, msg); err != nil {
-			w.onStopped(w.partitionSessionID, err)
+			w.notifyStopped(err)
 
 			return
 		}
@@ -266,6 +284,7 @@ func (w *PartitionWorker) callUserHandler(
 		w.partitionSession.Topic,
 		"OnReadMessages",
 		messagesCount,
+		topicreadercommon.MessagesMetadata(msg.Batch.Messages),
 	)
 
 	if err := w.userHandler.OnReadMessages(ctx, event); err != nil {
@@ -280,6 +299,59 @@ func (w *PartitionWorker) callUserHandler(
 	return nil
 }
 
+// callUserHandlerWithDeadLetter redelivers the batch to the user handler on failures
+// and moves the batch to dead letter topic after max attempts, if dead letter queue configured
+func (w *PartitionWorker) callUserHandlerWithDeadLetter(
+	ctx context.Context,
+	msg *batchMessage,
+	commitHandler CommitHandler,
+	messagesCount int,
+) error {
+	err := w.callUserHandler(ctx, msg, commitHandler, messagesCount)
+	if w.deadLetter == nil {
+		return err
+	}
+
+	commitRange := topicreadercommon.GetCommitRange(msg.Batch)
+	for attempt := 0; err != nil; attempt++ {
+		if ctx.Err() != nil {
+			return err
+		}
+
+		deadLettered, dlqErr := w.deadLetter.OnFailure(ctx, msg.Batch.Messages, commitRange, err)
+		if dlqErr != nil {
+			return dlqErr
+		}
+		if deadLettered {
+			return commitHandler.sendCommit(msg.Batch)
+		}
+
+		// handler fails usually because of unavailable dependencies, give them time for recover
+		if waitRedeliveryBackoff(ctx, attempt) != nil {
+			return err
+		}
+
+		topicreadercommon.BatchResetMessagesData(msg.Batch)
+		err = w.callUserHandler(ctx, msg, commitHandler, messagesCount)
+	}
+
+	w.deadLetter.Forget(commitRange)
+
+	return nil
+}
+
+func waitRedeliveryBackoff(ctx context.Context, attempt int) error {
+	t := time.NewTimer(backoff.Fast.Delay(attempt))
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
 // processBatchMessage handles ready PublicBatch messages
 func (w *PartitionWorker) processBatchMessage(ctx context.Context, msg *batchMessage) error {
 	// Add tracing for batch processing
@@ -313,8 +385,20 @@ func (w *PartitionWorker) processBatchMessage(ctx context.Context, msg *batchMes
 		return err
 	}
 
+	if w.keyedProcessor != nil {
+		// messages are processed by lanes of keys, flow control data requests are sent after every message
+		if err := w.keyedProcessor.dispatch(msg); err != nil {
+			traceDone(0, err)
+
+			return err
+		}
+		traceDone(messagesCount, nil)
+
+		return nil
+	}
+
 	// Call user handler with tracing
-	if err := w.callUserHandler(ctx, msg, commitHandler, messagesCount); err != nil {
+	if err := w.callUserHandlerWithDeadLetter(ctx, msg, commitHandler, messagesCount); err != nil {
 		traceDone(0, err)
 
 		return err
@@ -0,0 +1,241 @@
+package topiclistenerinternal
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/empty"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/grpcwrapper/rawtopic/rawtopiccommon"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/grpcwrapper/rawtopic/rawtopicreader"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/topic/topicreadercommon"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xcontext"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+)
+
+const DefaultKeyedMaxInFlightMessages = 100
+
+// KeyedProcessingConfig enables concurrent processing of messages within partition:
+// messages with different keys processed concurrently, messages with the same key - in order of offsets.
+type KeyedProcessingConfig struct {
+	// MessageKey returns key of the message. Keyed processing disabled if the func is nil.
+	MessageKey func(message *topicreadercommon.PublicMessage) string
+
+	// MaxInFlightMessages is max count of messages of one partition, which are received, but not committed yet
+	MaxInFlightMessages int
+}
+
+func (cfg *KeyedProcessingConfig) Enabled() bool {
+	return cfg.MessageKey != nil
+}
+
+func (cfg *KeyedProcessingConfig) Validate() error {
+	if !cfg.Enabled() {
+		return nil
+	}
+	if cfg.MaxInFlightMessages <= 0 {
+		return xerrors.WithStackTrace(fmt.Errorf(
+			"ydb: max in flight messages of keyed processing must be greater then 0, now: %v",
+			cfg.MaxInFlightMessages,
+		))
+	}
+
+	return nil
+}
+
+type keyedMessage struct {
+	metadata rawtopiccommon.ServerMessageMetadata
+	message  *topicreadercommon.PublicMessage
+}
+
+type keyedPendingMessage struct {
+	message *topicreadercommon.PublicMessage
+	done    bool
+}
+
+// keyedProcessor calls user handler for every message of the partition with single message batch.
+// Every key has own lane, lanes works concurrently. Confirm of the message marks it as done,
+// offsets committed up to first not done message only.
+// Every pending message holds in flight slot until the message is committed, so count of pending
+// messages is limited by MaxInFlightMessages.
+type keyedProcessor struct {
+	worker        *PartitionWorker
+	commitHandler CommitHandler
+	messageKey    func(message *topicreadercommon.PublicMessage) string
+	inFlight      chan empty.Struct
+
+	ctx       context.Context //nolint:containedctx
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+
+	m       sync.Mutex
+	lanes   map[string][]keyedMessage
+	pending []keyedPendingMessage
+}
+
+func newKeyedProcessor(
+	ctx context.Context,
+	worker *PartitionWorker,
+	commitHandler CommitHandler,
+	cfg KeyedProcessingConfig,
+) *keyedProcessor {
+	p := &keyedProcessor{
+		worker:        worker,
+		commitHandler: commitHandler,
+		messageKey:    cfg.MessageKey,
+		inFlight:      make(chan empty.Struct, cfg.MaxInFlightMessages),
+		lanes:         make(map[string][]keyedMessage),
+	}
+	p.ctx, p.cancel = xcontext.WithCancel(ctx)
+
+	return p
+}
+
+// dispatch sends messages of the batch to lanes of their keys.
+// It blocks while count of not committed messages reach the limit.
+func (p *keyedProcessor) dispatch(msg *batchMessage) error {
+	for _, mess := range msg.Batch.Messages {
+		select {
+		case <-p.ctx.Done():
+			return p.ctx.Err()
+		case p.inFlight <- empty.Struct{}:
+		}
+
+		key := p.messageKey(mess)
+
+		p.m.Lock()
+		p.pending = append(p.pending, keyedPendingMessage{message: mess})
+		queue, running := p.lanes[key]
+		p.lanes[key] = append(queue, keyedMessage{metadata: msg.ServerMessageMetadata, message: mess})
+		p.m.Unlock()
+
+		if !running {
+			p.worker.bgWorker.Start("keyed messages lane", func(context.Context) {
+				p.processLane(key)
+			})
+		}
+	}
+
+	return nil
+}
+
+// processLane processes messages with the key one by one, until the lane queue is empty.
+// The message stays at head of the queue while it is processing, for dispatch knows about running lane.
+func (p *keyedProcessor) processLane(key string) {
+	for {
+		p.m.Lock()
+		item := p.lanes[key][0]
+		p.m.Unlock()
+
+		if err := p.processMessage(item); err != nil {
+			p.fail(err)
+
+			return
+		}
+
+		p.m.Lock()
+		queue := p.lanes[key][1:]
+		if len(queue) == 0 {
+			delete(p.lanes, key)
+			p.m.Unlock()
+
+			return
+		}
+		p.lanes[key] = queue
+		p.m.Unlock()
+	}
+}
+
+func (p *keyedProcessor) processMessage(item keyedMessage) error {
+	batch, err := topicreadercommon.NewBatch(
+		p.worker.partitionSession,
+		[]*topicreadercommon.PublicMessage{item.message},
+	)
+	if err != nil {
+		return err
+	}
+
+	msg := &batchMessage{ServerMessageMetadata: item.metadata, Batch: batch}
+	if err = p.worker.callUserHandlerWithDeadLetter(p.ctx, msg, p, 1); err != nil {
+		return err
+	}
+
+	p.worker.messageSender.SendRaw(&rawtopicreader.ReadRequest{
+		BytesSize: topicreadercommon.MessageGetBufferBytesAccount(item.message),
+	})
+
+	return nil
+}
+
+// fail stops the partition worker after error of a lane
+func (p *keyedProcessor) fail(err error) {
+	p.closeOnce.Do(func() {
+		p.cancel()
+		p.worker.notifyStopped(err)
+		p.worker.messageQueue.Close()
+	})
+}
+
+// markDone marks messages of the commit range as done and returns batch of messages
+// from first not committed message to first not done message. The batch is nil if nothing to commit.
+func (p *keyedProcessor) markDone(
+	commitRange topicreadercommon.CommitRange,
+) (*topicreadercommon.PublicBatch, error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	for i := range p.pending {
+		messageRange := topicreadercommon.GetCommitRange(p.pending[i].message)
+		if messageRange.CommitOffsetStart >= commitRange.CommitOffsetStart &&
+			messageRange.CommitOffsetEnd <= commitRange.CommitOffsetEnd {
+			p.pending[i].done = true
+		}
+	}
+
+	doneCount := 0
+	for doneCount < len(p.pending) && p.pending[doneCount].done {
+		doneCount++
+	}
+	if doneCount == 0 {
+		return nil, nil
+	}
+
+	messages := make([]*topicreadercommon.PublicMessage, doneCount)
+	for i := range messages {
+		messages[i] = p.pending[i].message
+
+		// every pending message took the slot in dispatch
+		<-p.inFlight
+	}
+	p.pending = p.pending[doneCount:]
+
+	return topicreadercommon.NewBatch(p.worker.partitionSession, messages)
+}
+
+func (p *keyedProcessor) sendCommit(b *topicreadercommon.PublicBatch) error {
+	batch, err := p.markDone(topicreadercommon.GetCommitRange(b))
+	if err != nil || batch == nil {
+		return err
+	}
+
+	return p.commitHandler.sendCommit(batch)
+}
+
+func (p *keyedProcessor) getSyncCommitter() SyncCommitter {
+	return keyedSyncCommitter{p}
+}
+
+// keyedSyncCommitter commits contiguous done offsets and waits the server ack.
+// If earlier messages are not done yet - the offsets will be committed with them, Commit returns without wait.
+type keyedSyncCommitter struct {
+	p *keyedProcessor
+}
+
+func (c keyedSyncCommitter) Commit(ctx context.Context, commitRange topicreadercommon.CommitRange) error {
+	batch, err := c.p.markDone(commitRange)
+	if err != nil || batch == nil {
+		return err
+	}
+
+	return c.p.commitHandler.getSyncCommitter().Commit(ctx, topicreadercommon.GetCommitRange(batch))
+}
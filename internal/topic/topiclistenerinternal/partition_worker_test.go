@@ -3,6 +3,7 @@ package topiclistenerinternal
 import (
 	"context"
 	"errors"
+	"io"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -528,6 +529,345 @@ func TestPartitionWorkerInterface_UserHandlerError(t *testing.T) {
 	require.Contains(t, (*errPtr).Error(), "user handler error")
 }
 
+type testDeadLetterWriter struct {
+	mu       sync.Mutex
+	messages []topicreadercommon.DeadLetterMessage
+}
+
+func (w *testDeadLetterWriter) Write(_ context.Context, messages []topicreadercommon.DeadLetterMessage) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.messages = append(w.messages, messages...)
+
+	return nil
+}
+
+func (w *testDeadLetterWriter) Close(context.Context) error {
+	return nil
+}
+
+func TestPartitionWorkerInterface_UserHandlerErrorWithDeadLetter(t *testing.T) {
+	ctx := xtest.Context(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	session := createTestPartitionSession()
+	messageSender := newSyncMessageSender()
+	mockHandler := NewMockEventHandler(ctrl)
+
+	var stoppedErr atomic.Pointer[error]
+	onStopped := func(sessionID rawtopicreader.PartitionSessionID, err error) {
+		stoppedErr.Store(&err)
+	}
+
+	worker := NewPartitionWorker(
+		123,
+		session,
+		messageSender,
+		mockHandler,
+		onStopped,
+		&trace.Topic{},
+		"test-listener",
+	)
+	dlqWriter := &testDeadLetterWriter{}
+	worker.deadLetter = topicreadercommon.NewDeadLetterQueue(topicreadercommon.DeadLetterConfig{
+		Topic:       "dlq",
+		MaxAttempts: 3,
+		StartWriter: func(topicPath string) (topicreadercommon.DeadLetterWriter, error) {
+			return dlqWriter, nil
+		},
+	})
+
+	// batch is redelivered with same content on every attempt
+	var attempts int
+	mockHandler.EXPECT().
+		OnReadMessages(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, event *PublicReadMessages) error {
+			attempts++
+			content, err := io.ReadAll(event.Batch.Messages[0])
+			require.NoError(t, err)
+			require.Equal(t, "poison", string(content))
+
+			return errors.New("user handler error")
+		}).
+		Times(3)
+
+	worker.Start(ctx)
+	defer func() {
+		err := worker.Close(ctx, nil)
+		require.NoError(t, err)
+	}()
+
+	batch, err := topicreadercommon.NewBatchFromStream(topicreadercommon.NewDecoderMap(), session, rawtopicreader.Batch{
+		Codec: rawtopiccommon.CodecRaw,
+		MessageData: []rawtopicreader.MessageData{
+			{Offset: rawtopiccommon.NewOffset(100), Data: []byte("poison")},
+		},
+	})
+	require.NoError(t, err)
+
+	worker.AddMessagesBatch(rawtopiccommon.ServerMessageMetadata{Status: rawydb.StatusSuccess}, batch)
+
+	// commit is sent before read request, the read request signals only
+	require.NoError(t, messageSender.waitForMessage(ctx))
+
+	messages := messageSender.GetMessages()
+	require.Len(t, messages, 2)
+	require.Equal(t, -1, messages[0].(*rawtopicreader.ReadRequest).BytesSize) // commit
+	require.Equal(t, 3, attempts)
+	require.Nil(t, stoppedErr.Load())
+
+	dlqWriter.mu.Lock()
+	defer dlqWriter.mu.Unlock()
+	require.Len(t, dlqWriter.messages, 1)
+	require.Equal(t, []byte("poison"), dlqWriter.messages[0].Data)
+	require.Contains(t, string(dlqWriter.messages[0].Metadata[topicreadercommon.DeadLetterMetadataError]),
+		"user handler error")
+}
+
+// keyedCommitsSender records commit ranges of the keyed processing
+type keyedCommitsSender struct {
+	*syncMessageSender
+	commits chan topicreadercommon.CommitRange
+}
+
+func (s *keyedCommitsSender) sendCommit(b *topicreadercommon.PublicBatch) error {
+	s.commits <- topicreadercommon.GetCommitRange(b)
+
+	return nil
+}
+
+func createTestKeyedBatch(
+	t *testing.T,
+	session *topicreadercommon.PartitionSession,
+	keys ...string,
+) *topicreadercommon.PublicBatch {
+	messages := make([]rawtopicreader.MessageData, len(keys))
+	for i, key := range keys {
+		messages[i] = rawtopicreader.MessageData{
+			Offset:         rawtopiccommon.NewOffset(int64(100 + i)),
+			MessageGroupID: key,
+		}
+	}
+	batch, err := topicreadercommon.NewBatchFromStream(topicreadercommon.NewDecoderMap(), session, rawtopicreader.Batch{
+		Codec:       rawtopiccommon.CodecRaw,
+		MessageData: messages,
+	})
+	require.NoError(t, err)
+
+	return batch
+}
+
+func TestPartitionWorkerInterface_KeyedProcessing(t *testing.T) {
+	ctx := xtest.Context(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	session := createTestPartitionSession()
+	messageSender := &keyedCommitsSender{
+		syncMessageSender: newSyncMessageSender(),
+		commits:           make(chan topicreadercommon.CommitRange, 10),
+	}
+	mockHandler := NewMockEventHandler(ctrl)
+
+	var stoppedErr atomic.Pointer[error]
+	onStopped := func(sessionID rawtopicreader.PartitionSessionID, err error) {
+		stoppedErr.Store(&err)
+	}
+
+	worker := NewPartitionWorker(
+		123,
+		session,
+		messageSender,
+		mockHandler,
+		onStopped,
+		&trace.Topic{},
+		"test-listener",
+	)
+	worker.keyed = KeyedProcessingConfig{
+		MessageKey: func(message *topicreadercommon.PublicMessage) string {
+			return message.MessageGroupID
+		},
+		MaxInFlightMessages: 10,
+	}
+
+	firstStarted := make(empty.Chan)
+	releaseFirst := make(empty.Chan)
+	processed := make(chan int64, 4)
+	mockHandler.EXPECT().
+		OnReadMessages(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, event *PublicReadMessages) error {
+			require.Len(t, event.Batch.Messages, 1)
+			offset := event.Batch.Messages[0].Offset
+			if offset == 100 {
+				close(firstStarted)
+				<-releaseFirst
+			}
+			processed <- offset
+			event.Confirm()
+
+			return nil
+		}).
+		Times(4)
+
+	worker.Start(ctx)
+	defer func() {
+		err := worker.Close(ctx, nil)
+		require.NoError(t, err)
+	}()
+
+	worker.AddMessagesBatch(
+		rawtopiccommon.ServerMessageMetadata{Status: rawydb.StatusSuccess},
+		createTestKeyedBatch(t, session, "a", "b", "a", "b"),
+	)
+
+	// messages of key "b" processed while first message of key "a" is in progress
+	xtest.WaitChannelClosed(t, firstStarted)
+	require.Equal(t, int64(101), <-processed)
+	require.Equal(t, int64(103), <-processed)
+	require.Empty(t, messageSender.commits)
+
+	// commit moves after the first message only, then up to end of the batch
+	close(releaseFirst)
+	require.Equal(t, int64(100), <-processed)
+	commit := <-messageSender.commits
+	require.Equal(t, rawtopiccommon.NewOffset(100), commit.CommitOffsetStart)
+	require.Equal(t, rawtopiccommon.NewOffset(102), commit.CommitOffsetEnd)
+
+	require.Equal(t, int64(102), <-processed)
+	commit = <-messageSender.commits
+	require.Equal(t, rawtopiccommon.NewOffset(102), commit.CommitOffsetStart)
+	require.Equal(t, rawtopiccommon.NewOffset(104), commit.CommitOffsetEnd)
+
+	require.NoError(t, messageSender.waitForMessages(ctx, 4))
+	require.Nil(t, stoppedErr.Load())
+}
+
+func TestPartitionWorkerInterface_KeyedProcessingInFlightUntilCommit(t *testing.T) {
+	ctx := xtest.Context(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	session := createTestPartitionSession()
+	messageSender := &keyedCommitsSender{
+		syncMessageSender: newSyncMessageSender(),
+		commits:           make(chan topicreadercommon.CommitRange, 10),
+	}
+	mockHandler := NewMockEventHandler(ctrl)
+
+	worker := NewPartitionWorker(
+		123,
+		session,
+		messageSender,
+		mockHandler,
+		func(rawtopicreader.PartitionSessionID, error) {},
+		&trace.Topic{},
+		"test-listener",
+	)
+	worker.keyed = KeyedProcessingConfig{
+		MessageKey: func(message *topicreadercommon.PublicMessage) string {
+			return message.MessageGroupID
+		},
+		MaxInFlightMessages: 2,
+	}
+
+	var confirmFirst func()
+	firstReturned := make(empty.Chan)
+	processed := make(chan int64, 3)
+	mockHandler.EXPECT().
+		OnReadMessages(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, event *PublicReadMessages) error {
+			offset := event.Batch.Messages[0].Offset
+			processed <- offset
+			if offset == 100 {
+				// the message will be confirmed after return from handler
+				confirmFirst = event.Confirm
+				close(firstReturned)
+
+				return nil
+			}
+			event.Confirm()
+
+			return nil
+		}).
+		Times(3)
+
+	worker.Start(ctx)
+	defer func() {
+		err := worker.Close(ctx, nil)
+		require.NoError(t, err)
+	}()
+
+	worker.AddMessagesBatch(
+		rawtopiccommon.ServerMessageMetadata{Status: rawydb.StatusSuccess},
+		createTestKeyedBatch(t, session, "a", "b", "c"),
+	)
+
+	xtest.WaitChannelClosed(t, firstReturned)
+	received := map[int64]bool{<-processed: true, <-processed: true}
+	require.Equal(t, map[int64]bool{100: true, 101: true}, received)
+
+	// message 101 is confirmed, but not committed: third message waits commit of first message
+	select {
+	case offset := <-processed:
+		t.Fatalf("unexpected processing of message %v", offset)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	confirmFirst()
+	require.Equal(t, int64(102), <-processed)
+}
+
+func TestPartitionWorkerInterface_KeyedProcessingHandlerError(t *testing.T) {
+	ctx := xtest.Context(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	session := createTestPartitionSession()
+	messageSender := newSyncMessageSender()
+	mockHandler := NewMockEventHandler(ctrl)
+
+	var stopCalls atomic.Int32
+	stopped := make(empty.Chan)
+	onStopped := func(sessionID rawtopicreader.PartitionSessionID, err error) {
+		require.Contains(t, err.Error(), "user handler error")
+		if stopCalls.Add(1) == 1 {
+			close(stopped)
+		}
+	}
+
+	worker := NewPartitionWorker(
+		123,
+		session,
+		messageSender,
+		mockHandler,
+		onStopped,
+		&trace.Topic{},
+		"test-listener",
+	)
+	worker.keyed = KeyedProcessingConfig{
+		MessageKey: func(message *topicreadercommon.PublicMessage) string {
+			return message.MessageGroupID
+		},
+		MaxInFlightMessages: 1,
+	}
+
+	mockHandler.EXPECT().
+		OnReadMessages(gomock.Any(), gomock.Any()).
+		Return(errors.New("user handler error"))
+
+	worker.Start(ctx)
+
+	worker.AddMessagesBatch(
+		rawtopiccommon.ServerMessageMetadata{Status: rawydb.StatusSuccess},
+		createTestKeyedBatch(t, session, "a", "b"),
+	)
+
+	xtest.WaitChannelClosed(t, stopped)
+	require.NoError(t, worker.Close(ctx, nil))
+	require.Equal(t, int32(1), stopCalls.Load())
+}
+
 // Note: CommitMessage processing has been moved to streamListener
 // and is no longer handled by PartitionWorker
 
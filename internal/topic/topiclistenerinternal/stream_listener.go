@@ -416,6 +416,20 @@ func (l *streamListener) splitAndRouteReadResponse(m *rawtopicreader.ReadRespons
 			"ydb: failed to convert raw batches to public batches: %w", err)))
 	}
 
+	batches, dropped, droppedBytes, err := topicreadercommon.ApplyMiddlewares(l.cfg.Middlewares, batches)
+	if err != nil {
+		return err
+	}
+	// dropped messages don't pass to handler, commit them without wait of ack
+	for i := range dropped {
+		if err = l.syncCommitter.CommitNoWait(dropped[i]); err != nil {
+			return err
+		}
+	}
+	if droppedBytes > 0 {
+		l.sendDataRequest(droppedBytes)
+	}
+
 	// Route each batch to its partition worker
 	for _, batch := range batches {
 		partitionSession := topicreadercommon.BatchGetPartitionSession(batch)
@@ -585,6 +599,8 @@ func (l *streamListener) createWorkerForPartition(session *topicreadercommon.Par
 		l.tracer,
 		l.listenerID,
 	)
+	worker.deadLetter = l.cfg.deadLetterQueue
+	worker.keyed = l.cfg.KeyedProcessing
 
 	// Store worker in map
 	l.m.WithLock(func() {
@@ -8,6 +8,7 @@ import (
 
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/background"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/empty"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/topic/topicreadercommon"
 )
 
 var (
@@ -43,6 +44,12 @@ func NewTopicListenerReconnector(
 		connectionCompleted: make(empty.Chan),
 	}
 
+	if streamConfig.DeadLetter.Enabled() && streamConfig.deadLetterQueue == nil {
+		deadLetterCfg := streamConfig.DeadLetter
+		deadLetterCfg.Consumer = streamConfig.Consumer
+		streamConfig.deadLetterQueue = topicreadercommon.NewDeadLetterQueue(deadLetterCfg)
+	}
+
 	res.background.Start("connection", res.connect)
 
 	return res, nil
@@ -67,6 +74,10 @@ func (lr *TopicListenerReconnector) Close(ctx context.Context, reason error) err
 		}
 	}
 
+	if lr.streamConfig.deadLetterQueue != nil {
+		closeErrors = append(closeErrors, lr.streamConfig.deadLetterQueue.Close(ctx))
+	}
+
 	return errors.Join(closeErrors...)
 }
 
@@ -224,6 +224,16 @@ func BatchGetPartitionSession(item *PublicBatch) *PartitionSession {
 	return item.partitionSession()
 }
 
+// BatchGetBytesSize returns size of messages content of the batch, as received from server
+func BatchGetBytesSize(b *PublicBatch) int {
+	res := 0
+	for _, mess := range b.Messages {
+		res += mess.rawDataLen
+	}
+
+	return res
+}
+
 func BatchSetCommitRangeForTest(b *PublicBatch, commitRange CommitRange) *PublicBatch {
 	b.commitRange = commitRange
 
@@ -100,7 +100,22 @@ func (c *Committer) Commit(ctx context.Context, commitRange CommitRange) error {
 	return c.waitCommitAck(ctx, waiter)
 }
 
+// CommitNoWait sends commit without wait of ack from server in any commit mode
+func (c *Committer) CommitNoWait(commitRange CommitRange) error {
+	if !c.mode.CommitsEnabled() {
+		return ErrCommitDisabled
+	}
+
+	_, err := c.pushCommitWithWaiter(commitRange, false)
+
+	return err
+}
+
 func (c *Committer) pushCommit(commitRange CommitRange) (commitWaiter, error) {
+	return c.pushCommitWithWaiter(commitRange, c.mode == CommitModeSync)
+}
+
+func (c *Committer) pushCommitWithWaiter(commitRange CommitRange, needWaiter bool) (commitWaiter, error) {
 	var resErr error
 	waiter := newCommitWaiter(commitRange.PartitionSession, commitRange.CommitOffsetEnd)
 	c.m.WithLock(func() {
@@ -111,7 +126,7 @@ func (c *Committer) pushCommit(commitRange CommitRange) (commitWaiter, error) {
 		}
 
 		c.commits.Append(&commitRange)
-		if c.mode == CommitModeSync {
+		if needWaiter {
 			c.addWaiterNeedLock(waiter)
 		}
 	})
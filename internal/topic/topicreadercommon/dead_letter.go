@@ -0,0 +1,295 @@
+package topicreadercommon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+)
+
+// Metadata keys, added to messages written to dead letter topic
+const (
+	DeadLetterMetadataError           = "ydb-dlq-error"
+	DeadLetterMetadataSourceTopic     = "ydb-dlq-source-topic"
+	DeadLetterMetadataSourcePartition = "ydb-dlq-source-partition"
+	DeadLetterMetadataSourceOffset    = "ydb-dlq-source-offset"
+	DeadLetterMetadataConsumer        = "ydb-dlq-consumer"
+	DeadLetterMetadataAttempts        = "ydb-dlq-attempts"
+)
+
+var (
+	errDeadLetterWriterNotSet = xerrors.Wrap(errors.New("ydb: dead letter writer starter not set"))
+	errDeadLetterQueueClosed  = xerrors.Wrap(errors.New("ydb: dead letter queue closed"))
+)
+
+// DeadLetterMessage is message for write to dead letter topic
+type DeadLetterMessage struct {
+	Data      []byte
+	Metadata  map[string][]byte
+	CreatedAt time.Time
+}
+
+// DeadLetterWriter writes messages to dead letter topic.
+// Write must return after the messages acked by server.
+type DeadLetterWriter interface {
+	Write(ctx context.Context, messages []DeadLetterMessage) error
+	Close(ctx context.Context) error
+}
+
+// DeadLetterWriterStarter starts writer to the topic
+type DeadLetterWriterStarter func(topicPath string) (DeadLetterWriter, error)
+
+type DeadLetterConfig struct {
+	// Topic is path of dead letter topic. Dead letter queue disabled if the topic is empty.
+	Topic string
+
+	// MaxAttempts is count of processing failures before message will be moved to dead letter topic
+	MaxAttempts int
+
+	Consumer    string
+	StartWriter DeadLetterWriterStarter
+}
+
+func (cfg *DeadLetterConfig) Enabled() bool {
+	return cfg.Topic != ""
+}
+
+func (cfg *DeadLetterConfig) Validate() error {
+	if !cfg.Enabled() {
+		return nil
+	}
+	if cfg.MaxAttempts <= 0 {
+		return xerrors.WithStackTrace(fmt.Errorf(
+			"ydb: max attempts of dead letter queue must be greater then 0, now: %v", cfg.MaxAttempts,
+		))
+	}
+	if cfg.StartWriter == nil {
+		return xerrors.WithStackTrace(errDeadLetterWriterNotSet)
+	}
+
+	return nil
+}
+
+type deadLetterPartition struct {
+	topic       string
+	partitionID int64
+}
+
+type deadLetterRange struct {
+	start int64
+	end   int64
+}
+
+// DeadLetterQueue counts processing failures of messages and moves messages to dead letter topic
+// after MaxAttempts failures.
+// Counters are keyed by topic, partition and commit range of failed messages and survive reconnects of the reader.
+type DeadLetterQueue struct {
+	cfg DeadLetterConfig
+
+	m        sync.Mutex
+	closed   bool
+	writer   DeadLetterWriter
+	attempts map[deadLetterPartition]map[deadLetterRange]int
+}
+
+func NewDeadLetterQueue(cfg DeadLetterConfig) *DeadLetterQueue {
+	return &DeadLetterQueue{
+		cfg:      cfg,
+		attempts: make(map[deadLetterPartition]map[deadLetterRange]int),
+	}
+}
+
+// OnFailure registers failed processing of the messages with the commit range.
+// It writes the messages to dead letter topic and returns true when the failure is MaxAttempts-th.
+// Caller must commit the messages after that.
+func (q *DeadLetterQueue) OnFailure(
+	ctx context.Context,
+	messages []*PublicMessage,
+	commitRange CommitRange,
+	cause error,
+) (deadLettered bool, _ error) {
+	if len(messages) == 0 || commitRange.PartitionSession == nil {
+		return false, nil
+	}
+
+	partition := deadLetterPartition{
+		topic:       commitRange.PartitionSession.Topic,
+		partitionID: commitRange.PartitionSession.PartitionID,
+	}
+	key := deadLetterRange{
+		start: commitRange.CommitOffsetStart.ToInt64(),
+		end:   commitRange.CommitOffsetEnd.ToInt64(),
+	}
+
+	attempts, writer, err := q.registerFailure(partition, key)
+	if err != nil || attempts < q.cfg.MaxAttempts {
+		return false, err
+	}
+
+	// write without lock: write waits ack from server and must not block failures of other partitions
+	if err = q.write(ctx, writer, messages, cause, attempts); err != nil {
+		return false, xerrors.WithStackTrace(fmt.Errorf(
+			"ydb: failed to write messages to dead letter topic '%s': %w (processing error: %w)",
+			q.cfg.Topic, err, cause,
+		))
+	}
+
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	q.deleteAttemptsNeedLock(partition, key)
+
+	return true, nil
+}
+
+// registerFailure increments failures counter and returns it with dead letter writer, if the counter
+// reached max attempts
+func (q *DeadLetterQueue) registerFailure(partition deadLetterPartition, key deadLetterRange) (
+	attempts int,
+	_ DeadLetterWriter,
+	_ error,
+) {
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	if q.closed {
+		return 0, nil, xerrors.WithStackTrace(errDeadLetterQueueClosed)
+	}
+
+	ranges, ok := q.attempts[partition]
+	if !ok {
+		ranges = make(map[deadLetterRange]int)
+		q.attempts[partition] = ranges
+	}
+	ranges[key]++
+	attempts = ranges[key]
+	if attempts < q.cfg.MaxAttempts {
+		return attempts, nil, nil
+	}
+
+	if q.writer == nil {
+		writer, err := q.cfg.StartWriter(q.cfg.Topic)
+		if err != nil {
+			return 0, nil, xerrors.WithStackTrace(fmt.Errorf(
+				"ydb: failed to start writer to dead letter topic '%s': %w", q.cfg.Topic, err,
+			))
+		}
+		q.writer = writer
+	}
+
+	return attempts, q.writer, nil
+}
+
+func (q *DeadLetterQueue) deleteAttemptsNeedLock(partition deadLetterPartition, key deadLetterRange) {
+	ranges := q.attempts[partition]
+	delete(ranges, key)
+	if len(ranges) == 0 {
+		delete(q.attempts, partition)
+	}
+}
+
+// Forget removes counters of messages, committed by the commit range and ranges before it
+func (q *DeadLetterQueue) Forget(commitRange CommitRange) {
+	session := commitRange.PartitionSession
+	if session == nil {
+		return
+	}
+
+	partition := deadLetterPartition{topic: session.Topic, partitionID: session.PartitionID}
+
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	ranges, ok := q.attempts[partition]
+	if !ok {
+		return
+	}
+	for key := range ranges {
+		if key.end <= commitRange.CommitOffsetEnd.ToInt64() {
+			delete(ranges, key)
+		}
+	}
+	if len(ranges) == 0 {
+		delete(q.attempts, partition)
+	}
+}
+
+func (q *DeadLetterQueue) Close(ctx context.Context) error {
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	if q.closed {
+		return nil
+	}
+	q.closed = true
+
+	if q.writer == nil {
+		return nil
+	}
+
+	return q.writer.Close(ctx)
+}
+
+func (q *DeadLetterQueue) write(
+	ctx context.Context,
+	writer DeadLetterWriter,
+	messages []*PublicMessage,
+	cause error,
+	attempts int,
+) error {
+	dlqMessages := make([]DeadLetterMessage, len(messages))
+	for i, mess := range messages {
+		data, err := messageOriginalData(mess)
+		if err != nil {
+			return err
+		}
+
+		metadata := make(map[string][]byte, len(mess.Metadata)+6) //nolint:gomnd
+		for k, v := range mess.Metadata {
+			metadata[k] = v
+		}
+		metadata[DeadLetterMetadataError] = []byte(cause.Error())
+		metadata[DeadLetterMetadataSourceTopic] = []byte(mess.Topic())
+		metadata[DeadLetterMetadataSourcePartition] = []byte(strconv.FormatInt(mess.PartitionID(), 10))
+		metadata[DeadLetterMetadataSourceOffset] = []byte(strconv.FormatInt(mess.Offset, 10))
+		metadata[DeadLetterMetadataAttempts] = []byte(strconv.Itoa(attempts))
+		if q.cfg.Consumer != "" {
+			metadata[DeadLetterMetadataConsumer] = []byte(q.cfg.Consumer)
+		}
+
+		dlqMessages[i] = DeadLetterMessage{
+			Data:      data,
+			Metadata:  metadata,
+			CreatedAt: mess.CreatedAt,
+		}
+	}
+
+	return writer.Write(ctx, dlqMessages)
+}
+
+// messageOriginalData read uncompressed message content even if the message was read early
+func messageOriginalData(m *PublicMessage) ([]byte, error) {
+	if m.data.readerMaker == nil {
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(m.data.readerMaker())
+	if err != nil {
+		return nil, xerrors.WithStackTrace(err)
+	}
+
+	return data, nil
+}
+
+// BatchResetMessagesData allow to read content of the batch messages again, for redelivery the batch to handler
+func BatchResetMessagesData(b *PublicBatch) {
+	for _, m := range b.Messages {
+		m.data = newOneTimeReader(m.data.readerMaker)
+		m.dataConsumed = false
+	}
+}
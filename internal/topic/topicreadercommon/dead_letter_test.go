@@ -0,0 +1,229 @@
+package topicreadercommon
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/empty"
+	"github.com/ydb-platform/ydb-go-sdk/v3/pkg/xtest"
+)
+
+type testDeadLetterWriter struct {
+	topic    string
+	messages []DeadLetterMessage
+	closed   bool
+}
+
+func (w *testDeadLetterWriter) Write(_ context.Context, messages []DeadLetterMessage) error {
+	w.messages = append(w.messages, messages...)
+
+	return nil
+}
+
+func (w *testDeadLetterWriter) Close(context.Context) error {
+	w.closed = true
+
+	return nil
+}
+
+type blockingDeadLetterWriter struct {
+	started empty.Chan
+	unblock empty.Chan
+}
+
+func (w *blockingDeadLetterWriter) Write(ctx context.Context, _ []DeadLetterMessage) error {
+	close(w.started)
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-w.unblock:
+		return nil
+	}
+}
+
+func (w *blockingDeadLetterWriter) Close(context.Context) error {
+	return nil
+}
+
+func newTestDeadLetterQueue(maxAttempts int) (*DeadLetterQueue, *testDeadLetterWriter) {
+	writer := &testDeadLetterWriter{}
+	q := NewDeadLetterQueue(DeadLetterConfig{
+		Topic:       "dlq",
+		MaxAttempts: maxAttempts,
+		Consumer:    "consumer",
+		StartWriter: func(topicPath string) (DeadLetterWriter, error) {
+			writer.topic = topicPath
+
+			return writer, nil
+		},
+	})
+
+	return q, writer
+}
+
+func newDeadLetterTestBatch(offset int64, data ...string) *PublicBatch {
+	builder := NewPublicBatchBuilder("topic", 2)
+	for i := range data {
+		builder.Messages(NewPublicMessageBuilder().
+			Offset(offset + int64(i)).
+			DataAndUncompressedSize([]byte(data[i])).
+			Metadata(map[string][]byte{"key": []byte("value")}),
+		)
+	}
+
+	return builder.Build()
+}
+
+func TestDeadLetterQueue(t *testing.T) {
+	ctx := xtest.Context(t)
+	cause := errors.New("test error")
+
+	t.Run("WriteAfterMaxAttempts", func(t *testing.T) {
+		q, writer := newTestDeadLetterQueue(3)
+		batch := newDeadLetterTestBatch(10, "a", "b")
+
+		// consume content, as user handler does
+		_, err := io.ReadAll(batch.Messages[0])
+		require.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			deadLettered, err := q.OnFailure(ctx, batch.Messages, GetCommitRange(batch), cause)
+			require.NoError(t, err)
+			require.False(t, deadLettered)
+		}
+		require.Empty(t, writer.messages)
+
+		deadLettered, err := q.OnFailure(ctx, batch.Messages, GetCommitRange(batch), cause)
+		require.NoError(t, err)
+		require.True(t, deadLettered)
+		require.Equal(t, "dlq", writer.topic)
+		require.Len(t, writer.messages, 2)
+		require.Equal(t, []byte("a"), writer.messages[0].Data)
+		require.Equal(t, []byte("b"), writer.messages[1].Data)
+		require.Equal(t, map[string][]byte{
+			"key":                             []byte("value"),
+			DeadLetterMetadataError:           []byte("test error"),
+			DeadLetterMetadataSourceTopic:     []byte("topic"),
+			DeadLetterMetadataSourcePartition: []byte("2"),
+			DeadLetterMetadataSourceOffset:    []byte("11"),
+			DeadLetterMetadataConsumer:        []byte("consumer"),
+			DeadLetterMetadataAttempts:        []byte("3"),
+		}, writer.messages[1].Metadata)
+
+		// counter reset after dead letter
+		deadLettered, err = q.OnFailure(ctx, batch.Messages, GetCommitRange(batch), cause)
+		require.NoError(t, err)
+		require.False(t, deadLettered)
+
+		require.NoError(t, q.Close(ctx))
+		require.True(t, writer.closed)
+		_, err = q.OnFailure(ctx, batch.Messages, GetCommitRange(batch), cause)
+		require.ErrorIs(t, err, errDeadLetterQueueClosed)
+	})
+
+	t.Run("CountersSurviveNewSession", func(t *testing.T) {
+		q, writer := newTestDeadLetterQueue(2)
+
+		batch := newDeadLetterTestBatch(10, "a")
+		deadLettered, err := q.OnFailure(ctx, batch.Messages, GetCommitRange(batch), cause)
+		require.NoError(t, err)
+		require.False(t, deadLettered)
+
+		// same message received again after reconnect
+		batch = newDeadLetterTestBatch(10, "a")
+		deadLettered, err = q.OnFailure(ctx, batch.Messages, GetCommitRange(batch), cause)
+		require.NoError(t, err)
+		require.True(t, deadLettered)
+		require.Len(t, writer.messages, 1)
+	})
+
+	t.Run("CountersByCommitRange", func(t *testing.T) {
+		q, writer := newTestDeadLetterQueue(2)
+		long := newDeadLetterTestBatch(10, "a", "b")
+		short := newDeadLetterTestBatch(10, "a")
+
+		// batches with same first offset and other messages are counted separately
+		deadLettered, err := q.OnFailure(ctx, long.Messages, GetCommitRange(long), cause)
+		require.NoError(t, err)
+		require.False(t, deadLettered)
+		deadLettered, err = q.OnFailure(ctx, short.Messages, GetCommitRange(short), cause)
+		require.NoError(t, err)
+		require.False(t, deadLettered)
+		require.Empty(t, writer.messages)
+	})
+
+	t.Run("WriteWithoutLock", func(t *testing.T) {
+		writer := &blockingDeadLetterWriter{started: make(empty.Chan), unblock: make(empty.Chan)}
+		q := NewDeadLetterQueue(DeadLetterConfig{
+			Topic:       "dlq",
+			MaxAttempts: 1,
+			StartWriter: func(string) (DeadLetterWriter, error) {
+				return writer, nil
+			},
+		})
+		batch := newDeadLetterTestBatch(10, "a")
+
+		written := make(empty.Chan)
+		go func() {
+			defer close(written)
+
+			_, _ = q.OnFailure(ctx, batch.Messages, GetCommitRange(batch), cause)
+		}()
+		xtest.WaitChannelClosed(t, writer.started)
+
+		// other partitions are not blocked by write to dead letter topic
+		other := newDeadLetterTestBatch(20, "b")
+		q.Forget(GetCommitRange(other))
+
+		close(writer.unblock)
+		xtest.WaitChannelClosed(t, written)
+	})
+
+	t.Run("Forget", func(t *testing.T) {
+		q, writer := newTestDeadLetterQueue(2)
+		first := newDeadLetterTestBatch(10, "a")
+		second := newDeadLetterTestBatch(11, "b")
+
+		for _, b := range []*PublicBatch{first, second} {
+			_, err := q.OnFailure(ctx, b.Messages, GetCommitRange(b), cause)
+			require.NoError(t, err)
+		}
+
+		q.Forget(GetCommitRange(first))
+
+		deadLettered, err := q.OnFailure(ctx, first.Messages, GetCommitRange(first), cause)
+		require.NoError(t, err)
+		require.False(t, deadLettered)
+
+		deadLettered, err = q.OnFailure(ctx, second.Messages, GetCommitRange(second), cause)
+		require.NoError(t, err)
+		require.True(t, deadLettered)
+		require.Len(t, writer.messages, 1)
+		require.Equal(t, []byte("b"), writer.messages[0].Data)
+	})
+}
+
+func TestBatchResetMessagesData(t *testing.T) {
+	batch := newDeadLetterTestBatch(10, "content")
+
+	data, err := io.ReadAll(batch.Messages[0])
+	require.NoError(t, err)
+	require.Equal(t, "content", string(data))
+
+	BatchResetMessagesData(batch)
+
+	data, err = io.ReadAll(batch.Messages[0])
+	require.NoError(t, err)
+	require.Equal(t, "content", string(data))
+}
+
+func TestDeadLetterConfigValidate(t *testing.T) {
+	require.NoError(t, (&DeadLetterConfig{}).Validate())
+	require.Error(t, (&DeadLetterConfig{Topic: "dlq"}).Validate())
+	require.ErrorIs(t, (&DeadLetterConfig{Topic: "dlq", MaxAttempts: 1}).Validate(), errDeadLetterWriterNotSet)
+}
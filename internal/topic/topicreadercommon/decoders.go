@@ -5,6 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
 
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/grpcwrapper/rawtopic/rawtopiccommon"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
@@ -23,10 +27,16 @@ func NewDecoderMap() DecoderMap {
 			rawtopiccommon.CodecGzip: func(input io.Reader) (io.Reader, error) {
 				return gzip.NewReader(input)
 			},
+			rawtopiccommon.CodecZstd: newZstdDecoder,
 		},
 	}
 }
 
+// NewLz4Decoder creates lz4 decoder for custom codec, lz4 has no code in the protocol
+func NewLz4Decoder(input io.Reader) (io.Reader, error) {
+	return lz4.NewReader(input), nil
+}
+
 func (m *DecoderMap) AddDecoder(codec rawtopiccommon.Codec, createFunc PublicCreateDecoderFunc) {
 	m.m[codec] = createFunc
 }
@@ -41,6 +51,46 @@ func (m *DecoderMap) Decode(codec rawtopiccommon.Codec, input io.Reader) (io.Rea
 	))
 }
 
+var zstdDecoders sync.Pool
+
+// zstdDecoder returns decoder state to the pool after read message content to the end
+type zstdDecoder struct {
+	decoder *zstd.Decoder
+}
+
+func newZstdDecoder(input io.Reader) (io.Reader, error) {
+	decoder, _ := zstdDecoders.Get().(*zstd.Decoder)
+	if decoder == nil {
+		var err error
+
+		// with concurrency 1 the decoder works synchronously without background goroutines,
+		// then decoders of not fully read messages can be collected without close
+		decoder, err = zstd.NewReader(input, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, xerrors.WithStackTrace(err)
+		}
+	} else if err := decoder.Reset(input); err != nil {
+		return nil, xerrors.WithStackTrace(err)
+	}
+
+	return &zstdDecoder{decoder: decoder}, nil
+}
+
+func (d *zstdDecoder) Read(p []byte) (int, error) {
+	if d.decoder == nil {
+		return 0, io.EOF
+	}
+
+	n, err := d.decoder.Read(p)
+	if errors.Is(err, io.EOF) {
+		_ = d.decoder.Reset(nil)
+		zstdDecoders.Put(d.decoder)
+		d.decoder = nil
+	}
+
+	return n, err
+}
+
 type PublicCreateDecoderFunc func(input io.Reader) (io.Reader, error)
 
 // ErrPublicUnexpectedCodec return when try to read message content with unknown codec
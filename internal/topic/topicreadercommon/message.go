@@ -26,7 +26,7 @@ type PublicMessage struct {
 	Offset               int64
 	WrittenAt            time.Time
 	ProducerID           string
-	Metadata             map[string][]byte // Metadata, nil if no metadata
+	Metadata             map[string][]byte // Metadata, nil if no metadata. Use topictypes.Metadata for typed values
 
 	commitRange        CommitRange
 	data               oneTimeReader
@@ -197,7 +197,7 @@ func (pmb *PublicMessageBuilder) ProducerID(producerID string) *PublicMessageBui
 func (pmb *PublicMessageBuilder) DataAndUncompressedSize(data []byte) *PublicMessageBuilder {
 	copyData := make([]byte, len(data))
 	copy(copyData, data)
-	pmb.mess.data = oneTimeReader{reader: bytes.NewReader(data)}
+	pmb.mess.data = createReader(NewDecoderMap(), rawtopiccommon.CodecRaw, copyData)
 	pmb.mess.dataConsumed = false
 	pmb.mess.rawDataLen = len(copyData)
 	pmb.mess.UncompressedSize = len(copyData)
@@ -259,6 +259,80 @@ func (pmb *PublicMessageBuilder) Build() *PublicMessage {
 	return mess
 }
 
+// PublicBatchBuilder create batch of messages of one partition session (use for tests only)
+type PublicBatchBuilder struct {
+	session  *PartitionSession
+	messages []*PublicMessage
+}
+
+func NewPublicBatchBuilder(topic string, partitionID int64) *PublicBatchBuilder {
+	return &PublicBatchBuilder{
+		session: NewPartitionSession(context.Background(), topic, partitionID, 0, "", 0, 0, 0),
+	}
+}
+
+// PartitionSession set partition session of the batch, use it for create several batches of one session
+func (pbb *PublicBatchBuilder) PartitionSession(session *PartitionSession) *PublicBatchBuilder {
+	pbb.session = session
+
+	return pbb
+}
+
+// Offsets add messages with the offsets and empty content
+func (pbb *PublicBatchBuilder) Offsets(offsets ...int64) *PublicBatchBuilder {
+	for _, offset := range offsets {
+		pbb.messages = append(pbb.messages, NewPublicMessageBuilder().Offset(offset).Build())
+	}
+
+	return pbb
+}
+
+// Messages add messages of the builders
+func (pbb *PublicBatchBuilder) Messages(messages ...*PublicMessageBuilder) *PublicBatchBuilder {
+	for _, mess := range messages {
+		pbb.messages = append(pbb.messages, mess.Build())
+	}
+
+	return pbb
+}
+
+// Build return builded batch, it panics if offsets of messages are not sequential
+func (pbb *PublicBatchBuilder) Build() *PublicBatch {
+	for _, mess := range pbb.messages {
+		mess.commitRange.PartitionSession = pbb.session
+	}
+	batch, err := NewBatch(pbb.session, pbb.messages)
+	if err != nil {
+		panic(err)
+	}
+	pbb.messages = nil
+
+	return batch
+}
+
+// MessagesMetadata gives access to metadata of the messages for tracing
+type MessagesMetadata []*PublicMessage
+
+func (m MessagesMetadata) MetadataValues(key string) [][]byte {
+	var res [][]byte
+	for _, mess := range m {
+		if value, ok := mess.Metadata[key]; ok {
+			res = append(res, value)
+		}
+	}
+
+	return res
+}
+
+func (m MessagesMetadata) MetadataSize() int {
+	size := 0
+	for _, mess := range m {
+		size += rawtopiccommon.MetadataSize(mess.Metadata)
+	}
+
+	return size
+}
+
 func MessageGetBufferBytesAccount(m *PublicMessage) int {
 	return m.bufferBytesAccount
 }
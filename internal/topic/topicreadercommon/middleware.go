@@ -0,0 +1,297 @@
+package topicreadercommon
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/backoff"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xsync"
+)
+
+// EnvelopeKeyIDMetadataKey is metadata key of encrypted message with id of the key for decrypt the message
+const EnvelopeKeyIDMetadataKey = "__ydb_envelope_key_id"
+
+var errEnvelopeTooShort = xerrors.Wrap(errors.New("ydb: encrypted message is shorter then nonce"))
+
+// PublicMiddleware called for every message after receive from server and before the message will be
+// returned to user code. The middleware can change metadata and content (see MessageSetData) of the message.
+// Return keep=false for drop the message: dropped message doesn't return to user code, it will be committed
+// together with previous message of the batch or by reader itself, if the message is first in the batch.
+// Error of the middleware stops the reader, middleware must retry temporary errors itself.
+//
+// Middlewares called from goroutine, which receives messages from server: slow middleware delays
+// receive of all partitions of the reader. Avoid blocking calls for every message, cache results of them.
+//
+// Content of the message can be read once, middleware which reads the content must set it back by MessageSetData.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type PublicMiddleware func(ctx context.Context, message *PublicMessage) (keep bool, err error)
+
+// PublicEnvelopeKeyProvider returns key for decrypt messages by id of the key.
+// Keys cached by EnvelopeDecrypt, then provider called once for every key id.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type PublicEnvelopeKeyProvider interface {
+	EnvelopeKey(ctx context.Context, keyID string) ([]byte, error)
+}
+
+// MetadataFilter returns middleware, which keep messages with metadata value equal to one of values
+// and drop other messages.
+func MetadataFilter(key string, values ...string) PublicMiddleware {
+	return func(_ context.Context, message *PublicMessage) (keep bool, err error) {
+		value, ok := message.Metadata[key]
+		if !ok {
+			return false, nil
+		}
+		for _, v := range values {
+			if string(value) == v {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	}
+}
+
+// EnvelopeDecrypt returns middleware, which decrypts content of messages with EnvelopeKeyIDMetadataKey metadata.
+// Content of the encrypted message is AES-GCM nonce, followed by ciphertext. Messages without the metadata
+// pass without changes.
+// Keys cached by key id for lifetime of the middleware, provider called on first message with the key id only.
+func EnvelopeDecrypt(keys PublicEnvelopeKeyProvider) PublicMiddleware {
+	cache := &envelopeKeyCache{
+		provider: keys,
+		keys:     make(map[string][]byte),
+	}
+
+	return func(ctx context.Context, message *PublicMessage) (keep bool, err error) {
+		keyID, ok := message.Metadata[EnvelopeKeyIDMetadataKey]
+		if !ok {
+			return true, nil
+		}
+
+		key, err := cache.get(ctx, string(keyID))
+		if err != nil {
+			return false, err
+		}
+
+		encrypted, err := io.ReadAll(message)
+		if err != nil {
+			return false, err
+		}
+
+		data, err := envelopeOpen(key, encrypted)
+		if err != nil {
+			return false, xerrors.WithStackTrace(fmt.Errorf(
+				"ydb: failed to decrypt message with offset %v: %w", message.Offset, err,
+			))
+		}
+		MessageSetData(message, data)
+
+		return true, nil
+	}
+}
+
+type envelopeKeyCache struct {
+	provider PublicEnvelopeKeyProvider
+
+	m    xsync.Mutex
+	keys map[string][]byte
+}
+
+func (c *envelopeKeyCache) get(ctx context.Context, keyID string) ([]byte, error) {
+	var (
+		key []byte
+		ok  bool
+	)
+	c.m.WithLock(func() {
+		key, ok = c.keys[keyID]
+	})
+	if ok {
+		return key, nil
+	}
+
+	key, err := c.provider.EnvelopeKey(ctx, keyID)
+	if err != nil {
+		return nil, xerrors.WithStackTrace(fmt.Errorf("ydb: failed to get envelope key %q: %w", keyID, err))
+	}
+
+	c.m.WithLock(func() {
+		c.keys[keyID] = key
+	})
+
+	return key, nil
+}
+
+// EnvelopeSeal encrypts data in format of EnvelopeDecrypt
+func EnvelopeSeal(key, nonce, data []byte) ([]byte, error) {
+	gcm, err := newEnvelopeCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, xerrors.WithStackTrace(fmt.Errorf(
+			"ydb: bad nonce size for envelope: %v, expected: %v", len(nonce), gcm.NonceSize(),
+		))
+	}
+
+	res := make([]byte, len(nonce), len(nonce)+len(data)+gcm.Overhead())
+	copy(res, nonce)
+
+	return gcm.Seal(res, nonce, data, nil), nil
+}
+
+func envelopeOpen(key, encrypted []byte) ([]byte, error) {
+	gcm, err := newEnvelopeCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(encrypted) < gcm.NonceSize() {
+		return nil, xerrors.WithStackTrace(errEnvelopeTooShort)
+	}
+	nonce, ciphertext := encrypted[:gcm.NonceSize()], encrypted[gcm.NonceSize():]
+
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func newEnvelopeCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, xerrors.WithStackTrace(err)
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// MessageSetData replace content of the message
+func MessageSetData(m *PublicMessage, data []byte) {
+	m.data = newOneTimeReaderFromReader(bytes.NewReader(data))
+	m.dataConsumed = false
+	m.UncompressedSize = len(data)
+}
+
+// ApplyMiddlewares calls middlewares for messages of the batches and removes dropped messages.
+// Messages before offset of SkipMessagesBefore of the partition session are dropped without call of middlewares.
+// Commit range of dropped message joins to previous message of the batch. Dropped messages from start
+// of the batch returned as dropped ranges, caller must commit them without wait of user code.
+// Returns batches with messages, dropped ranges and size of buffer, used by messages of dropped ranges.
+func ApplyMiddlewares(
+	middlewares []PublicMiddleware,
+	batches []*PublicBatch,
+) (_ []*PublicBatch, dropped []CommitRange, freeBytes int, _ error) {
+	if len(middlewares) == 0 && !hasSkippedMessages(batches) {
+		return batches, nil, 0, nil
+	}
+
+	res := batches[:0]
+	for _, batch := range batches {
+		session := batch.partitionSession()
+		messages := make([]*PublicMessage, 0, len(batch.Messages))
+
+		var droppedRange *CommitRange
+		for _, message := range batch.Messages {
+			keep := message.Offset >= session.skipBefore()
+			if keep {
+				var err error
+				if keep, err = callMiddlewares(middlewares, message); err != nil {
+					return nil, nil, 0, err
+				}
+			}
+
+			switch {
+			case keep:
+				messages = append(messages, message)
+			case len(messages) > 0:
+				last := messages[len(messages)-1]
+				last.commitRange.CommitOffsetEnd = message.commitRange.CommitOffsetEnd
+				last.bufferBytesAccount += message.bufferBytesAccount
+			case droppedRange == nil:
+				commitRange := message.commitRange
+				commitRange.PartitionSession = session
+				droppedRange = &commitRange
+				freeBytes += message.bufferBytesAccount
+			default:
+				droppedRange.CommitOffsetEnd = message.commitRange.CommitOffsetEnd
+				freeBytes += message.bufferBytesAccount
+			}
+		}
+
+		if droppedRange != nil {
+			// remember before return of the batch to user code, for check order of user commits
+			session.addDroppedRange(*droppedRange)
+			dropped = append(dropped, *droppedRange)
+		}
+
+		if len(messages) == 0 {
+			continue
+		}
+
+		filtered, err := NewBatch(session, messages)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		res = append(res, filtered)
+	}
+
+	return res, dropped, freeBytes, nil
+}
+
+func hasSkippedMessages(batches []*PublicBatch) bool {
+	for _, batch := range batches {
+		if len(batch.Messages) > 0 && batch.Messages[0].Offset < batch.partitionSession().skipBefore() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// middlewareError is not retryable for the reader even if error of the middleware is retryable,
+// it is checked before nested errors by retry.Check
+type middlewareError struct {
+	err error
+}
+
+func (e *middlewareError) Error() string {
+	return "ydb: topic reader middleware failed: " + e.err.Error()
+}
+
+func (e *middlewareError) Unwrap() error {
+	return e.err
+}
+
+func (e *middlewareError) Code() int32 {
+	return -1
+}
+
+func (e *middlewareError) Name() string {
+	return "TopicReaderMiddleware"
+}
+
+func (e *middlewareError) Type() xerrors.Type {
+	return xerrors.TypeNonRetryable
+}
+
+func (e *middlewareError) BackoffType() backoff.Type {
+	return backoff.TypeNoBackoff
+}
+
+func callMiddlewares(middlewares []PublicMiddleware, message *PublicMessage) (bool, error) {
+	for _, middleware := range middlewares {
+		keep, err := middleware(message.Context(), message)
+		if err != nil {
+			// same message fails after reconnect again, stop instead of infinite reconnects
+			return false, xerrors.WithStackTrace(&middlewareError{err: err})
+		}
+		if !keep {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
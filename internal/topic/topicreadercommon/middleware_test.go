@@ -0,0 +1,207 @@
+package topicreadercommon
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/grpcwrapper/rawtopic/rawtopiccommon"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+	"github.com/ydb-platform/ydb-go-sdk/v3/pkg/xtest"
+)
+
+func newMiddlewareTestBatch(session *PartitionSession, offset int64, metadata ...map[string]string) *PublicBatch {
+	builder := NewPublicBatchBuilder(session.Topic, session.PartitionID).PartitionSession(session)
+	for i := range metadata {
+		mess := NewPublicMessageBuilder().Offset(offset + int64(i)).DataAndUncompressedSize([]byte("data"))
+		if metadata[i] != nil {
+			items := make(map[string][]byte, len(metadata[i]))
+			for k, v := range metadata[i] {
+				items[k] = []byte(v)
+			}
+			mess.Metadata(items)
+		}
+		builder.Messages(mess)
+	}
+
+	return builder.Build()
+}
+
+type testEnvelopeKeys struct {
+	keys  map[string][]byte
+	calls atomic.Int32
+}
+
+func (k *testEnvelopeKeys) EnvelopeKey(_ context.Context, keyID string) ([]byte, error) {
+	k.calls.Add(1)
+	key, ok := k.keys[keyID]
+	if !ok {
+		return nil, errors.New("unknown key")
+	}
+
+	return key, nil
+}
+
+func TestApplyMiddlewares(t *testing.T) {
+	t.Run("DropMessages", func(t *testing.T) {
+		session := NewPartitionSession(context.Background(), "topic", 1, 0, "", 0, 0, rawtopiccommon.NewOffset(10))
+		keep := map[string]string{"type": "keep"}
+		skip := map[string]string{"type": "skip"}
+		batches := []*PublicBatch{
+			newMiddlewareTestBatch(session, 10, keep, skip, nil),
+			newMiddlewareTestBatch(session, 13, skip, skip),
+			newMiddlewareTestBatch(session, 15, skip, keep),
+		}
+		require.NoError(t, splitBytesByMessagesInBatches(batches, 70))
+
+		res, dropped, freeBytes, err := ApplyMiddlewares([]PublicMiddleware{MetadataFilter("type", "keep")}, batches)
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+
+		// dropped messages in tail of batch committed with previous message
+		require.Len(t, res[0].Messages, 1)
+		require.Equal(t, int64(10), res[0].Messages[0].Offset)
+		require.Equal(t, rawtopiccommon.Offset(10), res[0].commitRange.CommitOffsetStart)
+		require.Equal(t, rawtopiccommon.Offset(13), res[0].commitRange.CommitOffsetEnd)
+		require.Equal(t, 30, MessageGetBufferBytesAccount(res[0].Messages[0]))
+
+		// dropped messages in head of batch returned for commit without user code
+		require.Len(t, res[1].Messages, 1)
+		require.Equal(t, int64(16), res[1].Messages[0].Offset)
+		require.Equal(t, rawtopiccommon.Offset(16), res[1].commitRange.CommitOffsetStart)
+		require.Equal(t, rawtopiccommon.Offset(17), res[1].commitRange.CommitOffsetEnd)
+		require.Equal(t, 10, MessageGetBufferBytesAccount(res[1].Messages[0]))
+
+		require.Equal(t, []CommitRange{
+			{CommitOffsetStart: 13, CommitOffsetEnd: 15, PartitionSession: session},
+			{CommitOffsetStart: 15, CommitOffsetEnd: 16, PartitionSession: session},
+		}, dropped)
+		require.Equal(t, 30, freeBytes)
+
+		// user code commits messages in order, dropped ranges are skipped
+		require.Equal(t, rawtopiccommon.Offset(10), session.NextCommitOffset())
+		session.SetCommittedOffsetForward(13)
+		require.Equal(t, rawtopiccommon.Offset(16), session.NextCommitOffset())
+		session.SetCommittedOffsetForward(17)
+		require.Equal(t, rawtopiccommon.Offset(17), session.NextCommitOffset())
+		require.Empty(t, session.droppedRanges)
+	})
+	t.Run("DropAll", func(t *testing.T) {
+		session := NewPartitionSession(context.Background(), "topic", 1, 0, "", 0, 0, rawtopiccommon.NewOffset(5))
+		dropAll := func(context.Context, *PublicMessage) (bool, error) { return false, nil }
+
+		res, dropped, _, err := ApplyMiddlewares(
+			[]PublicMiddleware{dropAll},
+			[]*PublicBatch{newMiddlewareTestBatch(session, 5, nil, nil)},
+		)
+		require.NoError(t, err)
+		require.Empty(t, res)
+		require.Equal(t, []CommitRange{
+			{CommitOffsetStart: 5, CommitOffsetEnd: 7, PartitionSession: session},
+		}, dropped)
+		require.Equal(t, rawtopiccommon.Offset(7), session.NextCommitOffset())
+	})
+	t.Run("ChangeMessage", func(t *testing.T) {
+		session := NewPartitionSession(context.Background(), "topic", 1, 0, "", 0, 0, rawtopiccommon.NewOffset(0))
+		res, _, _, err := ApplyMiddlewares([]PublicMiddleware{
+			func(_ context.Context, message *PublicMessage) (bool, error) {
+				message.Metadata = map[string][]byte{"changed": []byte("1")}
+				MessageSetData(message, []byte("replaced"))
+
+				return true, nil
+			},
+		}, []*PublicBatch{newMiddlewareTestBatch(session, 0, nil)})
+		require.NoError(t, err)
+
+		data, err := io.ReadAll(res[0].Messages[0])
+		require.NoError(t, err)
+		require.Equal(t, "replaced", string(data))
+		require.Equal(t, []byte("1"), res[0].Messages[0].Metadata["changed"])
+	})
+	t.Run("Error", func(t *testing.T) {
+		session := NewPartitionSession(context.Background(), "topic", 1, 0, "", 0, 0, rawtopiccommon.NewOffset(0))
+		testErr := xerrors.Retryable(errors.New("test"))
+		_, _, _, err := ApplyMiddlewares([]PublicMiddleware{
+			func(context.Context, *PublicMessage) (bool, error) { return false, testErr },
+		}, []*PublicBatch{newMiddlewareTestBatch(session, 0, nil)})
+		require.ErrorIs(t, err, testErr)
+
+		// the message fails after reconnect again
+		_, errType, _ := xerrors.Check(err)
+		require.Equal(t, xerrors.TypeNonRetryable, errType)
+	})
+}
+
+func TestEnvelopeDecrypt(t *testing.T) {
+	ctx := xtest.Context(t)
+	key := []byte("0123456789abcdef0123456789abcdef")
+	nonce := []byte("unique nonce")
+	keys := &testEnvelopeKeys{keys: map[string][]byte{"k1": key}}
+	middleware := EnvelopeDecrypt(keys)
+
+	t.Run("Decrypt", func(t *testing.T) {
+		encrypted, err := EnvelopeSeal(key, nonce, []byte("secret"))
+		require.NoError(t, err)
+		require.NotContains(t, string(encrypted), "secret")
+
+		message := NewPublicMessageBuilder().
+			Metadata(map[string][]byte{EnvelopeKeyIDMetadataKey: []byte("k1")}).
+			DataAndUncompressedSize(encrypted).
+			Build()
+		keep, err := middleware(ctx, message)
+		require.NoError(t, err)
+		require.True(t, keep)
+
+		data, err := io.ReadAll(message)
+		require.NoError(t, err)
+		require.Equal(t, "secret", string(data))
+	})
+	t.Run("KeyCached", func(t *testing.T) {
+		calls := keys.calls.Load()
+		for i := 0; i < 3; i++ {
+			encrypted, err := EnvelopeSeal(key, nonce, []byte("secret"))
+			require.NoError(t, err)
+
+			message := NewPublicMessageBuilder().
+				Metadata(map[string][]byte{EnvelopeKeyIDMetadataKey: []byte("k1")}).
+				DataAndUncompressedSize(encrypted).
+				Build()
+			_, err = middleware(ctx, message)
+			require.NoError(t, err)
+		}
+		require.Equal(t, calls, keys.calls.Load())
+	})
+	t.Run("NotEncrypted", func(t *testing.T) {
+		message := NewPublicMessageBuilder().DataAndUncompressedSize([]byte("plain")).Build()
+		keep, err := middleware(ctx, message)
+		require.NoError(t, err)
+		require.True(t, keep)
+
+		data, err := io.ReadAll(message)
+		require.NoError(t, err)
+		require.Equal(t, "plain", string(data))
+	})
+	t.Run("UnknownKey", func(t *testing.T) {
+		message := NewPublicMessageBuilder().
+			Metadata(map[string][]byte{EnvelopeKeyIDMetadataKey: []byte("k2")}).
+			DataAndUncompressedSize([]byte("data")).
+			Build()
+		_, err := middleware(ctx, message)
+		require.Error(t, err)
+	})
+	t.Run("WrongKey", func(t *testing.T) {
+		encrypted, err := EnvelopeSeal([]byte("fedcba9876543210fedcba9876543210"), nonce, []byte("secret"))
+		require.NoError(t, err)
+
+		message := NewPublicMessageBuilder().
+			Metadata(map[string][]byte{EnvelopeKeyIDMetadataKey: []byte("k1")}).
+			DataAndUncompressedSize(encrypted).
+			Build()
+		_, err = middleware(ctx, message)
+		require.Error(t, err)
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/grpcwrapper/rawtopic/rawtopiccommon"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/grpcwrapper/rawtopic/rawtopicreader"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xcontext"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xsync"
 )
 
 type PartitionSession struct {
@@ -24,6 +25,14 @@ type PartitionSession struct {
 	lastReceivedOffsetEndVal atomic.Int64
 	committedOffsetVal       atomic.Int64
 	noMoreMessages           atomic.Bool
+
+	// messages before the offset are dropped on receive, it is set by forward seek of the partition
+	skipBeforeVal atomic.Int64
+
+	// ranges of messages, dropped by middlewares and committed by reader without user code.
+	// Ranges removed when committed offset moves over them.
+	droppedMutex  xsync.Mutex
+	droppedRanges []CommitRange
 }
 
 func NewPartitionSession(
@@ -107,6 +116,46 @@ func (s *PartitionSession) SetInitialCommitOffset(committedOffset rawtopiccommon
 	s.lastReceivedOffsetEndVal.Store(committedOffset.ToInt64() - 1)
 }
 
+// SkipMessagesBefore drops messages of the session with offset less then the offset, see ApplyMiddlewares
+func (s *PartitionSession) SkipMessagesBefore(offset int64) {
+	s.skipBeforeVal.Store(offset)
+}
+
+func (s *PartitionSession) skipBefore() int64 {
+	return s.skipBeforeVal.Load()
+}
+
+// addDroppedRange remember range of messages, dropped by middlewares
+func (s *PartitionSession) addDroppedRange(commitRange CommitRange) {
+	s.droppedMutex.WithLock(func() {
+		s.droppedRanges = append(s.droppedRanges, commitRange)
+	})
+}
+
+// NextCommitOffset returns offset, from which next commit of user code starts:
+// committed offset, moved over ranges of messages dropped by middlewares.
+// Dropped ranges committed without wait of ack, then they may be not committed on server yet.
+func (s *PartitionSession) NextCommitOffset() rawtopiccommon.Offset {
+	committed := s.CommittedOffset()
+	res := committed
+
+	s.droppedMutex.WithLock(func() {
+		actual := s.droppedRanges[:0]
+		for _, dropped := range s.droppedRanges {
+			if dropped.CommitOffsetEnd <= committed {
+				continue
+			}
+			actual = append(actual, dropped)
+			if dropped.CommitOffsetStart <= res && res < dropped.CommitOffsetEnd {
+				res = dropped.CommitOffsetEnd
+			}
+		}
+		s.droppedRanges = actual
+	})
+
+	return res
+}
+
 func (s *PartitionSession) NoMoreMessages() bool {
 	return s.noMoreMessages.Load()
 }
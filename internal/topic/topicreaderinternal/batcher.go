@@ -97,6 +97,72 @@ func (b *batcher) FlushPartitionSession(session *topicreadercommon.PartitionSess
 	})
 }
 
+// SkipSessionMessagesBefore drops buffered messages of the session with offset less then the offset
+// and skips such messages on receive. Returns false without changes if messages after the offset
+// were popped from the batcher already.
+func (b *batcher) SkipSessionMessagesBefore(session *topicreadercommon.PartitionSession, offset int64) (
+	dropped []topicreadercommon.CommitRange,
+	freeBytes int,
+	ok bool,
+	_ error,
+) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if b.closed {
+		return nil, 0, false, xerrors.WithStackTrace(fmt.Errorf("ydb: skip messages in closed batcher: %w", b.closeErr))
+	}
+
+	items := b.messages[session]
+	nextOffset := session.LastReceivedMessageOffset().ToInt64() + 1
+	for i := range items {
+		if items[i].IsBatch() {
+			nextOffset = items[i].Batch.Messages[0].Offset
+
+			break
+		}
+	}
+	if offset < nextOffset {
+		return nil, 0, false, nil
+	}
+
+	session.SkipMessagesBefore(offset)
+
+	rest := make(batcherMessageOrderItems, 0, len(items))
+	for _, item := range items {
+		if item.IsBatch() {
+			batches, itemDropped, itemFreeBytes, err := topicreadercommon.ApplyMiddlewares(
+				nil,
+				[]*topicreadercommon.PublicBatch{item.Batch},
+			)
+			if err != nil {
+				return nil, 0, false, err
+			}
+			dropped = append(dropped, itemDropped...)
+			freeBytes += itemFreeBytes
+			if len(batches) == 0 {
+				continue
+			}
+			item = newBatcherItemBatch(batches[0])
+		}
+		rest = append(rest, item)
+	}
+
+	switch {
+	case len(items) == 0:
+		// pass
+	case len(rest) == 0:
+		delete(b.messages, session)
+		b.sessionsForFlush = xslices.Filter(b.sessionsForFlush, func(s *topicreadercommon.PartitionSession) bool {
+			return s != session
+		})
+	default:
+		b.messages[session] = rest
+	}
+
+	return dropped, freeBytes, true, nil
+}
+
 func (b *batcher) addNeedLock(session *topicreadercommon.PartitionSession, item batcherMessageOrderItem) error {
 	var currentItems batcherMessageOrderItems
 	var ok bool
@@ -593,6 +593,67 @@ func TestBatcherGetOptions_Split(t *testing.T) {
 	})
 }
 
+func TestBatcher_SkipSessionMessagesBefore(t *testing.T) {
+	newSession := func() *topicreadercommon.PartitionSession {
+		return topicreadercommon.NewPartitionSession(
+			context.Background(), "topic", 1, 0, "", 0, 0, rawtopiccommon.NewOffset(10),
+		)
+	}
+	newBatch := func(session *topicreadercommon.PartitionSession, offsets ...int64) *topicreadercommon.PublicBatch {
+		rawBatch := rawtopicreader.Batch{Codec: rawtopiccommon.CodecRaw}
+		for _, offset := range offsets {
+			rawBatch.MessageData = append(rawBatch.MessageData, rawtopicreader.MessageData{
+				Offset: rawtopiccommon.NewOffset(offset),
+			})
+		}
+		batch, err := topicreadercommon.NewBatchFromStream(topicreadercommon.NewDecoderMap(), session, rawBatch)
+		require.NoError(t, err)
+
+		return batch
+	}
+
+	t.Run("DropBuffered", func(t *testing.T) {
+		session := newSession()
+		other := newSession()
+		b := newBatcher()
+		require.NoError(t, b.PushBatches(newBatch(session, 10, 11, 12)))
+		require.NoError(t, b.PushBatches(newBatch(other, 10)))
+		b.FlushPartitionSession(session)
+
+		dropped, _, ok, err := b.SkipSessionMessagesBefore(session, 12)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, []topicreadercommon.CommitRange{
+			{CommitOffsetStart: 10, CommitOffsetEnd: 12, PartitionSession: session},
+		}, dropped)
+		require.Len(t, b.messages[session], 1)
+		require.Len(t, b.messages[session][0].Batch.Messages, 1)
+		require.Equal(t, int64(12), b.messages[session][0].Batch.Messages[0].Offset)
+		require.Len(t, b.messages[other][0].Batch.Messages, 1)
+
+		// messages received after seek are skipped too
+		dropped, _, ok, err = b.SkipSessionMessagesBefore(session, 20)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Len(t, dropped, 1)
+		require.NotContains(t, b.messages, session)
+		require.Empty(t, b.sessionsForFlush)
+	})
+
+	t.Run("DeliveredAlready", func(t *testing.T) {
+		session := newSession()
+		b := newBatcher()
+		require.NoError(t, b.PushBatches(newBatch(session, 10, 11)))
+
+		_, err := b.Pop(context.Background(), batcherGetOptions{})
+		require.NoError(t, err)
+
+		_, _, ok, err := b.SkipSessionMessagesBefore(session, 11)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
+
 func TestBatcher_Fire(t *testing.T) {
 	t.Run("Empty", func(t *testing.T) {
 		b := newBatcher()
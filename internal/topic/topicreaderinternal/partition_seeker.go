@@ -0,0 +1,183 @@
+package topicreaderinternal
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/topic/topicreadercommon"
+)
+
+type seekPartitionKey struct {
+	topic       string
+	partitionID int64
+}
+
+// seekRequest is seek of partition, applied to first stream, started after the seek
+type seekRequest struct {
+	offset int64
+
+	// stream is id of stream for apply the seek, 0 if the stream is not started yet
+	stream int64
+}
+
+// partitionSeeker stores seek requests of the reader.
+// Requests are applied on start of partition sessions in first stream, started after the seek.
+// Requests for partitions, which are not started by the stream (moved to other reader), are forgotten
+// on start of next stream.
+// The seeker shared between stream readers of the reader.
+type partitionSeeker struct {
+	m sync.Mutex
+
+	// stream is id of last started stream
+	stream int64
+
+	// offsets for start of partition sessions by partition id
+	offsets map[int64]seekRequest
+
+	// readFrom is time of last SeekToTime, zero if no seek to time or it applied already
+	readFrom       time.Time
+	readFromStream int64
+
+	// rewound contains partitions, which started from begin of partition after last SeekToTime
+	rewound map[seekPartitionKey]bool
+
+	// commits of sessions created before seek are skipped
+	skipCommitsBefore    map[int64]int64
+	skipAllCommitsBefore int64
+}
+
+func newPartitionSeeker() *partitionSeeker {
+	return &partitionSeeker{
+		offsets:           make(map[int64]seekRequest),
+		rewound:           make(map[seekPartitionKey]bool),
+		skipCommitsBefore: make(map[int64]int64),
+	}
+}
+
+func (s *partitionSeeker) SeekPartition(partitionID, offset int64) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.offsets[partitionID] = seekRequest{offset: offset}
+	s.skipCommitsBefore[partitionID] = clientSessionCounter.Load()
+}
+
+func (s *partitionSeeker) SeekToTime(t time.Time) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.readFrom = t
+	s.readFromStream = 0
+	s.rewound = make(map[seekPartitionKey]bool)
+	s.skipAllCommitsBefore = clientSessionCounter.Load()
+}
+
+// StartStream assigns not applied seek requests to new stream and forgets requests of previous streams.
+// Returns id of the stream for StartOffset and ReadSelectors.
+func (s *partitionSeeker) StartStream() int64 {
+	if s == nil {
+		return 0
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.stream++
+	for partitionID, request := range s.offsets {
+		switch {
+		case request.stream == 0:
+			request.stream = s.stream
+			s.offsets[partitionID] = request
+		case request.stream < s.stream:
+			// partition was not started by previous stream
+			delete(s.offsets, partitionID)
+		}
+	}
+
+	switch {
+	case s.readFrom.IsZero():
+		// pass
+	case s.readFromStream == 0:
+		s.readFromStream = s.stream
+	case s.readFromStream < s.stream:
+		s.readFrom = time.Time{}
+		s.readFromStream = 0
+		s.rewound = make(map[seekPartitionKey]bool)
+	}
+
+	return s.stream
+}
+
+// StartOffset returns offset for start read partition session by the stream,
+// if seek was requested for the partition
+func (s *partitionSeeker) StartOffset(stream int64, topic string, partitionID, partitionStartOffset int64) (
+	offset int64,
+	ok bool,
+) {
+	if s == nil {
+		return 0, false
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	key := seekPartitionKey{topic: topic, partitionID: partitionID}
+	if request, has := s.offsets[partitionID]; has && request.stream == stream {
+		delete(s.offsets, partitionID)
+		s.rewound[key] = true
+
+		return request.offset, true
+	}
+
+	if !s.readFrom.IsZero() && s.readFromStream == stream && !s.rewound[key] {
+		// server skip messages, written before readFrom
+		s.rewound[key] = true
+
+		return partitionStartOffset, true
+	}
+
+	return 0, false
+}
+
+// ReadSelectors returns selectors for init the stream with read from time of last SeekToTime,
+// if the seek is applied by the stream
+func (s *partitionSeeker) ReadSelectors(
+	stream int64,
+	selectors []*topicreadercommon.PublicReadSelector,
+) []*topicreadercommon.PublicReadSelector {
+	if s == nil {
+		return selectors
+	}
+
+	s.m.Lock()
+	readFrom := s.readFrom
+	if s.readFromStream != stream {
+		readFrom = time.Time{}
+	}
+	s.m.Unlock()
+
+	if readFrom.IsZero() {
+		return selectors
+	}
+
+	res := make([]*topicreadercommon.PublicReadSelector, len(selectors))
+	for i := range selectors {
+		res[i] = selectors[i].Clone()
+		res[i].ReadFrom = readFrom
+	}
+
+	return res
+}
+
+// SkipCommit returns true for sessions, started before seek of the partition
+func (s *partitionSeeker) SkipCommit(session *topicreadercommon.PartitionSession) bool {
+	if s == nil || session == nil {
+		return false
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return session.ClientPartitionSessionID <= s.skipAllCommitsBefore ||
+		session.ClientPartitionSessionID <= s.skipCommitsBefore[session.PartitionID]
+}
@@ -0,0 +1,111 @@
+package topicreaderinternal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/grpcwrapper/rawtopic/rawtopiccommon"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/topic/topicreadercommon"
+)
+
+func newSeekerTestSession(partitionID int64) *topicreadercommon.PartitionSession {
+	return topicreadercommon.NewPartitionSession(
+		context.Background(),
+		"topic",
+		partitionID,
+		0,
+		"",
+		0,
+		clientSessionCounter.Add(1),
+		rawtopiccommon.NewOffset(0),
+	)
+}
+
+func TestPartitionSeeker(t *testing.T) {
+	t.Run("Nil", func(t *testing.T) {
+		var seeker *partitionSeeker
+		stream := seeker.StartStream()
+		_, ok := seeker.StartOffset(stream, "topic", 1, 0)
+		require.False(t, ok)
+		require.False(t, seeker.SkipCommit(newSeekerTestSession(1)))
+
+		selectors := []*topicreadercommon.PublicReadSelector{{Path: "topic"}}
+		require.Equal(t, selectors, seeker.ReadSelectors(stream, selectors))
+	})
+
+	t.Run("SeekPartition", func(t *testing.T) {
+		seeker := newPartitionSeeker()
+		before := newSeekerTestSession(1)
+		otherPartition := newSeekerTestSession(2)
+
+		seeker.SeekPartition(1, 100)
+
+		require.True(t, seeker.SkipCommit(before))
+		require.False(t, seeker.SkipCommit(otherPartition))
+		require.False(t, seeker.SkipCommit(newSeekerTestSession(1)))
+
+		stream := seeker.StartStream()
+		_, ok := seeker.StartOffset(stream, "topic", 2, 0)
+		require.False(t, ok)
+
+		offset, ok := seeker.StartOffset(stream, "topic", 1, 0)
+		require.True(t, ok)
+		require.Equal(t, int64(100), offset)
+
+		// seek applied once only
+		_, ok = seeker.StartOffset(stream, "topic", 1, 0)
+		require.False(t, ok)
+	})
+
+	t.Run("SeekPartitionForgottenByNextStream", func(t *testing.T) {
+		seeker := newPartitionSeeker()
+		seeker.SeekPartition(1, 100)
+
+		// partition moved to other reader and was not started by the stream
+		seeker.StartStream()
+
+		stream := seeker.StartStream()
+		_, ok := seeker.StartOffset(stream, "topic", 1, 0)
+		require.False(t, ok)
+		require.Empty(t, seeker.offsets)
+	})
+
+	t.Run("SeekToTime", func(t *testing.T) {
+		seeker := newPartitionSeeker()
+		before := newSeekerTestSession(1)
+		readFrom := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		seeker.SeekToTime(readFrom)
+		require.True(t, seeker.SkipCommit(before))
+
+		stream := seeker.StartStream()
+		selectors := []*topicreadercommon.PublicReadSelector{{Path: "topic", ReadFrom: time.Unix(1, 0)}}
+		withSeek := seeker.ReadSelectors(stream, selectors)
+		require.Equal(t, readFrom, withSeek[0].ReadFrom)
+		require.Equal(t, time.Unix(1, 0), selectors[0].ReadFrom)
+
+		offset, ok := seeker.StartOffset(stream, "topic", 1, 5)
+		require.True(t, ok)
+		require.Equal(t, int64(5), offset)
+
+		// partition started once by the stream
+		_, ok = seeker.StartOffset(stream, "topic", 1, 5)
+		require.False(t, ok)
+
+		// explicit partition seek has priority
+		seeker.SeekPartition(2, 50)
+		stream = seeker.StartStream()
+		offset, ok = seeker.StartOffset(stream, "topic", 2, 5)
+		require.True(t, ok)
+		require.Equal(t, int64(50), offset)
+
+		// after reconnect partitions continue from committed offset
+		require.Equal(t, selectors, seeker.ReadSelectors(stream, selectors))
+		_, ok = seeker.StartOffset(stream, "topic", 3, 5)
+		require.False(t, ok)
+		require.True(t, seeker.readFrom.IsZero())
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/ydb-platform/ydb-go-sdk/v3/credentials"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/config"
@@ -22,6 +23,7 @@ var (
 	errReaderClosed                 = xerrors.Wrap(errors.New("ydb: reader closed"))
 	errSetConsumerAndNoConsumer     = xerrors.Wrap(errors.New("ydb: reader has non empty consumer name and set option WithReaderWithoutConsumer. Only one of them must be set")) //nolint:lll
 	errCommitSessionFromOtherReader = xerrors.Wrap(errors.New("ydb: commit with session from other reader"))
+	errDeadLetterNotConfigured      = xerrors.Wrap(errors.New("ydb: dead letter topic is not configured for the reader"))
 )
 
 // TopicSteamReaderConnect connect to grpc stream
@@ -37,6 +39,16 @@ type Reader struct {
 	defaultBatchConfig ReadMessageBatchOptions
 	tracer             *trace.Topic
 	readerID           int64
+	deadLetter         *topicreadercommon.DeadLetterQueue
+	seeker             *partitionSeeker
+}
+
+type streamReconnector interface {
+	Reconnect(ctx context.Context, reason error) error
+}
+
+type partitionForwardSeeker interface {
+	SeekPartitionForward(ctx context.Context, partitionID, offset int64) (bool, error)
 }
 
 func (r *Reader) TopicOnReaderStart(consumer string, err error) {
@@ -79,7 +91,11 @@ func NewReader(
 ) (Reader, error) {
 	cfg := convertNewParamsToStreamConfig(consumer, readSelectors, opts...)
 
-	if errs := cfg.Validate(); len(errs) > 0 {
+	errs := cfg.Validate()
+	if err := cfg.DeadLetter.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
 		return Reader{}, xerrors.WithStackTrace(fmt.Errorf(
 			"ydb: failed to start topic reader, because is contains error in config: %w",
 			errors.Join(errs...),
@@ -87,6 +103,7 @@ func NewReader(
 	}
 
 	readerID := topicreadercommon.NextReaderID()
+	cfg.seeker = newPartitionSeeker()
 
 	readerConnector := func(ctx context.Context) (batchedStreamReader, error) {
 		stream, err := connector(ctx, readerID, cfg.Trace)
@@ -111,6 +128,13 @@ func NewReader(
 		defaultBatchConfig: cfg.DefaultBatchConfig,
 		tracer:             cfg.Trace,
 		readerID:           readerID,
+		seeker:             cfg.seeker,
+	}
+
+	if cfg.DeadLetter.Enabled() {
+		deadLetterCfg := cfg.DeadLetter
+		deadLetterCfg.Consumer = cfg.Consumer
+		res.deadLetter = topicreadercommon.NewDeadLetterQueue(deadLetterCfg)
 	}
 
 	return res, nil
@@ -129,7 +153,14 @@ func (r *Reader) Tracer() *trace.Topic {
 }
 
 func (r *Reader) Close(ctx context.Context) error {
-	return r.reader.CloseWithError(ctx, xerrors.WithStackTrace(errReaderClosed))
+	err := r.reader.CloseWithError(ctx, xerrors.WithStackTrace(errReaderClosed))
+	if r.deadLetter != nil {
+		if dlqErr := r.deadLetter.Close(ctx); dlqErr != nil {
+			return errors.Join(err, xerrors.WithStackTrace(dlqErr))
+		}
+	}
+
+	return err
 }
 
 func (r *Reader) PopBatchTx(
@@ -202,7 +233,88 @@ func (r *Reader) Commit(ctx context.Context, offsets topicreadercommon.PublicCom
 		)))
 	}
 
-	return r.reader.Commit(ctx, cr)
+	if r.seeker.SkipCommit(cr.PartitionSession) {
+		// messages was read before seek, the partition reads from other position now
+		return nil
+	}
+
+	if err = r.reader.Commit(ctx, cr); err != nil {
+		return err
+	}
+
+	if r.deadLetter != nil {
+		r.deadLetter.Forget(cr)
+	}
+
+	return nil
+}
+
+// SeekPartition restarts read the partition from the offset.
+// Seek forward from messages, which were not received by user code yet, drops buffered messages of the
+// partition before the offset and commits them without reconnect.
+// Other seek restarts the stream: buffered messages of all partitions are discarded and commits of messages,
+// received before seek, are skipped.
+func (r *Reader) SeekPartition(ctx context.Context, partitionID, offset int64) error {
+	if offset < 0 {
+		return xerrors.WithStackTrace(fmt.Errorf("ydb: negative seek offset: %v", offset))
+	}
+
+	if seeker, ok := r.reader.(partitionForwardSeeker); ok {
+		applied, err := seeker.SeekPartitionForward(ctx, partitionID, offset)
+		if err != nil || applied {
+			return err
+		}
+	}
+
+	r.seeker.SeekPartition(partitionID, offset)
+
+	return r.reconnectAfterSeek(ctx)
+}
+
+// SeekToTime restarts read all partitions from messages, written since t.
+// Buffered messages are discarded and commits of messages, received before seek, are skipped.
+func (r *Reader) SeekToTime(ctx context.Context, t time.Time) error {
+	if t.IsZero() {
+		return xerrors.WithStackTrace(errors.New("ydb: zero seek time"))
+	}
+
+	r.seeker.SeekToTime(t)
+
+	return r.reconnectAfterSeek(ctx)
+}
+
+func (r *Reader) reconnectAfterSeek(ctx context.Context) error {
+	reconnector, ok := r.reader.(streamReconnector)
+	if !ok {
+		return xerrors.WithStackTrace(errors.New("ydb: reader doesn't support seek"))
+	}
+
+	return reconnector.Reconnect(ctx, xerrors.WithStackTrace(errSeek))
+}
+
+// ReportFailure registers failed processing of the messages (single message or batch).
+// When count of failures reaches max attempts of dead letter queue the messages are written
+// to dead letter topic and committed, then ReportFailure returns true.
+func (r *Reader) ReportFailure(
+	ctx context.Context,
+	messages []*topicreadercommon.PublicMessage,
+	commitRange topicreadercommon.PublicCommitRangeGetter,
+	cause error,
+) (deadLettered bool, _ error) {
+	if r.deadLetter == nil {
+		return false, xerrors.WithStackTrace(errDeadLetterNotConfigured)
+	}
+
+	deadLettered, err := r.deadLetter.OnFailure(ctx, messages, topicreadercommon.GetCommitRange(commitRange), cause)
+	if err != nil || !deadLettered {
+		return false, err
+	}
+
+	if err = r.Commit(ctx, commitRange); err != nil {
+		return false, err
+	}
+
+	return true, nil
 }
 
 func (r *Reader) CommitRanges(ctx context.Context, ranges []topicreadercommon.PublicCommitRange) error {
@@ -251,6 +363,7 @@ type ReaderConfig struct {
 
 	RetrySettings      topic.RetrySettings
 	DefaultBatchConfig ReadMessageBatchOptions
+	DeadLetter         topicreadercommon.DeadLetterConfig
 	topicStreamReaderConfig
 }
 
@@ -271,6 +384,13 @@ func WithTrace(tracer *trace.Topic) PublicReaderOption {
 	}
 }
 
+// WithDeadLetterWriter set function for start writer to dead letter topic
+func WithDeadLetterWriter(startWriter topicreadercommon.DeadLetterWriterStarter) PublicReaderOption {
+	return func(cfg *ReaderConfig) {
+		cfg.DeadLetter.StartWriter = startWriter
+	}
+}
+
 func convertNewParamsToStreamConfig(
 	consumer string,
 	readSelectors []topicreadercommon.PublicReadSelector,
@@ -174,6 +174,71 @@ func TestReader_Commit(t *testing.T) {
 	})
 }
 
+type testDeadLetterWriter struct {
+	messages []topicreadercommon.DeadLetterMessage
+}
+
+func (w *testDeadLetterWriter) Write(_ context.Context, messages []topicreadercommon.DeadLetterMessage) error {
+	w.messages = append(w.messages, messages...)
+
+	return nil
+}
+
+func (w *testDeadLetterWriter) Close(context.Context) error {
+	return nil
+}
+
+func TestReader_ReportFailure(t *testing.T) {
+	t.Run("DeadLetter", func(t *testing.T) {
+		ctx := xtest.Context(t)
+		mc := gomock.NewController(t)
+		defer mc.Finish()
+
+		readerID := topicreadercommon.NextReaderID()
+		baseReader := NewMockbatchedStreamReader(mc)
+		dlqWriter := &testDeadLetterWriter{}
+		reader := &Reader{
+			reader:   baseReader,
+			readerID: readerID,
+			deadLetter: topicreadercommon.NewDeadLetterQueue(topicreadercommon.DeadLetterConfig{
+				Topic:       "dlq",
+				MaxAttempts: 2,
+				StartWriter: func(topicPath string) (topicreadercommon.DeadLetterWriter, error) {
+					return dlqWriter, nil
+				},
+			}),
+		}
+
+		commitRange := topicreadercommon.CommitRange{
+			CommitOffsetStart: 9,
+			CommitOffsetEnd:   10,
+			PartitionSession:  newTestPartitionSessionReaderID(readerID, 10),
+		}
+		msg := topicreadercommon.MessageWithSetCommitRangeForTest(
+			&topicreadercommon.PublicMessage{Offset: 9},
+			commitRange,
+		)
+		testErr := errors.New("test error")
+
+		deadLettered, err := reader.ReportFailure(ctx, []*topicreadercommon.PublicMessage{msg}, msg, testErr)
+		require.NoError(t, err)
+		require.False(t, deadLettered)
+		require.Empty(t, dlqWriter.messages)
+
+		baseReader.EXPECT().Commit(gomock.Any(), commitRange).Return(nil)
+		deadLettered, err = reader.ReportFailure(ctx, []*topicreadercommon.PublicMessage{msg}, msg, testErr)
+		require.NoError(t, err)
+		require.True(t, deadLettered)
+		require.Len(t, dlqWriter.messages, 1)
+	})
+
+	t.Run("NotConfigured", func(t *testing.T) {
+		reader := &Reader{}
+		_, err := reader.ReportFailure(xtest.Context(t), nil, nil, errors.New("test error"))
+		require.ErrorIs(t, err, errDeadLetterNotConfigured)
+	})
+}
+
 func TestReader_WaitInit(t *testing.T) {
 	mc := gomock.NewController(t)
 	defer mc.Finish()
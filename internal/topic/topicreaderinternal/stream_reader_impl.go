@@ -60,6 +60,10 @@ type topicStreamReaderImpl struct {
 	stream           topicreadercommon.RawTopicReaderStream
 	readConnectionID string
 	readerID         int64
+	seekStream       int64
+
+	// receiveMutex serializes put received messages to batcher and seek of partitions
+	receiveMutex xsync.Mutex
 
 	m       xsync.RWMutex
 	err     error
@@ -81,7 +85,10 @@ type topicStreamReaderConfig struct {
 	GetPartitionStartOffsetCallback PublicGetPartitionStartOffsetFunc
 	CommitMode                      topicreadercommon.PublicCommitMode
 	Decoders                        topicreadercommon.DecoderMap
+	Middlewares                     []topicreadercommon.PublicMiddleware
 	EnableSplitMergeSupport         bool
+
+	seeker *partitionSeeker
 }
 
 func newTopicStreamReaderConfig() topicStreamReaderConfig {
@@ -361,11 +368,13 @@ func (r *topicStreamReaderImpl) ReadMessageBatch(
 	)
 	defer func() {
 		if batch == nil {
-			onDone(0, "", -1, -1, -1, -1, r.getRestBufferBytes(), err)
+			onDone(0, 0, nil, "", -1, -1, -1, -1, r.getRestBufferBytes(), err)
 		} else {
 			commitRange := topicreadercommon.GetCommitRange(batch)
 			onDone(
 				len(batch.Messages),
+				topicreadercommon.BatchGetBytesSize(batch),
+				topicreadercommon.MessagesMetadata(batch.Messages),
 				batch.Topic(),
 				batch.PartitionID(),
 				topicreadercommon.BatchGetPartitionSession(batch).StreamPartitionSessionID.ToInt64(),
@@ -573,7 +582,7 @@ func (r *topicStreamReaderImpl) checkCommitRange(commitRange topicreadercommon.C
 	if err != nil || session != ownSession {
 		return xerrors.WithStackTrace(topicreadercommon.ErrPublicCommitSessionToExpiredSession)
 	}
-	if session.CommittedOffset() != commitRange.CommitOffsetStart && r.cfg.CommitMode == topicreadercommon.CommitModeSync {
+	if session.NextCommitOffset() != commitRange.CommitOffsetStart && r.cfg.CommitMode == topicreadercommon.CommitModeSync {
 		return topicreadercommon.ErrWrongCommitOrderInSyncMode
 	}
 
@@ -622,7 +631,12 @@ func (r *topicStreamReaderImpl) setStarted() error {
 }
 
 func (r *topicStreamReaderImpl) initSession() (err error) {
-	initMessage := topicreadercommon.CreateInitMessage(r.cfg.Consumer, r.cfg.EnableSplitMergeSupport, r.cfg.ReadSelectors)
+	r.seekStream = r.cfg.seeker.StartStream()
+	initMessage := topicreadercommon.CreateInitMessage(
+		r.cfg.Consumer,
+		r.cfg.EnableSplitMergeSupport,
+		r.cfg.seeker.ReadSelectors(r.seekStream, r.cfg.ReadSelectors),
+	)
 
 	logCtx := r.cfg.BaseContext
 	onDone := trace.TopicOnReaderInit(r.cfg.Trace, &logCtx, r.readConnectionID, initMessage)
@@ -793,6 +807,10 @@ func (r *topicStreamReaderImpl) freeBufferFromMessages(batch *topicreadercommon.
 	for messageIndex := range batch.Messages {
 		size += topicreadercommon.MessageGetBufferBytesAccount(batch.Messages[messageIndex])
 	}
+	r.freeBuffer(size)
+}
+
+func (r *topicStreamReaderImpl) freeBuffer(size int) {
 	select {
 	case r.freeBytes <- size:
 	case <-r.ctx.Done():
@@ -823,11 +841,25 @@ func (r *topicStreamReaderImpl) onReadResponse(msg *rawtopicreader.ReadResponse)
 		onDone(err)
 	}()
 
+	r.receiveMutex.Lock()
+	defer r.receiveMutex.Unlock()
+
 	batches, err2 := topicreadercommon.ReadRawBatchesToPublicBatches(msg, &r.sessionController, r.cfg.Decoders)
 	if err2 != nil {
 		return err2
 	}
 
+	batches, dropped, droppedBytes, err2 := topicreadercommon.ApplyMiddlewares(r.cfg.Middlewares, batches)
+	if err2 != nil {
+		return err2
+	}
+	if err2 = r.commitDropped(dropped); err2 != nil {
+		return err2
+	}
+	if droppedBytes > 0 {
+		r.freeBuffer(droppedBytes)
+	}
+
 	for i := range batches {
 		if err := r.batcher.PushBatches(batches[i]); err != nil {
 			return err
@@ -837,6 +869,50 @@ func (r *topicStreamReaderImpl) onReadResponse(msg *rawtopicreader.ReadResponse)
 	return nil
 }
 
+// commitDropped commits messages, dropped by middlewares, they will not be committed by user code
+func (r *topicStreamReaderImpl) commitDropped(dropped []topicreadercommon.CommitRange) error {
+	if !r.cfg.CommitMode.CommitsEnabled() {
+		return nil
+	}
+	for i := range dropped {
+		if err := r.committer.CommitNoWait(dropped[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SeekPartitionForward skips messages of the partition before the offset without restart the stream.
+// Returns false if the partition is not read by the stream or messages after the offset
+// were received by user code already, then the partition must be re-read from the offset.
+func (r *topicStreamReaderImpl) SeekPartitionForward(partitionID, offset int64) (bool, error) {
+	r.receiveMutex.Lock()
+	defer r.receiveMutex.Unlock()
+
+	found := false
+	for _, session := range r.sessionController.GetAll() {
+		if session.PartitionID != partitionID || session.Context().Err() != nil {
+			continue
+		}
+
+		dropped, freeBytes, ok, err := r.batcher.SkipSessionMessagesBefore(session, offset)
+		if err != nil || !ok {
+			return false, err
+		}
+		found = true
+
+		if err = r.commitDropped(dropped); err != nil {
+			return false, err
+		}
+		if freeBytes > 0 {
+			r.freeBuffer(freeBytes)
+		}
+	}
+
+	return found, nil
+}
+
 func (r *topicStreamReaderImpl) CloseWithError(ctx context.Context, reason error) (closeErr error) {
 	logCtx := r.cfg.BaseContext
 	onDone := trace.TopicOnReaderClose(r.cfg.Trace, &logCtx, r.readConnectionID, reason)
@@ -973,7 +1049,14 @@ func (r *topicStreamReaderImpl) onStartPartitionSessionRequestFromBuffer(
 		onDone(forceOffset, commitOffset, err)
 	}()
 
-	if r.cfg.GetPartitionStartOffsetCallback != nil {
+	if seekOffset, ok := r.cfg.seeker.StartOffset(
+		r.seekStream,
+		session.Topic,
+		session.PartitionID,
+		m.PartitionOffsets.Start.ToInt64(),
+	); ok {
+		forceOffset = &seekOffset
+	} else if r.cfg.GetPartitionStartOffsetCallback != nil {
 		req := PublicGetPartitionStartOffsetRequest{
 			Topic:       session.Topic,
 			PartitionID: session.PartitionID,
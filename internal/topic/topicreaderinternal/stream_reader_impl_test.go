@@ -1434,3 +1434,74 @@ func TestUpdateCommitInTransaction(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestTopicStreamReaderImpl_Middlewares(t *testing.T) {
+	e := newTopicReaderTestEnv(t)
+	e.reader.cfg.Middlewares = []topicreadercommon.PublicMiddleware{topicreadercommon.MetadataFilter("type", "keep")}
+	e.Start()
+
+	lastOffset := e.partitionSession.LastReceivedMessageOffset()
+	const dataSize = 4
+
+	// buffer of dropped message released without read
+	readRequestReceived := make(empty.Chan)
+	e.stream.EXPECT().Send(
+		&rawtopicreader.ReadRequest{BytesSize: dataSize},
+	).DoAndReturn(func(_ rawtopicreader.ClientMessage) error {
+		close(readRequestReceived)
+
+		return nil
+	})
+
+	sendMessage := func(offset rawtopiccommon.Offset, messageType string) {
+		e.SendFromServer(&rawtopicreader.ReadResponse{
+			BytesSize: dataSize,
+			PartitionData: []rawtopicreader.PartitionData{
+				{
+					PartitionSessionID: e.partitionSessionID,
+					Batches: []rawtopicreader.Batch{
+						{
+							Codec: rawtopiccommon.CodecRaw,
+							MessageData: []rawtopicreader.MessageData{
+								{
+									Offset:        offset,
+									MetadataItems: []rawtopiccommon.MetadataItem{{Key: "type", Value: []byte(messageType)}},
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+	}
+	// dropped message committed without user code
+	commitReceived := make(empty.Chan)
+	e.stream.EXPECT().Send(&rawtopicreader.CommitOffsetRequest{
+		CommitOffsets: []rawtopicreader.PartitionCommitOffset{
+			{
+				PartitionSessionID: e.partitionSessionID,
+				Offsets:            []rawtopiccommon.OffsetRange{{Start: lastOffset + 1, End: lastOffset + 2}},
+			},
+		},
+	}).DoAndReturn(func(_ rawtopicreader.ClientMessage) error {
+		close(commitReceived)
+
+		return nil
+	})
+
+	sendMessage(lastOffset+1, "skip")
+	xtest.WaitChannelClosed(t, readRequestReceived)
+	xtest.WaitChannelClosed(t, commitReceived)
+
+	// buffer of kept message released after read
+	e.stream.EXPECT().Send(&rawtopicreader.ReadRequest{BytesSize: dataSize}).MaxTimes(1)
+	sendMessage(lastOffset+2, "keep")
+	batch, err := e.reader.ReadMessageBatch(e.ctx, newReadMessageBatchOptions())
+	require.NoError(t, err)
+	require.Len(t, batch.Messages, 1)
+	require.Equal(t, (lastOffset + 2).ToInt64(), batch.Messages[0].Offset)
+
+	commitRange := topicreadercommon.GetCommitRange(batch)
+	require.Equal(t, lastOffset+2, commitRange.CommitOffsetStart)
+	require.Equal(t, lastOffset+3, commitRange.CommitOffsetEnd)
+}
@@ -26,6 +26,7 @@ import (
 var (
 	errReconnectRequestOutdated = xerrors.Wrap(errors.New("ydb: reconnect request outdated"))
 	errReconnect                = xerrors.Wrap(errors.New("ydb: reconnect to topic grpc stream"))
+	errSeek                     = xerrors.Retryable(xerrors.Wrap(errors.New("ydb: reader seek")))
 	errConnectionTimeout        = xerrors.Wrap(errors.New("ydb: topic reader connection timeout for stream"))
 )
 
@@ -207,6 +208,46 @@ func (r *readerReconnector) Commit(
 	return err
 }
 
+// Reconnect closes current stream and starts new connection.
+// Buffered messages of the closed stream are discarded.
+func (r *readerReconnector) Reconnect(ctx context.Context, reason error) error {
+	stream, err := r.stream(ctx)
+	switch {
+	case r.isRetriableError(err):
+		// reconnect already in progress
+		return nil
+	case err != nil:
+		return err
+	}
+
+	_ = stream.CloseWithError(ctx, reason)
+	r.fireReconnectOnRetryableError(stream, reason)
+
+	return nil
+}
+
+// SeekPartitionForward skips messages of the partition before the offset in current stream without reconnect.
+// Returns false if the seek needs re-read of the partition.
+func (r *readerReconnector) SeekPartitionForward(ctx context.Context, partitionID, offset int64) (bool, error) {
+	stream, err := r.stream(ctx)
+	switch {
+	case r.isRetriableError(err):
+		// reconnect in progress
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	seeker, ok := stream.(interface {
+		SeekPartitionForward(partitionID, offset int64) (bool, error)
+	})
+	if !ok {
+		return false, nil
+	}
+
+	return seeker.SeekPartitionForward(partitionID, offset)
+}
+
 func (r *readerReconnector) CloseWithError(ctx context.Context, reason error) error {
 	var closeErr error
 	r.closeOnce.Do(func() {
@@ -303,9 +344,7 @@ func (r *readerReconnector) reconnectionLoop(ctx context.Context) {
 			}
 		}
 
-		err := r.reconnect(ctx, request.reason, request.oldReader)
-		logCtx := r.logContext
-		trace.TopicOnReaderReconnect(r.tracer, &logCtx, request.reason)(err)
+		_ = r.reconnect(ctx, request.reason, request.oldReader)
 	}
 }
 
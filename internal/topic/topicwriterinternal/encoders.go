@@ -8,6 +8,9 @@ import (
 	"io"
 	"sync"
 
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/grpcwrapper/rawtopic/rawtopiccommon"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xsync"
@@ -68,10 +71,19 @@ func NewMultiEncoder() *MultiEncoder {
 	me.AddEncoder(rawtopiccommon.CodecGzip, func(writer io.Writer) (io.WriteCloser, error) {
 		return gzip.NewWriter(writer), nil
 	})
+	me.AddEncoder(rawtopiccommon.CodecZstd, func(writer io.Writer) (io.WriteCloser, error) {
+		// messages are compressed in parallel by the writer, internal concurrency of encoder is not needed
+		return zstd.NewWriter(writer, zstd.WithEncoderConcurrency(1))
+	})
 
 	return me
 }
 
+// NewLz4Encoder creates lz4 encoder for custom codec, lz4 has no code in the protocol
+func NewLz4Encoder(writer io.Writer) (io.WriteCloser, error) {
+	return lz4.NewWriter(writer), nil
+}
+
 func (e *MultiEncoder) AddEncoder(codec rawtopiccommon.Codec, creator PublicCreateEncoderFunc) {
 	e.m[codec] = creator
 	e.ep[codec] = newEncoderPool()
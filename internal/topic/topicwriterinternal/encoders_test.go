@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/grpcwrapper/rawtopic/rawtopiccommon"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/topic/topicreadercommon"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xrand"
 	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
 )
@@ -240,7 +241,7 @@ func TestMultiEncoder(t *testing.T) {
 
 	t.Run("NotResetableWriter", func(t *testing.T) {
 		testMultiEncoder := NewMultiEncoder()
-		require.Len(t, testMultiEncoder.ep, 2)
+		require.Len(t, testMultiEncoder.ep, 3)
 
 		buf := &bytes.Buffer{}
 		_, err := testMultiEncoder.EncodeBytes(rawtopiccommon.CodecRaw, buf, []byte("test_data"))
@@ -255,7 +256,7 @@ func TestMultiEncoder(t *testing.T) {
 		testMultiEncoder.AddEncoder(customCodecCode, func(writer io.Writer) (io.WriteCloser, error) {
 			return gzip.NewWriter(writer), nil
 		})
-		require.Len(t, testMultiEncoder.ep, 3)
+		require.Len(t, testMultiEncoder.ep, 4)
 
 		buf := &bytes.Buffer{}
 		_, err := testMultiEncoder.EncodeBytes(customCodecCode, buf, []byte("test_data_1"))
@@ -296,4 +297,30 @@ func TestMultiEncoder(t *testing.T) {
 			require.Equal(t, string(testMsg), decompressGzip(buf))
 		}
 	})
+
+	t.Run("ZstdAndLz4", func(t *testing.T) {
+		lz4Codec := rawtopiccommon.Codec(rawtopiccommon.CodecCustomerFirst)
+		testMultiEncoder := NewMultiEncoder()
+		testMultiEncoder.AddEncoder(lz4Codec, NewLz4Encoder)
+		decoders := topicreadercommon.NewDecoderMap()
+		decoders.AddDecoder(lz4Codec, topicreadercommon.NewLz4Decoder)
+
+		for _, codec := range []rawtopiccommon.Codec{rawtopiccommon.CodecZstd, lz4Codec} {
+			buf := &bytes.Buffer{}
+			for i := 0; i < 10; i++ {
+				testMsg := []byte(strings.Repeat(fmt.Sprintf("test_data_%d", i), 100))
+
+				buf.Reset()
+				_, err := testMultiEncoder.EncodeBytes(codec, buf, testMsg)
+				require.NoError(t, err)
+				require.Less(t, buf.Len(), len(testMsg))
+
+				reader, err := decoders.Decode(codec, bytes.NewReader(buf.Bytes()))
+				require.NoError(t, err)
+				decompressed, err := io.ReadAll(reader)
+				require.NoError(t, err)
+				require.Equal(t, testMsg, decompressed)
+			}
+		}
+	})
 }
@@ -0,0 +1,518 @@
+package topicwriterinternal
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5" //nolint:gosec
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/background"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/empty"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/grpcwrapper/rawtopic"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xsync"
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
+)
+
+var (
+	errNoActivePartitions     = xerrors.Wrap(errors.New("ydb: topic has no active partitions"))
+	errStopKeyedWriter        = xerrors.Wrap(errors.New("ydb: stop keyed writer"))
+	errKeyedWriterNoDescriber = xerrors.Wrap(errors.New("ydb: keyed writer has no describe topic function"))
+)
+
+const defaultKeyedWriterRefreshInterval = time.Minute
+
+// PublicKeyedPartitioner is a strategy of map message key to partition
+type PublicKeyedPartitioner int
+
+const (
+	// PublicKeyedPartitionerAuto use PublicKeyedPartitionerKeyRange if all active partitions of the topic
+	// have key ranges (autopartitioned topics) and PublicKeyedPartitionerHash otherwise
+	PublicKeyedPartitionerAuto = PublicKeyedPartitioner(iota)
+
+	// PublicKeyedPartitionerHash map key to the active partition by hash of key modulo count of active partitions.
+	// Mapping of keys changes when count of active partitions changes.
+	PublicKeyedPartitionerHash
+
+	// PublicKeyedPartitionerKeyRange map md5 hash of key into key ranges of active partitions.
+	// When a partition splits - keys of the partition move to its child partitions only.
+	PublicKeyedPartitionerKeyRange
+)
+
+// PublicKeyedMessage is a message with key for route to partition
+type PublicKeyedMessage struct {
+	Key string
+	PublicMessage
+}
+
+type keyedPartitionWriter interface {
+	Write(ctx context.Context, messages []PublicMessage) error
+	Flush(ctx context.Context) error
+	Close(ctx context.Context) error
+}
+
+type (
+	KeyedWriterDescribeFunc func(ctx context.Context) ([]rawtopic.PartitionInfo, error)
+
+	KeyedWriterConfig struct {
+		// Describe returns partitions of the topic
+		Describe KeyedWriterDescribeFunc
+
+		// WriterOptions used for create writer of each partition
+		WriterOptions []PublicWriterOption
+
+		Partitioner     PublicKeyedPartitioner
+		RefreshInterval time.Duration
+
+		// newWriter for tests only
+		newWriter func(partitionID int64, opts []PublicWriterOption) (keyedPartitionWriter, error)
+	}
+
+	PublicKeyedWriterOption func(cfg *KeyedWriterConfig)
+)
+
+func WithKeyedWriterPartitioner(partitioner PublicKeyedPartitioner) PublicKeyedWriterOption {
+	return func(cfg *KeyedWriterConfig) {
+		cfg.Partitioner = partitioner
+	}
+}
+
+func WithKeyedWriterRefreshInterval(interval time.Duration) PublicKeyedWriterOption {
+	return func(cfg *KeyedWriterConfig) {
+		cfg.RefreshInterval = interval
+	}
+}
+
+func WithKeyedWriterWriterOptions(opts ...PublicWriterOption) PublicKeyedWriterOption {
+	return func(cfg *KeyedWriterConfig) {
+		cfg.WriterOptions = append(cfg.WriterOptions, opts...)
+	}
+}
+
+func NewKeyedWriterConfig(describe KeyedWriterDescribeFunc, opts ...PublicKeyedWriterOption) KeyedWriterConfig {
+	cfg := KeyedWriterConfig{
+		Describe:        describe,
+		Partitioner:     PublicKeyedPartitionerAuto,
+		RefreshInterval: defaultKeyedWriterRefreshInterval,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+
+	return cfg
+}
+
+// KeyedWriter route messages to partitions by message key.
+// Messages with same key written to same partition in order of Write calls.
+// KeyedWriter holds one WriterReconnector per active partition and refresh partitions of the topic
+// periodically and after reconnects of partition writers (for example after partition split).
+type KeyedWriter struct {
+	cfg        KeyedWriterConfig
+	background background.Worker
+	refreshCh  empty.Chan
+
+	refreshMutex xsync.Mutex
+
+	// m guard routing and generation, it isn't held during write to partition writers
+	m          xsync.RWMutex
+	routing    keyedRouting
+	generation *keyedWriterGeneration
+	writers    map[int64]keyedPartitionWriter
+
+	writersMutex xsync.Mutex
+}
+
+// keyedWriterGeneration is writes with same routing. Writes of new generation wait for writes of previous
+// generation and close of writers of inactive partitions: messages of a key, written to old partition,
+// are flushed before write to new partition.
+type keyedWriterGeneration struct {
+	writes sync.WaitGroup
+	ready  empty.Chan
+}
+
+func newKeyedWriterGeneration() *keyedWriterGeneration {
+	return &keyedWriterGeneration{ready: make(empty.Chan)}
+}
+
+func NewKeyedWriter(ctx context.Context, cfg KeyedWriterConfig) (*KeyedWriter, error) { //nolint:gocritic
+	if cfg.Describe == nil {
+		return nil, xerrors.WithStackTrace(errKeyedWriterNoDescriber)
+	}
+
+	w := &KeyedWriter{
+		cfg:        cfg,
+		refreshCh:  make(empty.Chan, 1),
+		generation: newKeyedWriterGeneration(),
+		writers:    make(map[int64]keyedPartitionWriter),
+	}
+	close(w.generation.ready)
+	if w.cfg.newWriter == nil {
+		w.cfg.newWriter = w.newWriterReconnector
+	}
+
+	if err := w.Refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	w.background.Start("keyed writer refresh loop", w.refreshLoop)
+
+	return w, nil
+}
+
+type keyedWriterBatch struct {
+	writer   keyedPartitionWriter
+	messages []PublicMessage
+}
+
+// Write route messages to partitions by key and write it with partition writers.
+// Order of messages with same key is kept.
+func (w *KeyedWriter) Write(ctx context.Context, messages []PublicKeyedMessage) error {
+	if err := w.background.CloseReason(); err != nil {
+		return xerrors.WithStackTrace(fmt.Errorf("ydb: keyed writer is closed: %w", err))
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	generation, batches, err := w.routeMessages(messages)
+	if err != nil {
+		return err
+	}
+	defer generation.writes.Done()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-generation.ready:
+	}
+
+	for i := range batches {
+		if err = batches[i].writer.Write(ctx, batches[i].messages); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// routeMessages resolves writers for messages and registers write in current generation
+func (w *KeyedWriter) routeMessages(messages []PublicKeyedMessage) (
+	generation *keyedWriterGeneration,
+	batches []keyedWriterBatch,
+	_ error,
+) {
+	w.m.RLock()
+	defer w.m.RUnlock()
+
+	batchIndexes := make(map[int64]int)
+	for i := range messages {
+		partitionID := w.routing.route(messages[i].Key)
+		index, has := batchIndexes[partitionID]
+		if !has {
+			writer, err := w.partitionWriter(partitionID)
+			if err != nil {
+				return nil, nil, err
+			}
+			index = len(batches)
+			batchIndexes[partitionID] = index
+			batches = append(batches, keyedWriterBatch{writer: writer})
+		}
+		batches[index].messages = append(batches[index].messages, messages[i].PublicMessage)
+	}
+
+	w.generation.writes.Add(1)
+
+	return w.generation, batches, nil
+}
+
+// PartitionForKey returns partition id for the key with current routing
+func (w *KeyedWriter) PartitionForKey(key string) int64 {
+	w.m.RLock()
+	defer w.m.RUnlock()
+
+	return w.routing.route(key)
+}
+
+// Flush waits till all in-flight messages of all partitions are acknowledged
+func (w *KeyedWriter) Flush(ctx context.Context) error {
+	var errs []error
+	for _, writer := range w.currentWriters() {
+		if err := writer.Flush(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return xerrors.Join(errs...)
+}
+
+// Refresh describes the topic and update routing if active partitions changed.
+// Writers of inactive partitions flushed and closed before messages routed to new partitions.
+func (w *KeyedWriter) Refresh(ctx context.Context) error {
+	w.refreshMutex.Lock()
+	defer w.refreshMutex.Unlock()
+
+	partitions, err := w.cfg.Describe(ctx)
+	if err != nil {
+		return xerrors.WithStackTrace(err)
+	}
+
+	routing, err := newKeyedRouting(partitions, w.cfg.Partitioner)
+	if err != nil {
+		return err
+	}
+
+	var (
+		prevGeneration *keyedWriterGeneration
+		newGeneration  *keyedWriterGeneration
+		retired        []keyedPartitionWriter
+	)
+	w.m.WithLock(func() {
+		if routing.equal(&w.routing) {
+			return
+		}
+
+		prevGeneration, newGeneration = w.generation, newKeyedWriterGeneration()
+		w.generation = newGeneration
+		w.routing = routing
+		w.writersMutex.WithLock(func() {
+			for partitionID, writer := range w.writers {
+				if !routing.has(partitionID) {
+					retired = append(retired, writer)
+					delete(w.writers, partitionID)
+				}
+			}
+		})
+	})
+	if prevGeneration == nil {
+		return nil
+	}
+
+	defer close(newGeneration.ready)
+
+	// writes of previous generation may put messages to retired writers
+	prevGeneration.writes.Wait()
+
+	var errs []error
+	for _, writer := range retired {
+		if err = writer.Close(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return xerrors.Join(errs...)
+}
+
+// Close flush messages of all partitions and close the writer
+func (w *KeyedWriter) Close(ctx context.Context) error {
+	if err := w.background.Close(ctx, xerrors.WithStackTrace(errStopKeyedWriter)); err != nil {
+		return err
+	}
+
+	var writers map[int64]keyedPartitionWriter
+	w.writersMutex.WithLock(func() {
+		writers = w.writers
+		w.writers = make(map[int64]keyedPartitionWriter)
+	})
+
+	var errs []error
+	for _, writer := range writers {
+		if err := writer.Close(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return xerrors.Join(errs...)
+}
+
+func (w *KeyedWriter) currentWriters() map[int64]keyedPartitionWriter {
+	w.writersMutex.Lock()
+	defer w.writersMutex.Unlock()
+
+	res := make(map[int64]keyedPartitionWriter, len(w.writers))
+	for partitionID, writer := range w.writers {
+		res[partitionID] = writer
+	}
+
+	return res
+}
+
+func (w *KeyedWriter) partitionWriter(partitionID int64) (writer keyedPartitionWriter, err error) {
+	w.writersMutex.WithLock(func() {
+		var has bool
+		if writer, has = w.writers[partitionID]; has {
+			return
+		}
+		if closeReason := w.background.CloseReason(); closeReason != nil {
+			err = xerrors.WithStackTrace(fmt.Errorf("ydb: keyed writer is closed: %w", closeReason))
+
+			return
+		}
+
+		writer, err = w.cfg.newWriter(partitionID, w.cfg.WriterOptions)
+		if err == nil {
+			w.writers[partitionID] = writer
+		}
+	})
+
+	return writer, err
+}
+
+func (w *KeyedWriter) newWriterReconnector(partitionID int64, opts []PublicWriterOption) (keyedPartitionWriter, error) {
+	opts = append(opts[:len(opts):len(opts)],
+		WithPartitioning(NewPartitioningWithPartitionID(partitionID)),
+		WithTrace(&trace.Topic{
+			OnWriterReconnect: func(info trace.TopicWriterReconnectStartInfo) func(trace.TopicWriterReconnectConnectedInfo) func(trace.TopicWriterReconnectDoneInfo) { //nolint:lll
+				if info.Attempt > 0 {
+					// reconnect may be caused by partition split or merge
+					w.requestRefresh()
+				}
+
+				return nil
+			},
+		}),
+	)
+
+	return NewWriterReconnector(NewWriterReconnectorConfig(opts...))
+}
+
+func (w *KeyedWriter) requestRefresh() {
+	select {
+	case w.refreshCh <- empty.Struct{}:
+	default:
+	}
+}
+
+func (w *KeyedWriter) refreshLoop(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-w.refreshCh:
+		}
+
+		// errors of background refresh are not fatal: next refresh will try again
+		_ = w.Refresh(ctx)
+	}
+}
+
+type keyedPartition struct {
+	id        int64
+	fromBound []byte
+	toBound   []byte
+}
+
+type keyedRouting struct {
+	// partitions sorted by fromBound for key range routing and by id for hash routing
+	partitions []keyedPartition
+	byKeyRange bool
+}
+
+func newKeyedRouting(partitions []rawtopic.PartitionInfo, partitioner PublicKeyedPartitioner) (keyedRouting, error) {
+	var (
+		r           keyedRouting
+		hasKeyRange = true
+	)
+	for i := range partitions {
+		if !partitions[i].Active {
+			continue
+		}
+		p := keyedPartition{
+			id:        partitions[i].PartitionID,
+			fromBound: partitions[i].KeyRange.FromBound,
+			toBound:   partitions[i].KeyRange.ToBound,
+		}
+		if len(p.fromBound) == 0 && len(p.toBound) == 0 {
+			hasKeyRange = false
+		}
+		r.partitions = append(r.partitions, p)
+	}
+	if len(r.partitions) == 0 {
+		return r, xerrors.WithStackTrace(errNoActivePartitions)
+	}
+
+	switch partitioner {
+	case PublicKeyedPartitionerHash:
+		r.byKeyRange = false
+	case PublicKeyedPartitionerKeyRange:
+		r.byKeyRange = true
+	default:
+		// single partition topic has no bounds even if autopartitioning enabled
+		r.byKeyRange = hasKeyRange || len(r.partitions) == 1
+	}
+
+	if r.byKeyRange {
+		sort.Slice(r.partitions, func(i, j int) bool {
+			return bytes.Compare(r.partitions[i].fromBound, r.partitions[j].fromBound) < 0
+		})
+	} else {
+		sort.Slice(r.partitions, func(i, j int) bool {
+			return r.partitions[i].id < r.partitions[j].id
+		})
+	}
+
+	return r, nil
+}
+
+func (r *keyedRouting) route(key string) int64 {
+	if !r.byKeyRange {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(key))
+
+		return r.partitions[h.Sum64()%uint64(len(r.partitions))].id
+	}
+
+	hash := keyRangeHash(key)
+	// first partition with toBound greater than hash
+	i := sort.Search(len(r.partitions), func(i int) bool {
+		toBound := r.partitions[i].toBound
+
+		return len(toBound) == 0 || bytes.Compare(hash, toBound) < 0
+	})
+	if i == len(r.partitions) {
+		i = len(r.partitions) - 1
+	}
+
+	return r.partitions[i].id
+}
+
+// keyRangeHash map key uniformly into 128-bit key space of autopartitioned topic
+func keyRangeHash(key string) []byte {
+	hash := md5.Sum([]byte(key)) //nolint:gosec
+
+	return hash[:]
+}
+
+func (r *keyedRouting) has(partitionID int64) bool {
+	for i := range r.partitions {
+		if r.partitions[i].id == partitionID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *keyedRouting) equal(other *keyedRouting) bool {
+	if r.byKeyRange != other.byKeyRange || len(r.partitions) != len(other.partitions) {
+		return false
+	}
+	for i := range r.partitions {
+		lhs, rhs := &r.partitions[i], &other.partitions[i]
+		if lhs.id != rhs.id || !bytes.Equal(lhs.fromBound, rhs.fromBound) || !bytes.Equal(lhs.toBound, rhs.toBound) {
+			return false
+		}
+	}
+
+	return true
+}
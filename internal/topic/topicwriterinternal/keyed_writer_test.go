@@ -0,0 +1,273 @@
+package topicwriterinternal
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/empty"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/grpcwrapper/rawtopic"
+	"github.com/ydb-platform/ydb-go-sdk/v3/pkg/xtest"
+)
+
+type fakePartitionWriter struct {
+	m          sync.Mutex
+	messages   []string
+	flushed    bool
+	closed     bool
+	blockWrite empty.Chan
+	blocked    atomic.Int32
+}
+
+func (w *fakePartitionWriter) Write(_ context.Context, messages []PublicMessage) error {
+	if w.blockWrite != nil {
+		w.blocked.Add(1)
+		defer w.blocked.Add(-1)
+
+		<-w.blockWrite
+	}
+
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	for i := range messages {
+		data, _ := io.ReadAll(messages[i].Data)
+		w.messages = append(w.messages, string(data))
+	}
+
+	return nil
+}
+
+func (w *fakePartitionWriter) Flush(context.Context) error {
+	w.flushed = true
+
+	return nil
+}
+
+func (w *fakePartitionWriter) Close(context.Context) error {
+	w.closed = true
+
+	return nil
+}
+
+type keyedWriterEnv struct {
+	m          sync.Mutex
+	partitions []rawtopic.PartitionInfo
+	writers    map[int64]*fakePartitionWriter
+}
+
+func newKeyedWriterEnv(t *testing.T, partitions []rawtopic.PartitionInfo, opts ...PublicKeyedWriterOption) (
+	*keyedWriterEnv, *KeyedWriter,
+) {
+	env := &keyedWriterEnv{
+		partitions: partitions,
+		writers:    make(map[int64]*fakePartitionWriter),
+	}
+
+	cfg := NewKeyedWriterConfig(func(ctx context.Context) ([]rawtopic.PartitionInfo, error) {
+		env.m.Lock()
+		defer env.m.Unlock()
+
+		return env.partitions, nil
+	}, opts...)
+	cfg.newWriter = func(partitionID int64, _ []PublicWriterOption) (keyedPartitionWriter, error) {
+		env.m.Lock()
+		defer env.m.Unlock()
+
+		w := &fakePartitionWriter{}
+		env.writers[partitionID] = w
+
+		return w, nil
+	}
+
+	w, err := NewKeyedWriter(xtest.Context(t), cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = w.Close(context.Background())
+	})
+
+	return env, w
+}
+
+func keyedMessage(key, data string) PublicKeyedMessage {
+	return PublicKeyedMessage{Key: key, PublicMessage: PublicMessage{Data: strings.NewReader(data)}}
+}
+
+func activePartitions(ids ...int64) []rawtopic.PartitionInfo {
+	partitions := make([]rawtopic.PartitionInfo, len(ids))
+	for i, id := range ids {
+		partitions[i] = rawtopic.PartitionInfo{PartitionID: id, Active: true}
+	}
+
+	return partitions
+}
+
+func TestKeyedWriterHashRouting(t *testing.T) {
+	env, w := newKeyedWriterEnv(t, activePartitions(0, 1, 2))
+	require.False(t, w.routing.byKeyRange)
+
+	var messages []PublicKeyedMessage
+	for i := 0; i < 30; i++ {
+		messages = append(messages, keyedMessage(fmt.Sprintf("key-%d", i%10), fmt.Sprintf("%d", i)))
+	}
+	require.NoError(t, w.Write(xtest.Context(t), messages))
+
+	total := 0
+	for partitionID, writer := range env.writers {
+		total += len(writer.messages)
+		for _, data := range writer.messages {
+			var i int
+			_, err := fmt.Sscan(data, &i)
+			require.NoError(t, err)
+			require.Equal(t, partitionID, w.PartitionForKey(fmt.Sprintf("key-%d", i%10)))
+		}
+	}
+	require.Equal(t, 30, total)
+
+	// order of messages with same key is kept
+	partitionID := w.PartitionForKey("key-3")
+	var keyMessages []string
+	for _, data := range env.writers[partitionID].messages {
+		if data == "3" || data == "13" || data == "23" {
+			keyMessages = append(keyMessages, data)
+		}
+	}
+	require.Equal(t, []string{"3", "13", "23"}, keyMessages)
+
+	require.NoError(t, w.Flush(xtest.Context(t)))
+	for _, writer := range env.writers {
+		require.True(t, writer.flushed)
+	}
+}
+
+func TestKeyedWriterKeyRangeRouting(t *testing.T) {
+	middle := []byte{0x80}
+	env, w := newKeyedWriterEnv(t, []rawtopic.PartitionInfo{
+		{PartitionID: 1, Active: true, KeyRange: rawtopic.PartitionKeyRange{FromBound: middle}},
+		{PartitionID: 0, Active: true, KeyRange: rawtopic.PartitionKeyRange{ToBound: middle}},
+	})
+	require.True(t, w.routing.byKeyRange)
+
+	keys := map[int64]string{}
+	for i := 0; len(keys) < 2; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		keys[w.PartitionForKey(key)] = key
+	}
+	require.Len(t, keys, 2)
+
+	// split partition 1 into 2 and 3
+	quarter := []byte{0xc0}
+	env.m.Lock()
+	env.partitions = []rawtopic.PartitionInfo{
+		{PartitionID: 0, Active: true, KeyRange: rawtopic.PartitionKeyRange{ToBound: middle}},
+		{PartitionID: 1, Active: false, KeyRange: rawtopic.PartitionKeyRange{FromBound: middle}},
+		{PartitionID: 2, Active: true, KeyRange: rawtopic.PartitionKeyRange{FromBound: middle, ToBound: quarter}},
+		{PartitionID: 3, Active: true, KeyRange: rawtopic.PartitionKeyRange{FromBound: quarter}},
+	}
+	env.m.Unlock()
+
+	require.NoError(t, w.Write(xtest.Context(t), []PublicKeyedMessage{keyedMessage(keys[1], "before-split")}))
+	require.NoError(t, w.Refresh(xtest.Context(t)))
+	require.True(t, env.writers[1].closed)
+
+	require.Equal(t, int64(0), w.PartitionForKey(keys[0]))
+	require.Contains(t, []int64{2, 3}, w.PartitionForKey(keys[1]))
+
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		hash := keyRangeHash(key)
+		partitionID := w.PartitionForKey(key)
+		switch {
+		case bytes.Compare(hash, middle) < 0:
+			require.Equal(t, int64(0), partitionID, key)
+		case bytes.Compare(hash, quarter) < 0:
+			require.Equal(t, int64(2), partitionID, key)
+		default:
+			require.Equal(t, int64(3), partitionID, key)
+		}
+	}
+}
+
+func TestKeyedWriterRefreshWithoutChanges(t *testing.T) {
+	env, w := newKeyedWriterEnv(t, activePartitions(0, 1))
+
+	require.NoError(t, w.Write(xtest.Context(t), []PublicKeyedMessage{keyedMessage("a", "1"), keyedMessage("b", "2")}))
+	require.NoError(t, w.Refresh(xtest.Context(t)))
+	for _, writer := range env.writers {
+		require.False(t, writer.closed)
+	}
+
+	require.NoError(t, w.Close(xtest.Context(t)))
+	for _, writer := range env.writers {
+		require.True(t, writer.closed)
+	}
+	require.Error(t, w.Write(xtest.Context(t), []PublicKeyedMessage{keyedMessage("a", "3")}))
+}
+
+func TestKeyedWriterBlockedWrite(t *testing.T) {
+	ctx := xtest.Context(t)
+	env, w := newKeyedWriterEnv(t, activePartitions(0))
+	require.NoError(t, w.Write(ctx, []PublicKeyedMessage{keyedMessage("a", "1")}))
+
+	env.m.Lock()
+	env.writers[0].blockWrite = make(empty.Chan)
+	env.partitions = activePartitions(0, 1)
+	env.m.Unlock()
+
+	writeDone := make(empty.Chan)
+	go func() {
+		defer close(writeDone)
+
+		_ = w.Write(ctx, []PublicKeyedMessage{keyedMessage("a", "2")})
+	}()
+	xtest.SpinWaitCondition(t, nil, func() bool {
+		return env.writers[0].blocked.Load() == 1
+	})
+
+	// routing changed without wait of the write, but close of old writers and new writes wait for it
+	refreshDone := make(empty.Chan)
+	go func() {
+		defer close(refreshDone)
+
+		_ = w.Refresh(ctx)
+	}()
+	xtest.SpinWaitCondition(t, nil, func() bool {
+		w.m.RLock()
+		defer w.m.RUnlock()
+
+		return len(w.routing.partitions) == 2
+	})
+
+	newWriteDone := make(empty.Chan)
+	go func() {
+		defer close(newWriteDone)
+
+		_ = w.Write(ctx, []PublicKeyedMessage{keyedMessage("a", "3")})
+	}()
+
+	close(env.writers[0].blockWrite)
+	xtest.WaitChannelClosed(t, writeDone)
+	xtest.WaitChannelClosed(t, refreshDone)
+	xtest.WaitChannelClosed(t, newWriteDone)
+
+	var written []string
+	for _, writer := range env.writers {
+		written = append(written, writer.messages...)
+	}
+	require.ElementsMatch(t, []string{"1", "2", "3"}, written)
+}
+
+func TestKeyedWriterNoActivePartitions(t *testing.T) {
+	cfg := NewKeyedWriterConfig(func(ctx context.Context) ([]rawtopic.PartitionInfo, error) {
+		return []rawtopic.PartitionInfo{{PartitionID: 0}}, nil
+	})
+	_, err := NewKeyedWriter(xtest.Context(t), cfg)
+	require.ErrorIs(t, err, errNoActivePartitions)
+}
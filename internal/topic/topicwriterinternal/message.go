@@ -19,7 +19,7 @@ type PublicMessage struct {
 	SeqNo     int64
 	CreatedAt time.Time
 	Data      io.Reader
-	Metadata  map[string][]byte
+	Metadata  map[string][]byte // Metadata items of the message, use topictypes.Metadata for typed values
 
 	tx tx.Transaction
 
@@ -57,6 +57,51 @@ func NewPartitioningWithPartitionID(id int64) PublicFuturePartitioning {
 	}
 }
 
+// messagesMetadata allow to add metadata items to messages from trace handlers.
+// It copies messages and metadata before change for not modify messages of the caller.
+// Item doesn't add to message if the message metadata will be more, then limits of the writer.
+type messagesMetadata struct {
+	messages []PublicMessage
+	copied   bool
+	maxItems int
+	maxSize  int
+}
+
+func (m *messagesMetadata) SetMetadata(key string, value []byte) {
+	if !m.copied {
+		m.messages = append([]PublicMessage(nil), m.messages...)
+		m.copied = true
+	}
+
+	for i := range m.messages {
+		if _, ok := m.messages[i].Metadata[key]; ok {
+			continue
+		}
+		if m.maxItems > 0 && len(m.messages[i].Metadata)+1 > m.maxItems {
+			continue
+		}
+		if m.maxSize > 0 && rawtopiccommon.MetadataSize(m.messages[i].Metadata)+len(key)+len(value) > m.maxSize {
+			continue
+		}
+
+		metadata := make(map[string][]byte, len(m.messages[i].Metadata)+1)
+		for k, v := range m.messages[i].Metadata {
+			metadata[k] = v
+		}
+		metadata[key] = value
+		m.messages[i].Metadata = metadata
+	}
+}
+
+func (m *messagesMetadata) MetadataSize() int {
+	size := 0
+	for i := range m.messages {
+		size += rawtopiccommon.MetadataSize(m.messages[i].Metadata)
+	}
+
+	return size
+}
+
 type messageWithDataContent struct {
 	PublicMessage
 
@@ -69,6 +114,9 @@ type messageWithDataContent struct {
 	rawBuf              bytes.Buffer
 	encoders            *MultiEncoder
 	BufUncompressedSize int
+
+	// onAck called by message queue after ack of the message or close of the queue
+	onAck func(err error)
 }
 
 func (m *messageWithDataContent) GetEncodedBytes(codec rawtopiccommon.Codec) ([]byte, error) {
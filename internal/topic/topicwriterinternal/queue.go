@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/empty"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/grpcwrapper/rawtopic/rawtopicwriter"
@@ -32,7 +33,11 @@ const (
 type messageQueue struct {
 	OnAckReceived func(count int)
 
+	// OnAckedSeqNo called with max seqno of acked messages after receive acks
+	OnAckedSeqNo func(seqNo int64)
+
 	hasNewMessages    empty.Chan
+	flushRequested    empty.Chan
 	closedErr         error
 	acksReceivedEvent xsync.EventBroadcast
 
@@ -53,6 +58,7 @@ func newMessageQueue() messageQueue {
 		messagesByOrder: make(map[int]messageWithDataContent),
 		seqNoToOrderID:  make(map[int64]int),
 		hasNewMessages:  make(empty.Chan, 1),
+		flushRequested:  make(empty.Chan, 1),
 		closedChan:      make(empty.Chan),
 		lastSeqNo:       -1,
 	}
@@ -109,6 +115,15 @@ func (q *messageQueue) notifyNewMessages() {
 	}
 }
 
+// requestFlush notify sender about waiting of send messages without linger
+func (q *messageQueue) requestFlush() {
+	select {
+	case q.flushRequested <- empty.Struct{}:
+		// pass
+	default:
+	}
+}
+
 func (q *messageQueue) checkNewMessagesBeforeAddNeedLock(messages []messageWithDataContent) error {
 	if len(messages) == 0 {
 		return nil
@@ -146,8 +161,18 @@ func (q *messageQueue) addMessageNeedLock(
 	return messageIndex
 }
 
+// Len returns count of messages in the queue, which are not acked by server yet
+func (q *messageQueue) Len() int {
+	q.m.RLock()
+	defer q.m.RUnlock()
+
+	return len(q.messagesByOrder)
+}
+
 func (q *messageQueue) AcksReceived(acks []rawtopicwriter.WriteAck) error {
 	ackReceivedCounter := 0
+	maxAckedSeqNo := int64(-1)
+	var ackCallbacks []func(err error)
 	q.m.Lock()
 	defer func() {
 		q.m.Unlock()
@@ -155,15 +180,28 @@ func (q *messageQueue) AcksReceived(acks []rawtopicwriter.WriteAck) error {
 		if q.OnAckReceived != nil {
 			q.OnAckReceived(ackReceivedCounter)
 		}
+		if q.OnAckedSeqNo != nil && ackReceivedCounter > 0 {
+			q.OnAckedSeqNo(maxAckedSeqNo)
+		}
+		for _, callback := range ackCallbacks {
+			callback(nil)
+		}
 	}()
 	if q.closed {
 		return xerrors.WithStackTrace(errAckOnClosedMessageQueue)
 	}
 
 	for i := range acks {
-		if err := q.ackReceivedNeedLock(acks[i].SeqNo); err != nil {
+		onAck, err := q.ackReceivedNeedLock(acks[i].SeqNo)
+		if err != nil {
 			return err
 		}
+		if onAck != nil {
+			ackCallbacks = append(ackCallbacks, onAck)
+		}
+		if acks[i].SeqNo > maxAckedSeqNo {
+			maxAckedSeqNo = acks[i].SeqNo
+		}
 		ackReceivedCounter++
 	}
 
@@ -172,16 +210,17 @@ func (q *messageQueue) AcksReceived(acks []rawtopicwriter.WriteAck) error {
 	return nil
 }
 
-func (q *messageQueue) ackReceivedNeedLock(seqNo int64) error {
+func (q *messageQueue) ackReceivedNeedLock(seqNo int64) (onAck func(err error), _ error) {
 	orderID, ok := q.seqNoToOrderID[seqNo]
 	if !ok {
-		return xerrors.WithStackTrace(errAckUnexpectedMessage)
+		return nil, xerrors.WithStackTrace(errAckUnexpectedMessage)
 	}
 
+	onAck = q.messagesByOrder[orderID].onAck
 	delete(q.seqNoToOrderID, seqNo)
 	delete(q.messagesByOrder, orderID)
 
-	return nil
+	return onAck, nil
 }
 
 func (q *messageQueue) StopAddNewMessages(reason error) {
@@ -199,6 +238,7 @@ func (q *messageQueue) stopAddNewMessagesNeedLock(reason error) {
 
 func (q *messageQueue) Close(err error) error {
 	isFirstTimeClosed := false
+	var ackCallbacks []func(err error)
 	q.m.Lock()
 	defer func() {
 		q.m.Unlock()
@@ -207,6 +247,9 @@ func (q *messageQueue) Close(err error) error {
 		if isFirstTimeClosed && q.OnAckReceived != nil {
 			q.OnAckReceived(len(q.seqNoToOrderID))
 		}
+		for _, callback := range ackCallbacks {
+			callback(err)
+		}
 	}()
 
 	q.stopAddNewMessagesNeedLock(err)
@@ -216,6 +259,12 @@ func (q *messageQueue) Close(err error) error {
 	}
 	isFirstTimeClosed = true
 
+	for _, mess := range q.messagesByOrder {
+		if mess.onAck != nil {
+			ackCallbacks = append(ackCallbacks, mess.onAck)
+		}
+	}
+
 	q.closed = true
 	q.closedErr = err
 	close(q.closedChan)
@@ -234,6 +283,15 @@ func (q *messageQueue) ensureNoSmallIntIndexes() {
 // GetMessagesForSend one or more messages for send
 // it blocked until context cancelled of have least one message for send
 func (q *messageQueue) GetMessagesForSend(ctx context.Context) ([]messageWithDataContent, error) {
+	return q.GetMessagesBatchForSend(ctx, sendBatchLimits{})
+}
+
+// GetMessagesBatchForSend one or more messages for send, but no more than limits
+// it blocked until context cancelled of have least one message for send
+func (q *messageQueue) GetMessagesBatchForSend(
+	ctx context.Context,
+	limits sendBatchLimits,
+) ([]messageWithDataContent, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
@@ -246,7 +304,7 @@ func (q *messageQueue) GetMessagesForSend(ctx context.Context) ([]messageWithDat
 	}
 
 	for {
-		res := q.getMessagesForSendWithLock()
+		res := q.getMessagesForSendWithLock(limits)
 		if len(res) != 0 {
 			return res, nil
 		}
@@ -262,6 +320,31 @@ func (q *messageQueue) GetMessagesForSend(ctx context.Context) ([]messageWithDat
 	}
 }
 
+// FillBatchForSend adds new messages to the batch until the batch is full, linger is done or flush requested
+func (q *messageQueue) FillBatchForSend(
+	ctx context.Context,
+	batch []messageWithDataContent,
+	limits sendBatchLimits,
+	lingerDone <-chan time.Time,
+) []messageWithDataContent {
+	for !limits.isFull(batch) {
+		select {
+		case <-ctx.Done():
+			return batch
+		case <-q.closedChan:
+			return batch
+		case <-lingerDone:
+			return q.appendMessagesForSendWithLock(batch, limits)
+		case <-q.flushRequested:
+			return q.appendMessagesForSendWithLock(batch, limits)
+		case <-q.hasNewMessages:
+			batch = q.appendMessagesForSendWithLock(batch, limits)
+		}
+	}
+
+	return batch
+}
+
 func (q *messageQueue) ResetSentProgress() {
 	q.m.Lock()
 	defer q.m.Unlock()
@@ -277,28 +360,44 @@ func (q *messageQueue) ResetSentProgress() {
 	q.notifyNewMessages()
 }
 
-func (q *messageQueue) getMessagesForSendWithLock() []messageWithDataContent {
+func (q *messageQueue) getMessagesForSendWithLock(limits sendBatchLimits) []messageWithDataContent {
+	return q.appendMessagesForSendWithLock(nil, limits)
+}
+
+// appendMessagesForSendWithLock appends not sent messages to the batch while it is in the limits.
+// Empty batch receive one message at least.
+func (q *messageQueue) appendMessagesForSendWithLock(
+	batch []messageWithDataContent,
+	limits sendBatchLimits,
+) []messageWithDataContent {
 	q.m.Lock()
 	defer q.m.Unlock()
 
 	if q.lastWrittenIndex == q.lastSentIndex {
-		return nil
+		return batch
 	}
 
-	var res []messageWithDataContent
+	bytesSize := 0
+	for i := range batch {
+		bytesSize += batch[i].BufUncompressedSize
+	}
 
 	// use  "!=" stop instead of  "<" - for work with negative indexes after overflow
 	for q.lastWrittenIndex != q.lastSentIndex {
-		q.lastSentIndex++
-
 		// msg may be unexisted if it already has ack from server
 		// pass
-		if msg, ok := q.messagesByOrder[q.lastSentIndex]; ok {
-			res = append(res, msg)
+		msg, ok := q.messagesByOrder[q.lastSentIndex+1]
+		if ok {
+			if len(batch) > 0 && !limits.canAdd(len(batch), bytesSize, msg.BufUncompressedSize) {
+				break
+			}
+			batch = append(batch, msg)
+			bytesSize += msg.BufUncompressedSize
 		}
+		q.lastSentIndex++
 	}
 
-	return res
+	return batch
 }
 
 func (q *messageQueue) Wait(ctx context.Context, waiter MessageQueueAckWaiter) error {
@@ -306,6 +405,9 @@ func (q *messageQueue) Wait(ctx context.Context, waiter MessageQueueAckWaiter) e
 		return err
 	}
 
+	// waited messages must be sent without linger
+	q.requestFlush()
+
 	ctxDone := ctx.Done()
 	for {
 		ackReceived := q.acksReceivedEvent.Waiter()
@@ -348,6 +450,36 @@ func (q *messageQueue) WaitLastWritten(ctx context.Context) error {
 	return q.Wait(ctx, MessageQueueAckWaiter{sequenseNumbers: []int{lastIndex}})
 }
 
+// sendBatchLimits limits messages count and uncompressed bytes of one send batch, zero mean no limit
+type sendBatchLimits struct {
+	maxMessages int
+	maxBytes    int
+}
+
+func (l sendBatchLimits) canAdd(messagesCount, bytesSize, messageBytes int) bool {
+	if l.maxMessages > 0 && messagesCount >= l.maxMessages {
+		return false
+	}
+
+	return l.maxBytes <= 0 || bytesSize+messageBytes <= l.maxBytes
+}
+
+func (l sendBatchLimits) isFull(messages []messageWithDataContent) bool {
+	if l.maxMessages > 0 && len(messages) >= l.maxMessages {
+		return true
+	}
+	if l.maxBytes <= 0 {
+		return false
+	}
+
+	bytesSize := 0
+	for i := range messages {
+		bytesSize += messages[i].BufUncompressedSize
+	}
+
+	return bytesSize >= l.maxBytes
+}
+
 type MessageQueueAckWaiter struct {
 	sequenseNumbers []int
 }
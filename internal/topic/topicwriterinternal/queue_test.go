@@ -98,6 +98,73 @@ func TestMessageQueue_GetMessages(t *testing.T) {
 		require.Equal(t, []int64{1, 2, 3, 4}, getSeqNumbers(messages))
 	})
 
+	t.Run("Limits", func(t *testing.T) {
+		q := newMessageQueue()
+		messages := newTestMessagesWithContent(1, 2, 3, 4, 5)
+		for i := range messages {
+			messages[i].BufUncompressedSize = 10
+		}
+		require.NoError(t, q.AddMessages(messages))
+
+		res, err := q.GetMessagesBatchForSend(ctx, sendBatchLimits{maxMessages: 2})
+		require.NoError(t, err)
+		require.Equal(t, []int64{1, 2}, getSeqNumbers(res))
+
+		res, err = q.GetMessagesBatchForSend(ctx, sendBatchLimits{maxBytes: 25})
+		require.NoError(t, err)
+		require.Equal(t, []int64{3, 4}, getSeqNumbers(res))
+
+		// one message at least
+		res, err = q.GetMessagesBatchForSend(ctx, sendBatchLimits{maxBytes: 5})
+		require.NoError(t, err)
+		require.Equal(t, []int64{5}, getSeqNumbers(res))
+	})
+
+	t.Run("FillBatch", func(t *testing.T) {
+		q := newMessageQueue()
+		require.NoError(t, q.AddMessages(newTestMessagesWithContent(1)))
+
+		batch, err := q.GetMessagesBatchForSend(ctx, sendBatchLimits{maxMessages: 3})
+		require.NoError(t, err)
+
+		filled := make(chan []messageWithDataContent, 1)
+		go func() {
+			filled <- q.FillBatchForSend(ctx, batch, sendBatchLimits{maxMessages: 3}, nil)
+		}()
+
+		require.NoError(t, q.AddMessages(newTestMessagesWithContent(2)))
+		require.NoError(t, q.AddMessages(newTestMessagesWithContent(3, 4)))
+		require.Equal(t, []int64{1, 2, 3}, getSeqNumbers(<-filled))
+
+		res, err := q.GetMessagesForSend(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []int64{4}, getSeqNumbers(res))
+	})
+
+	t.Run("FillBatchUntilLinger", func(t *testing.T) {
+		q := newMessageQueue()
+		lingerDone := make(chan time.Time)
+		filled := make(chan []messageWithDataContent, 1)
+		go func() {
+			filled <- q.FillBatchForSend(ctx, nil, sendBatchLimits{}, lingerDone)
+		}()
+
+		require.NoError(t, q.AddMessages(newTestMessagesWithContent(1)))
+		require.NoError(t, q.AddMessages(newTestMessagesWithContent(2)))
+		close(lingerDone)
+		require.Equal(t, []int64{1, 2}, getSeqNumbers(<-filled))
+	})
+
+	t.Run("FillBatchUntilFlush", func(t *testing.T) {
+		q := newMessageQueue()
+		require.NoError(t, q.AddMessages(newTestMessagesWithContent(1)))
+		batch, err := q.GetMessagesForSend(ctx)
+		require.NoError(t, err)
+
+		q.requestFlush()
+		require.Equal(t, []int64{1}, getSeqNumbers(q.FillBatchForSend(ctx, batch, sendBatchLimits{}, nil)))
+	})
+
 	t.Run("SendMessagesAfterStartWait", func(t *testing.T) {
 		q := newMessageQueue()
 
@@ -0,0 +1,618 @@
+package topicwriterinternal
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xsync"
+)
+
+const (
+	spillSegmentExt      = ".wal"
+	spillSegmentMaxBytes = 16 * 1024 * 1024
+	spillHeaderSize      = 8
+	spillDirPermissions  = 0o700
+	spillFilePermissions = 0o600
+)
+
+var (
+	ErrPublicSpillQuotaExceeded = xerrors.Wrap(errors.New("ydb: topic writer spill quota exceeded"))
+
+	errSpillClosed       = xerrors.Wrap(errors.New("ydb: topic writer spill closed"))
+	errSpillBrokenRecord = xerrors.Wrap(errors.New("ydb: broken record in topic writer spill"))
+	errSpillTransaction  = xerrors.Wrap(errors.New("ydb: topic writer spill doesn't support transactions"))
+)
+
+// spillConfig is config of write-ahead spill of writer messages to local directory
+type spillConfig struct {
+	// Dir of spill files. Spill disabled if Dir is empty.
+	Dir string
+
+	// MaxBytes is disk quota of spill files, zero means no limit
+	MaxBytes int64
+
+	// SyncInterval is interval of fsync spill files. Zero means fsync on every write.
+	SyncInterval time.Duration
+
+	// NoSync disable fsync spill files, rely on OS page cache flushing
+	NoSync bool
+}
+
+func (cfg *spillConfig) Enabled() bool {
+	return cfg.Dir != ""
+}
+
+type spillRecord struct {
+	SeqNo     int64
+	CreatedAt time.Time
+	Metadata  map[string][]byte
+	Data      []byte
+}
+
+type spillSegment struct {
+	id       int64
+	size     int64
+	maxSeqNo int64
+}
+
+type spillCursor struct {
+	segmentID int64
+	offset    int64
+}
+
+// spillStorage is write-ahead log of writer messages in local directory.
+// Messages are appended to segment files and removed with segment after ack from server.
+// Not acked messages read again after restart of the process.
+type spillStorage struct {
+	cfg spillConfig
+
+	newRecords xsync.EventBroadcast
+	acked      xsync.EventBroadcast
+
+	m          sync.Mutex
+	closed     bool
+	segments   []*spillSegment
+	file       *os.File
+	needSync   bool
+	totalBytes int64
+	lastSeqNo  int64
+	ackedSeqNo int64
+
+	cursor     spillCursor
+	readFile   *os.File
+	readBuffer *bufio.Reader
+}
+
+func openSpillStorage(cfg spillConfig) (*spillStorage, error) {
+	if err := os.MkdirAll(cfg.Dir, spillDirPermissions); err != nil {
+		return nil, xerrors.WithStackTrace(fmt.Errorf("ydb: failed to create topic writer spill dir: %w", err))
+	}
+
+	s := &spillStorage{
+		cfg:        cfg,
+		lastSeqNo:  -1,
+		ackedSeqNo: -1,
+	}
+
+	entries, err := os.ReadDir(cfg.Dir)
+	if err != nil {
+		return nil, xerrors.WithStackTrace(err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, spillSegmentExt) {
+			continue
+		}
+		id, err := strconv.ParseInt(strings.TrimSuffix(name, spillSegmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		s.segments = append(s.segments, &spillSegment{id: id})
+	}
+	sort.Slice(s.segments, func(i, j int) bool {
+		return s.segments[i].id < s.segments[j].id
+	})
+
+	for i, segment := range s.segments {
+		if err = s.recoverSegment(segment, i == len(s.segments)-1); err != nil {
+			return nil, err
+		}
+		s.totalBytes += segment.size
+		if segment.maxSeqNo > s.lastSeqNo {
+			s.lastSeqNo = segment.maxSeqNo
+		}
+	}
+
+	if len(s.segments) > 0 {
+		s.cursor.segmentID = s.segments[0].id
+	}
+
+	return s, nil
+}
+
+func (s *spillStorage) segmentPath(id int64) string {
+	return filepath.Join(s.cfg.Dir, fmt.Sprintf("%020d%s", id, spillSegmentExt))
+}
+
+// recoverSegment reads all records of the segment. Broken tail of the last segment was written
+// before crash and truncated, broken record in other segments is error: records after it would be lost.
+func (s *spillStorage) recoverSegment(segment *spillSegment, isLast bool) error {
+	f, err := os.OpenFile(s.segmentPath(segment.id), os.O_RDWR, spillFilePermissions)
+	if err != nil {
+		return xerrors.WithStackTrace(err)
+	}
+	defer f.Close()
+
+	segment.maxSeqNo = -1
+	reader := bufio.NewReader(f)
+	for {
+		record, size, err := readSpillRecord(reader)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if isLast && (errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, errSpillBrokenRecord)) {
+				break
+			}
+
+			return xerrors.WithStackTrace(fmt.Errorf("ydb: failed to recover topic writer spill segment %v at offset %v: %w",
+				s.segmentPath(segment.id), segment.size, err,
+			))
+		}
+		segment.size += size
+		segment.maxSeqNo = record.SeqNo
+	}
+
+	if err = f.Truncate(segment.size); err != nil {
+		return xerrors.WithStackTrace(err)
+	}
+
+	return nil
+}
+
+// Append writes records to the spill. Records must be ordered by SeqNo.
+func (s *spillStorage) Append(records []spillRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	var buf []byte
+	for i := range records {
+		recordStart := len(buf)
+		buf = appendSpillRecord(buf, &records[i])
+		if len(buf)-recordStart > spillSegmentMaxBytes {
+			return xerrors.WithStackTrace(fmt.Errorf("ydb: message with seqno %v is too large for topic writer spill: %w",
+				records[i].SeqNo, errLargeMessage,
+			))
+		}
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.closed {
+		return xerrors.WithStackTrace(errSpillClosed)
+	}
+
+	if s.quotaExceededNeedLock(len(buf)) {
+		if err := s.removeAckedTailNeedLock(); err != nil {
+			return err
+		}
+		if s.quotaExceededNeedLock(len(buf)) {
+			return xerrors.WithStackTrace(fmt.Errorf("%w: used %v bytes, need %v bytes, quota %v bytes",
+				ErrPublicSpillQuotaExceeded, s.totalBytes, len(buf), s.cfg.MaxBytes,
+			))
+		}
+	}
+
+	segment, err := s.writeSegmentNeedLock(int64(len(buf)))
+	if err != nil {
+		return err
+	}
+
+	if _, err = s.file.Write(buf); err != nil {
+		return xerrors.WithStackTrace(fmt.Errorf("ydb: failed to write topic writer spill: %w", err))
+	}
+	segment.size += int64(len(buf))
+	segment.maxSeqNo = records[len(records)-1].SeqNo
+	s.totalBytes += int64(len(buf))
+	s.lastSeqNo = segment.maxSeqNo
+
+	if !s.cfg.NoSync {
+		if s.cfg.SyncInterval == 0 {
+			if err = s.file.Sync(); err != nil {
+				return xerrors.WithStackTrace(err)
+			}
+		} else {
+			s.needSync = true
+		}
+	}
+
+	s.newRecords.Broadcast()
+
+	return nil
+}
+
+func (s *spillStorage) quotaExceededNeedLock(appendBytes int) bool {
+	return s.cfg.MaxBytes > 0 && s.totalBytes+int64(appendBytes) > s.cfg.MaxBytes
+}
+
+// writeSegmentNeedLock returns segment for append, it starts new segment when last segment is full
+func (s *spillStorage) writeSegmentNeedLock(appendBytes int64) (*spillSegment, error) {
+	var last *spillSegment
+	if len(s.segments) > 0 {
+		last = s.segments[len(s.segments)-1]
+	}
+
+	if last == nil || (last.size > 0 && last.size+appendBytes > spillSegmentMaxBytes) {
+		if s.file != nil {
+			if err := s.closeWriteFileNeedLock(); err != nil {
+				return nil, err
+			}
+		}
+
+		var id int64
+		if last != nil {
+			id = last.id + 1
+		}
+		last = &spillSegment{id: id, maxSeqNo: -1}
+		s.segments = append(s.segments, last)
+	}
+
+	if s.file == nil {
+		f, err := os.OpenFile(s.segmentPath(last.id), os.O_CREATE|os.O_WRONLY|os.O_APPEND, spillFilePermissions)
+		if err != nil {
+			return nil, xerrors.WithStackTrace(fmt.Errorf("ydb: failed to open topic writer spill file: %w", err))
+		}
+		s.file = f
+	}
+
+	return last, nil
+}
+
+func (s *spillStorage) closeWriteFileNeedLock() error {
+	f := s.file
+	s.file = nil
+
+	var syncErr error
+	if !s.cfg.NoSync {
+		syncErr = f.Sync()
+	}
+	s.needSync = false
+	closeErr := f.Close()
+
+	if syncErr != nil {
+		return xerrors.WithStackTrace(syncErr)
+	}
+	if closeErr != nil {
+		return xerrors.WithStackTrace(closeErr)
+	}
+
+	return nil
+}
+
+// removeAckedTailNeedLock removes last segment if all its records acked.
+// The last segment kept on ack for restore LastSeqNo after restart, but it may be removed
+// before append new records.
+func (s *spillStorage) removeAckedTailNeedLock() error {
+	if len(s.segments) == 0 {
+		return nil
+	}
+	last := s.segments[len(s.segments)-1]
+	if last.size == 0 || last.maxSeqNo > s.ackedSeqNo {
+		return nil
+	}
+
+	if s.file != nil {
+		if err := s.closeWriteFileNeedLock(); err != nil {
+			return err
+		}
+	}
+	if err := s.removeSegmentNeedLock(last); err != nil {
+		return err
+	}
+	s.segments = s.segments[:len(s.segments)-1]
+
+	// keep segment id sequence for new segments
+	s.segments = append(s.segments, &spillSegment{id: last.id + 1, maxSeqNo: -1})
+
+	return nil
+}
+
+func (s *spillStorage) removeSegmentNeedLock(segment *spillSegment) error {
+	if segment.id == s.cursor.segmentID {
+		s.closeReadFileNeedLock()
+	}
+	if err := os.Remove(s.segmentPath(segment.id)); err != nil && !os.IsNotExist(err) {
+		return xerrors.WithStackTrace(err)
+	}
+	s.totalBytes -= segment.size
+
+	return nil
+}
+
+// Sync flushes appended records to disk, if sync by interval configured
+func (s *spillStorage) Sync() error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if !s.needSync || s.file == nil {
+		return nil
+	}
+	s.needSync = false
+
+	return xerrors.WithStackTrace(s.file.Sync())
+}
+
+// ReadNext returns up to maxCount not acked records after previous read
+func (s *spillStorage) ReadNext(maxCount int) ([]spillRecord, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.closed {
+		return nil, xerrors.WithStackTrace(errSpillClosed)
+	}
+
+	var res []spillRecord
+	for len(res) < maxCount {
+		segment := s.findSegmentNeedLock(s.cursor.segmentID)
+		if segment == nil || s.cursor.offset >= segment.size {
+			next := s.nextSegmentNeedLock(s.cursor.segmentID)
+			if next == nil {
+				break
+			}
+			s.closeReadFileNeedLock()
+			s.cursor = spillCursor{segmentID: next.id}
+
+			continue
+		}
+
+		if s.readFile == nil {
+			f, err := os.Open(s.segmentPath(segment.id))
+			if err != nil {
+				return nil, xerrors.WithStackTrace(err)
+			}
+			if _, err = f.Seek(s.cursor.offset, io.SeekStart); err != nil {
+				_ = f.Close()
+
+				return nil, xerrors.WithStackTrace(err)
+			}
+			s.readFile = f
+			s.readBuffer = bufio.NewReader(f)
+		}
+
+		record, size, err := readSpillRecord(s.readBuffer)
+		if err != nil {
+			return nil, err
+		}
+		s.cursor.offset += size
+
+		if record.SeqNo > s.ackedSeqNo {
+			res = append(res, record)
+		}
+	}
+
+	return res, nil
+}
+
+func (s *spillStorage) findSegmentNeedLock(id int64) *spillSegment {
+	for _, segment := range s.segments {
+		if segment.id == id {
+			return segment
+		}
+	}
+
+	return nil
+}
+
+func (s *spillStorage) nextSegmentNeedLock(id int64) *spillSegment {
+	for _, segment := range s.segments {
+		if segment.id > id {
+			return segment
+		}
+	}
+
+	return nil
+}
+
+func (s *spillStorage) closeReadFileNeedLock() {
+	if s.readFile != nil {
+		_ = s.readFile.Close()
+		s.readFile = nil
+		s.readBuffer = nil
+	}
+}
+
+// Ack marks records with SeqNo up to seqNo as delivered and removes fully acked segments.
+// Last segment isn't removed for restore LastSeqNo after restart.
+func (s *spillStorage) Ack(seqNo int64) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if seqNo <= s.ackedSeqNo {
+		return
+	}
+	s.ackedSeqNo = seqNo
+	defer s.acked.Broadcast()
+
+	if s.closed {
+		return
+	}
+
+	for len(s.segments) > 1 && s.segments[0].maxSeqNo <= seqNo {
+		if err := s.removeSegmentNeedLock(s.segments[0]); err != nil {
+			// the segment will be removed with next ack
+			return
+		}
+		s.segments = s.segments[1:]
+	}
+}
+
+// LastSeqNo returns SeqNo of last appended record, -1 if the spill is empty
+func (s *spillStorage) LastSeqNo() int64 {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return s.lastSeqNo
+}
+
+// NewRecordsWaiter returns waiter, which will be notified on next append
+func (s *spillStorage) NewRecordsWaiter() xsync.OneTimeWaiter {
+	return s.newRecords.Waiter()
+}
+
+// WaitAcked waits ack of all records up to seqNo
+func (s *spillStorage) WaitAcked(ctx context.Context, seqNo int64) error {
+	for {
+		waiter := s.acked.Waiter()
+
+		s.m.Lock()
+		ackedSeqNo, closed := s.ackedSeqNo, s.closed
+		s.m.Unlock()
+
+		if ackedSeqNo >= seqNo {
+			return nil
+		}
+		if closed {
+			return xerrors.WithStackTrace(errSpillClosed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-waiter.Done():
+		}
+	}
+}
+
+// Close flushes and closes spill files, not acked records will be read after reopen the spill
+func (s *spillStorage) Close() error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	s.closeReadFileNeedLock()
+	s.acked.Broadcast()
+
+	if s.file != nil {
+		return s.closeWriteFileNeedLock()
+	}
+
+	return nil
+}
+
+// record format: payload len (uint32), payload crc32 (uint32), payload
+// payload: seqno (int64), created at unix nano (int64), metadata count (uint32),
+// metadata items: key len (uint32), key, value len (uint32), value, message data
+func appendSpillRecord(buf []byte, record *spillRecord) []byte {
+	headerPos := len(buf)
+	buf = append(buf, make([]byte, spillHeaderSize)...)
+	payloadPos := len(buf)
+
+	buf = binary.LittleEndian.AppendUint64(buf, uint64(record.SeqNo))
+	var createdAt int64
+	if !record.CreatedAt.IsZero() {
+		createdAt = record.CreatedAt.UnixNano()
+	}
+	buf = binary.LittleEndian.AppendUint64(buf, uint64(createdAt))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(record.Metadata)))
+	for k, v := range record.Metadata {
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(k)))
+		buf = append(buf, k...)
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(v)))
+		buf = append(buf, v...)
+	}
+	buf = append(buf, record.Data...)
+
+	payload := buf[payloadPos:]
+	binary.LittleEndian.PutUint32(buf[headerPos:], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(buf[headerPos+4:], crc32.ChecksumIEEE(payload))
+
+	return buf
+}
+
+func readSpillRecord(reader io.Reader) (record spillRecord, size int64, _ error) {
+	var header [spillHeaderSize]byte
+	if _, err := io.ReadFull(reader, header[:]); err != nil {
+		return record, 0, err
+	}
+	payloadSize := binary.LittleEndian.Uint32(header[:])
+	if payloadSize > spillSegmentMaxBytes-spillHeaderSize {
+		// length of broken record, check it before allocate buffer
+		return record, 0, xerrors.WithStackTrace(errSpillBrokenRecord)
+	}
+	payload := make([]byte, payloadSize)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return record, 0, err
+	}
+	if crc32.ChecksumIEEE(payload) != binary.LittleEndian.Uint32(header[4:]) {
+		return record, 0, xerrors.WithStackTrace(errSpillBrokenRecord)
+	}
+
+	const fixedSize = 8 + 8 + 4
+	if len(payload) < fixedSize {
+		return record, 0, xerrors.WithStackTrace(errSpillBrokenRecord)
+	}
+	record.SeqNo = int64(binary.LittleEndian.Uint64(payload))
+	if createdAt := int64(binary.LittleEndian.Uint64(payload[8:])); createdAt != 0 {
+		record.CreatedAt = time.Unix(0, createdAt)
+	}
+	metadataCount := int(binary.LittleEndian.Uint32(payload[16:]))
+	rest := payload[fixedSize:]
+
+	readBytes := func() ([]byte, bool) {
+		if len(rest) < 4 { //nolint:gomnd
+			return nil, false
+		}
+		l := int(binary.LittleEndian.Uint32(rest))
+		rest = rest[4:]
+		if len(rest) < l {
+			return nil, false
+		}
+		res := rest[:l:l]
+		rest = rest[l:]
+
+		return res, true
+	}
+
+	if metadataCount > len(rest)/8 { //nolint:mnd // every metadata item has lengths of key and value
+		return record, 0, xerrors.WithStackTrace(errSpillBrokenRecord)
+	}
+	if metadataCount > 0 {
+		record.Metadata = make(map[string][]byte, metadataCount)
+	}
+	for i := 0; i < metadataCount; i++ {
+		key, ok := readBytes()
+		if !ok {
+			return record, 0, xerrors.WithStackTrace(errSpillBrokenRecord)
+		}
+		value, ok := readBytes()
+		if !ok {
+			return record, 0, xerrors.WithStackTrace(errSpillBrokenRecord)
+		}
+		record.Metadata[string(key)] = value
+	}
+	record.Data = rest
+
+	return record, int64(spillHeaderSize + len(payload)), nil
+}
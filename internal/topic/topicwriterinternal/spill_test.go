@@ -0,0 +1,317 @@
+package topicwriterinternal
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/empty"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/grpcwrapper/rawtopic/rawtopiccommon"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/grpcwrapper/rawtopic/rawtopicwriter"
+	xtest "github.com/ydb-platform/ydb-go-sdk/v3/pkg/xtest"
+)
+
+func newTestSpillRecords(seqNumbers ...int64) []spillRecord {
+	res := make([]spillRecord, len(seqNumbers))
+	for i, seqNo := range seqNumbers {
+		res[i] = spillRecord{
+			SeqNo:     seqNo,
+			CreatedAt: time.Unix(0, seqNo),
+			Data:      []byte{byte(seqNo)},
+		}
+	}
+
+	return res
+}
+
+func spillRecordsSeqNumbers(records []spillRecord) []int64 {
+	res := make([]int64, len(records))
+	for i := range records {
+		res[i] = records[i].SeqNo
+	}
+
+	return res
+}
+
+func TestSpillStorage(t *testing.T) {
+	t.Run("AppendRead", func(t *testing.T) {
+		s, err := openSpillStorage(spillConfig{Dir: t.TempDir()})
+		require.NoError(t, err)
+		defer func() { _ = s.Close() }()
+
+		require.Equal(t, int64(-1), s.LastSeqNo())
+
+		records := newTestSpillRecords(1, 2, 3)
+		records[1].Metadata = map[string][]byte{"key": []byte("val")}
+		require.NoError(t, s.Append(records))
+		require.Equal(t, int64(3), s.LastSeqNo())
+
+		res, err := s.ReadNext(2)
+		require.NoError(t, err)
+		require.Equal(t, records[:2], res)
+
+		res, err = s.ReadNext(10)
+		require.NoError(t, err)
+		require.Equal(t, records[2:], res)
+
+		res, err = s.ReadNext(10)
+		require.NoError(t, err)
+		require.Empty(t, res)
+	})
+	t.Run("Reopen", func(t *testing.T) {
+		dir := t.TempDir()
+		s, err := openSpillStorage(spillConfig{Dir: dir})
+		require.NoError(t, err)
+		require.NoError(t, s.Append(newTestSpillRecords(1, 2, 3)))
+		res, err := s.ReadNext(10)
+		require.NoError(t, err)
+		require.Len(t, res, 3)
+		s.Ack(1)
+		require.NoError(t, s.Close())
+
+		s, err = openSpillStorage(spillConfig{Dir: dir})
+		require.NoError(t, err)
+		defer func() { _ = s.Close() }()
+
+		require.Equal(t, int64(3), s.LastSeqNo())
+
+		// acked state lost after restart, server deduplicate messages by seqno
+		s.Ack(2)
+		res, err = s.ReadNext(10)
+		require.NoError(t, err)
+		require.Equal(t, []int64{3}, spillRecordsSeqNumbers(res))
+	})
+	t.Run("BrokenTail", func(t *testing.T) {
+		dir := t.TempDir()
+		s, err := openSpillStorage(spillConfig{Dir: dir})
+		require.NoError(t, err)
+		require.NoError(t, s.Append(newTestSpillRecords(1, 2)))
+		require.NoError(t, s.Close())
+
+		// partial write of record before crash
+		f, err := os.OpenFile(filepath.Join(dir, "00000000000000000000.wal"), os.O_WRONLY|os.O_APPEND, 0)
+		require.NoError(t, err)
+		_, err = f.Write(appendSpillRecord(nil, &newTestSpillRecords(3)[0])[:5])
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		s, err = openSpillStorage(spillConfig{Dir: dir})
+		require.NoError(t, err)
+		defer func() { _ = s.Close() }()
+
+		require.Equal(t, int64(2), s.LastSeqNo())
+		require.NoError(t, s.Append(newTestSpillRecords(3)))
+
+		res, err := s.ReadNext(10)
+		require.NoError(t, err)
+		require.Equal(t, []int64{1, 2, 3}, spillRecordsSeqNumbers(res))
+	})
+	t.Run("BrokenMiddleSegment", func(t *testing.T) {
+		dir := t.TempDir()
+		s, err := openSpillStorage(spillConfig{Dir: dir})
+		require.NoError(t, err)
+		require.NoError(t, s.Append(newTestSpillRecords(1, 2)))
+		require.NoError(t, s.Close())
+
+		segment := filepath.Join(dir, "00000000000000000000.wal")
+		content, err := os.ReadFile(segment)
+		require.NoError(t, err)
+		content[len(content)-1]++
+		require.NoError(t, os.WriteFile(segment, content, 0o600))
+		require.NoError(t, os.WriteFile(
+			filepath.Join(dir, "00000000000000000001.wal"),
+			appendSpillRecord(nil, &newTestSpillRecords(3)[0]),
+			0o600,
+		))
+
+		_, err = openSpillStorage(spillConfig{Dir: dir})
+		require.ErrorIs(t, err, errSpillBrokenRecord)
+	})
+	t.Run("BrokenRecordLength", func(t *testing.T) {
+		record := appendSpillRecord(nil, &newTestSpillRecords(1)[0])
+		binary.LittleEndian.PutUint32(record, math.MaxUint32)
+
+		_, _, err := readSpillRecord(bytes.NewReader(record))
+		require.ErrorIs(t, err, errSpillBrokenRecord)
+	})
+	t.Run("Quota", func(t *testing.T) {
+		recordSize := int64(len(appendSpillRecord(nil, &newTestSpillRecords(1)[0])))
+		s, err := openSpillStorage(spillConfig{Dir: t.TempDir(), MaxBytes: recordSize * 2})
+		require.NoError(t, err)
+		defer func() { _ = s.Close() }()
+
+		require.NoError(t, s.Append(newTestSpillRecords(1, 2)))
+		require.ErrorIs(t, s.Append(newTestSpillRecords(3)), ErrPublicSpillQuotaExceeded)
+
+		// acked tail removed for free space
+		_, err = s.ReadNext(10)
+		require.NoError(t, err)
+		s.Ack(2)
+		require.NoError(t, s.Append(newTestSpillRecords(3)))
+		require.Equal(t, int64(3), s.LastSeqNo())
+
+		res, err := s.ReadNext(10)
+		require.NoError(t, err)
+		require.Equal(t, []int64{3}, spillRecordsSeqNumbers(res))
+	})
+	t.Run("WaitAcked", func(t *testing.T) {
+		ctx := xtest.Context(t)
+		s, err := openSpillStorage(spillConfig{Dir: t.TempDir(), NoSync: true})
+		require.NoError(t, err)
+		require.NoError(t, s.Append(newTestSpillRecords(1, 2)))
+
+		waitErr := make(chan error, 1)
+		go func() {
+			waitErr <- s.WaitAcked(ctx, 2)
+		}()
+
+		s.Ack(1)
+		select {
+		case <-waitErr:
+			t.Fatal("wait must complete after ack of all messages")
+		case <-time.After(10 * time.Millisecond):
+			// pass
+		}
+
+		s.Ack(2)
+		require.NoError(t, <-waitErr)
+
+		require.NoError(t, s.Close())
+		require.ErrorIs(t, s.WaitAcked(ctx, 3), errSpillClosed)
+	})
+}
+
+func TestWriterReconnector_Spill(t *testing.T) {
+	dir := t.TempDir()
+
+	// messages from previous run of the writer
+	s, err := openSpillStorage(spillConfig{Dir: dir})
+	require.NoError(t, err)
+	require.NoError(t, s.Append(newTestSpillRecords(1, 2, 3)))
+	require.NoError(t, s.Close())
+
+	initRequested := make(empty.Chan)
+	e := newTestEnv(t, &testEnvOptions{
+		writerOptions: []PublicWriterOption{
+			WithSpillDir(dir),
+			WithAutoSetSeqNo(true),
+		},
+		customInitRequestHandler: func(_ *testEnv, _ *rawtopicwriter.InitRequest) {
+			close(initRequested)
+		},
+		skipWaitInitResponse: true,
+	})
+	<-initRequested
+
+	sent := make(chan []int64, 2)
+	e.stream.EXPECT().Send(gomock.AssignableToTypeOf(&rawtopicwriter.WriteRequest{})).DoAndReturn(
+		func(msg rawtopicwriter.ClientMessage) error {
+			req := msg.(*rawtopicwriter.WriteRequest)
+			seqNumbers := make([]int64, len(req.Messages))
+			for i := range req.Messages {
+				seqNumbers[i] = req.Messages[i].SeqNo
+			}
+			sent <- seqNumbers
+
+			return nil
+		},
+	).AnyTimes()
+
+	e.sendFromServer(&rawtopicwriter.InitResult{
+		LastSeqNo:       2,
+		SessionID:       "session-" + t.Name(),
+		PartitionID:     e.partitionID,
+		SupportedCodecs: rawtopiccommon.SupportedCodecs{rawtopiccommon.CodecRaw},
+	})
+
+	ack := func(seqNo int64) {
+		e.sendFromServer(&rawtopicwriter.WriteResult{
+			Acks: []rawtopicwriter.WriteAck{
+				{
+					SeqNo: seqNo,
+					MessageWriteStatus: rawtopicwriter.MessageWriteStatus{
+						Type:          rawtopicwriter.WriteStatusTypeWritten,
+						WrittenOffset: seqNo,
+					},
+				},
+			},
+			PartitionID: e.partitionID,
+		})
+	}
+
+	// already written messages skipped
+	require.Equal(t, []int64{3}, <-sent)
+	ack(3)
+	require.NoError(t, e.writer.Flush(e.ctx))
+
+	require.NoError(t, e.writer.Write(e.ctx, []PublicMessage{{Data: bytes.NewReader([]byte{4})}}))
+	require.Equal(t, []int64{4}, <-sent)
+	ack(4)
+	require.NoError(t, e.writer.Flush(e.ctx))
+	require.Equal(t, int64(4), e.writer.spill.LastSeqNo())
+}
+
+func TestWriterReconnector_SpillWriteBeforeConnect(t *testing.T) {
+	t.Run("SeqNoFromSpill", func(t *testing.T) {
+		dir := t.TempDir()
+		s, err := openSpillStorage(spillConfig{Dir: dir})
+		require.NoError(t, err)
+		require.NoError(t, s.Append(newTestSpillRecords(1, 2)))
+		require.NoError(t, s.Close())
+
+		e := newTestEnv(t, &testEnvOptions{
+			writerOptions: []PublicWriterOption{
+				WithSpillDir(dir),
+				WithAutoSetSeqNo(true),
+			},
+			customInitRequestHandler: func(*testEnv, *rawtopicwriter.InitRequest) {
+				// server doesn't respond
+			},
+			skipWaitInitResponse: true,
+		})
+
+		require.NoError(t, e.writer.Write(e.ctx, []PublicMessage{{Data: bytes.NewReader([]byte{3})}}))
+
+		records, err := e.writer.spill.ReadNext(10)
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+		require.Equal(t, int64(3), records[2].SeqNo)
+	})
+	t.Run("EmptySpillWaitsLastSeqNoFromServer", func(t *testing.T) {
+		initRequested := make(empty.Chan)
+		e := newTestEnv(t, &testEnvOptions{
+			writerOptions: []PublicWriterOption{
+				WithSpillDir(t.TempDir()),
+				WithAutoSetSeqNo(true),
+			},
+			customInitRequestHandler: func(*testEnv, *rawtopicwriter.InitRequest) {
+				close(initRequested)
+			},
+			skipWaitInitResponse: true,
+		})
+		<-initRequested
+
+		ctx, cancel := context.WithTimeout(e.ctx, 10*time.Millisecond)
+		defer cancel()
+		err := e.writer.Write(ctx, []PublicMessage{{Data: bytes.NewReader([]byte{1})}})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		e.stream.EXPECT().Send(gomock.AssignableToTypeOf(&rawtopicwriter.WriteRequest{})).Return(nil).AnyTimes()
+		e.sendFromServer(&rawtopicwriter.InitResult{
+			LastSeqNo:       100,
+			SessionID:       "session-" + t.Name(),
+			PartitionID:     e.partitionID,
+			SupportedCodecs: rawtopiccommon.SupportedCodecs{rawtopiccommon.CodecRaw},
+		})
+		require.NoError(t, e.writer.Write(e.ctx, []PublicMessage{{Data: bytes.NewReader([]byte{1})}}))
+		require.Equal(t, int64(101), e.writer.spill.LastSeqNo())
+	})
+}
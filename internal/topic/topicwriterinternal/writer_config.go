@@ -20,6 +20,9 @@ type WritersCommonConfig struct {
 	defaultPartitioning rawtopicwriter.Partitioning
 	compressorCount     int
 	maxBytesPerMessage  int
+	linger              time.Duration
+	maxBatchMessages    int
+	maxBatchBytes       int
 
 	LogContext         context.Context //nolint:containedctx
 	Tracer             *trace.Topic
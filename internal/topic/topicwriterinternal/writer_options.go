@@ -110,12 +110,54 @@ func WithAutosetCreatedTime(enable bool) PublicWriterOption {
 	}
 }
 
+func WithLinger(linger time.Duration) PublicWriterOption {
+	return func(cfg *WriterReconnectorConfig) {
+		cfg.linger = linger
+	}
+}
+
+func WithMaxBatchMessages(num int) PublicWriterOption {
+	return func(cfg *WriterReconnectorConfig) {
+		cfg.maxBatchMessages = num
+	}
+}
+
+func WithMaxBatchBytes(size int) PublicWriterOption {
+	return func(cfg *WriterReconnectorConfig) {
+		cfg.maxBatchBytes = size
+	}
+}
+
 func WithMaxQueueLen(num int) PublicWriterOption {
 	return func(cfg *WriterReconnectorConfig) {
 		cfg.MaxQueueLen = num
 	}
 }
 
+func WithSpillDir(dir string) PublicWriterOption {
+	return func(cfg *WriterReconnectorConfig) {
+		cfg.spill.Dir = dir
+	}
+}
+
+func WithSpillMaxBytes(maxBytes int64) PublicWriterOption {
+	return func(cfg *WriterReconnectorConfig) {
+		cfg.spill.MaxBytes = maxBytes
+	}
+}
+
+func WithSpillSyncInterval(interval time.Duration) PublicWriterOption {
+	return func(cfg *WriterReconnectorConfig) {
+		cfg.spill.SyncInterval = interval
+	}
+}
+
+func WithSpillNoSync(noSync bool) PublicWriterOption {
+	return func(cfg *WriterReconnectorConfig) {
+		cfg.spill.NoSync = noSync
+	}
+}
+
 func WithPartitioning(partitioning PublicFuturePartitioning) PublicWriterOption {
 	return func(cfg *WriterReconnectorConfig) {
 		cfg.defaultPartitioning = partitioning.ToRaw()
@@ -0,0 +1,279 @@
+package topicwriterinternal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+)
+
+var (
+	errWriterPoolClosed  = xerrors.Wrap(errors.New("ydb: writer pool is closed"))
+	errWriterPoolBadSize = xerrors.Wrap(errors.New("ydb: size of writer pool must be positive"))
+
+	errWriterPoolNoProducerIDPrefix = xerrors.Wrap(errors.New(
+		"ydb: writer pool with spill needs stable producer id prefix, set it by WithWriterPoolProducerIDPrefix",
+	))
+)
+
+const defaultWriterPoolSize = 4
+
+type poolWriter interface {
+	keyedPartitionWriter
+	QueueLen() int
+}
+
+type (
+	WriterPoolConfig struct {
+		// Size is count of writers in the pool
+		Size int
+
+		// ProducerIDPrefix is prefix of producer ids of writers, producer id of writer is "<prefix>-<index>".
+		// Random if empty, required if writers use spill: spill of writer is found after restart by producer id.
+		ProducerIDPrefix string
+
+		// WriterOptions used for create each writer of the pool
+		WriterOptions []PublicWriterOption
+
+		// newWriter for tests only
+		newWriter func(producerID string, opts []PublicWriterOption) (poolWriter, error)
+	}
+
+	PublicWriterPoolOption func(cfg *WriterPoolConfig)
+)
+
+func WithWriterPoolSize(size int) PublicWriterPoolOption {
+	return func(cfg *WriterPoolConfig) {
+		cfg.Size = size
+	}
+}
+
+func WithWriterPoolProducerIDPrefix(prefix string) PublicWriterPoolOption {
+	return func(cfg *WriterPoolConfig) {
+		cfg.ProducerIDPrefix = prefix
+	}
+}
+
+func WithWriterPoolWriterOptions(opts ...PublicWriterOption) PublicWriterPoolOption {
+	return func(cfg *WriterPoolConfig) {
+		cfg.WriterOptions = append(cfg.WriterOptions, opts...)
+	}
+}
+
+func NewWriterPoolConfig(opts ...PublicWriterPoolOption) WriterPoolConfig {
+	cfg := WriterPoolConfig{
+		Size: defaultWriterPoolSize,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+
+	return cfg
+}
+
+// WriterPool holds several writers with different producer ids for parallel write to the topic.
+// Write calls go to the writer with the smallest queue, so a reconnecting writer doesn't stall
+// others: its queue grows and new messages go to other writers.
+// Messages with same key always go to the same writer and keep order.
+type WriterPool struct {
+	cfg     WriterPoolConfig
+	writers []*writerPoolItem
+	next    atomic.Uint64
+	closed  atomic.Bool
+}
+
+type writerPoolItem struct {
+	writer poolWriter
+
+	// writing is count of messages in active Write calls, they may wait free space in queue of the writer
+	writing atomic.Int64
+}
+
+func (item *writerPoolItem) load() int64 {
+	return item.writing.Load() + int64(item.writer.QueueLen())
+}
+
+func (item *writerPoolItem) write(ctx context.Context, messages []PublicMessage) error {
+	item.writing.Add(int64(len(messages)))
+	defer item.writing.Add(-int64(len(messages)))
+
+	return item.writer.Write(ctx, messages)
+}
+
+func NewWriterPool(cfg WriterPoolConfig) (*WriterPool, error) { //nolint:gocritic
+	if cfg.Size <= 0 {
+		return nil, xerrors.WithStackTrace(fmt.Errorf("%w: %v", errWriterPoolBadSize, cfg.Size))
+	}
+	if cfg.ProducerIDPrefix == "" {
+		writerCfg := NewWriterReconnectorConfig(cfg.WriterOptions...)
+		if writerCfg.spill.Enabled() {
+			return nil, xerrors.WithStackTrace(errWriterPoolNoProducerIDPrefix)
+		}
+		cfg.ProducerIDPrefix = uuid.NewString()
+	}
+	if cfg.newWriter == nil {
+		cfg.newWriter = newPoolWriterReconnector
+	}
+
+	p := &WriterPool{
+		cfg:     cfg,
+		writers: make([]*writerPoolItem, 0, cfg.Size),
+	}
+	for i := 0; i < cfg.Size; i++ {
+		writer, err := cfg.newWriter(fmt.Sprintf("%s-%d", cfg.ProducerIDPrefix, i), cfg.WriterOptions)
+		if err != nil {
+			_ = p.closeWriters(context.Background())
+
+			return nil, err
+		}
+		p.writers = append(p.writers, &writerPoolItem{writer: writer})
+	}
+
+	return p, nil
+}
+
+func newPoolWriterReconnector(producerID string, opts []PublicWriterOption) (poolWriter, error) {
+	opts = append(opts[:len(opts):len(opts)],
+		WithProducerID(producerID),
+		func(cfg *WriterReconnectorConfig) {
+			// writers of the pool must not share spill files
+			if cfg.spill.Enabled() {
+				cfg.spill.Dir = filepath.Join(cfg.spill.Dir, producerID)
+			}
+		},
+	)
+
+	return NewWriterReconnector(NewWriterReconnectorConfig(opts...))
+}
+
+// Write sends all messages with the least loaded writer, order of the messages is kept
+func (p *WriterPool) Write(ctx context.Context, messages []PublicMessage) error {
+	if p.closed.Load() {
+		return xerrors.WithStackTrace(errWriterPoolClosed)
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	return p.leastLoaded().write(ctx, messages)
+}
+
+// WriteKeyed sends messages with same key by same writer in order of the messages.
+// Messages with empty key are sent by the least loaded writer.
+func (p *WriterPool) WriteKeyed(ctx context.Context, messages []PublicKeyedMessage) error {
+	if p.closed.Load() {
+		return xerrors.WithStackTrace(errWriterPoolClosed)
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	var (
+		order   []*writerPoolItem
+		batches = make(map[*writerPoolItem][]PublicMessage)
+		free    *writerPoolItem
+	)
+	for i := range messages {
+		var item *writerPoolItem
+		if messages[i].Key == "" {
+			if free == nil {
+				free = p.leastLoaded()
+			}
+			item = free
+		} else {
+			item = p.writers[p.writerIndexForKey(messages[i].Key)]
+		}
+
+		if _, has := batches[item]; !has {
+			order = append(order, item)
+		}
+		batches[item] = append(batches[item], messages[i].PublicMessage)
+	}
+
+	for _, item := range order {
+		if err := item.write(ctx, batches[item]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ProducerIDForKey returns producer id of writer, which sends messages with the key
+func (p *WriterPool) ProducerIDForKey(key string) string {
+	return fmt.Sprintf("%s-%d", p.cfg.ProducerIDPrefix, p.writerIndexForKey(key))
+}
+
+func (p *WriterPool) writerIndexForKey(key string) int {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+
+	return int(h.Sum64() % uint64(len(p.writers)))
+}
+
+// leastLoaded returns writer with the smallest count of not acked messages.
+// Search starts from next writer on every call for spread equal loaded writers.
+func (p *WriterPool) leastLoaded() *writerPoolItem {
+	start := int(p.next.Add(1) % uint64(len(p.writers)))
+
+	best := p.writers[start]
+	bestLoad := best.load()
+	for i := 1; i < len(p.writers) && bestLoad > 0; i++ {
+		item := p.writers[(start+i)%len(p.writers)]
+		if load := item.load(); load < bestLoad {
+			best, bestLoad = item, load
+		}
+	}
+
+	return best
+}
+
+// Flush waits till all in-flight messages of all writers are acknowledged
+func (p *WriterPool) Flush(ctx context.Context) error {
+	return p.forEachWriter(func(writer poolWriter) error {
+		return writer.Flush(ctx)
+	})
+}
+
+// Close flushes messages of all writers and close them
+func (p *WriterPool) Close(ctx context.Context) error {
+	if !p.closed.CompareAndSwap(false, true) {
+		return xerrors.WithStackTrace(errWriterPoolClosed)
+	}
+
+	return p.closeWriters(ctx)
+}
+
+func (p *WriterPool) closeWriters(ctx context.Context) error {
+	return p.forEachWriter(func(writer poolWriter) error {
+		return writer.Close(ctx)
+	})
+}
+
+// forEachWriter calls f for all writers in parallel, so slow writer doesn't delay others
+func (p *WriterPool) forEachWriter(f func(writer poolWriter) error) error {
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, len(p.writers))
+	)
+	for i := range p.writers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			errs[i] = f(p.writers[i].writer)
+		}(i)
+	}
+	wg.Wait()
+
+	return xerrors.Join(errs...)
+}
@@ -0,0 +1,167 @@
+package topicwriterinternal
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/empty"
+	"github.com/ydb-platform/ydb-go-sdk/v3/pkg/xtest"
+)
+
+type fakePoolWriter struct {
+	fakePartitionWriter
+
+	producerID string
+	queueLen   int
+}
+
+func (w *fakePoolWriter) QueueLen() int {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	return w.queueLen + len(w.messages)
+}
+
+func (w *fakePoolWriter) written() []string {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	return append([]string(nil), w.messages...)
+}
+
+func newTestWriterPool(t *testing.T, opts ...PublicWriterPoolOption) (*WriterPool, []*fakePoolWriter) {
+	var writers []*fakePoolWriter
+
+	cfg := NewWriterPoolConfig(append([]PublicWriterPoolOption{
+		WithWriterPoolSize(3),
+		WithWriterPoolProducerIDPrefix("producer"),
+	}, opts...)...)
+	cfg.newWriter = func(producerID string, _ []PublicWriterOption) (poolWriter, error) {
+		w := &fakePoolWriter{producerID: producerID}
+		writers = append(writers, w)
+
+		return w, nil
+	}
+
+	p, err := NewWriterPool(cfg)
+	require.NoError(t, err)
+
+	return p, writers
+}
+
+func poolMessages(data ...string) []PublicMessage {
+	messages := make([]PublicMessage, len(data))
+	for i := range data {
+		messages[i] = PublicMessage{Data: strings.NewReader(data[i])}
+	}
+
+	return messages
+}
+
+func TestWriterPool(t *testing.T) {
+	t.Run("ProducerIDs", func(t *testing.T) {
+		_, writers := newTestWriterPool(t)
+
+		require.Len(t, writers, 3)
+		for i, producerID := range []string{"producer-0", "producer-1", "producer-2"} {
+			require.Equal(t, producerID, writers[i].producerID)
+		}
+	})
+	t.Run("BadSize", func(t *testing.T) {
+		_, err := NewWriterPool(NewWriterPoolConfig(WithWriterPoolSize(0)))
+		require.ErrorIs(t, err, errWriterPoolBadSize)
+	})
+	t.Run("SpillNeedsProducerIDPrefix", func(t *testing.T) {
+		_, err := NewWriterPool(NewWriterPoolConfig(
+			WithWriterPoolWriterOptions(WithSpillDir(t.TempDir())),
+		))
+		require.ErrorIs(t, err, errWriterPoolNoProducerIDPrefix)
+	})
+	t.Run("BalanceByQueueLen", func(t *testing.T) {
+		ctx := xtest.Context(t)
+		p, writers := newTestWriterPool(t)
+		writers[0].queueLen = 10
+		writers[2].queueLen = 5
+
+		require.NoError(t, p.Write(ctx, poolMessages("1", "2")))
+		require.Equal(t, []string{"1", "2"}, writers[1].written())
+
+		require.NoError(t, p.Write(ctx, poolMessages("3", "4", "5")))
+		require.NoError(t, p.Write(ctx, poolMessages("6")))
+		require.Equal(t, []string{"1", "2", "3", "4", "5", "6"}, writers[1].written())
+
+		require.NoError(t, p.Write(ctx, poolMessages("7")))
+		require.Equal(t, []string{"7"}, writers[2].written())
+		require.Empty(t, writers[0].written())
+	})
+	t.Run("BlockedWriterDoesNotStallOthers", func(t *testing.T) {
+		ctx := xtest.Context(t)
+		p, writers := newTestWriterPool(t)
+		writers[0].blockWrite = make(empty.Chan)
+		writers[1].queueLen = 1
+		writers[2].queueLen = 1
+
+		// first write go to writer 0 (empty queue) and blocks as during reconnect
+		p.next.Store(2)
+		blockedWrite := make(empty.Chan)
+		go func() {
+			defer close(blockedWrite)
+
+			_ = p.Write(ctx, poolMessages("b1", "b2", "b3", "b4", "b5"))
+		}()
+		xtest.SpinWaitCondition(t, nil, func() bool {
+			return p.writers[0].writing.Load() == 5
+		})
+
+		for i := 0; i < 4; i++ {
+			require.NoError(t, p.Write(ctx, poolMessages("free")))
+		}
+		require.Len(t, writers[1].written(), 2)
+		require.Len(t, writers[2].written(), 2)
+
+		close(writers[0].blockWrite)
+		xtest.WaitChannelClosed(t, blockedWrite)
+		require.Equal(t, []string{"b1", "b2", "b3", "b4", "b5"}, writers[0].written())
+	})
+	t.Run("WriteKeyed", func(t *testing.T) {
+		ctx := xtest.Context(t)
+		p, writers := newTestWriterPool(t)
+
+		for round := 0; round < 2; round++ {
+			var messages []PublicKeyedMessage
+			for i := 0; i < 20; i++ {
+				key := string(rune('a' + i%10))
+				messages = append(messages, PublicKeyedMessage{
+					Key:           key,
+					PublicMessage: PublicMessage{Data: strings.NewReader(key)},
+				})
+			}
+			require.NoError(t, p.WriteKeyed(ctx, messages))
+		}
+
+		written := 0
+		for i, w := range writers {
+			for _, key := range w.written() {
+				require.Equal(t, w.producerID, p.ProducerIDForKey(key), i)
+				written++
+			}
+		}
+		require.Equal(t, 40, written)
+	})
+	t.Run("FlushClose", func(t *testing.T) {
+		ctx := xtest.Context(t)
+		p, writers := newTestWriterPool(t)
+
+		require.NoError(t, p.Flush(ctx))
+		require.NoError(t, p.Close(ctx))
+		for _, w := range writers {
+			require.True(t, w.flushed)
+			require.True(t, w.closed)
+		}
+
+		require.ErrorIs(t, p.Write(ctx, poolMessages("1")), errWriterPoolClosed)
+		require.ErrorIs(t, p.Close(ctx), errWriterPoolClosed)
+	})
+}
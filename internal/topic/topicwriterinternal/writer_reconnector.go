@@ -1,10 +1,12 @@
 package topicwriterinternal
 
 import (
+	"bytes"
 	"context"
 	"crypto/rand"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"math/big"
 	"runtime"
@@ -36,6 +38,7 @@ var (
 	errNonZeroCreatedAt                            = xerrors.Wrap(errors.New("ydb: non zero Message.CreatedAt and set auto fill created at option")) //nolint:lll
 	errNoAllowedCodecs                             = xerrors.Wrap(errors.New("ydb: no allowed codecs for write to topic"))
 	errLargeMessage                                = xerrors.Wrap(errors.New("ydb: message uncompressed size more, then limit"))                                                                                                                                                                                             //nolint:lll
+	errLargeMetadata                               = xerrors.Wrap(errors.New("ydb: message metadata more, then limit"))                                                                                                                                                                                                      //nolint:lll
 	ErrPublicQueueIsFull                           = xerrors.Wrap(errors.New("ydb: queue is full"))                                                                                                                                                                                                                          // Deprecated.
 	ErrPublicMessagesPutToInternalQueueBeforeError = xerrors.Wrap(errors.New("ydb: the messages was put to internal buffer before the error happened. It mean about the messages can be delivered to the server"))                                                                                                           //nolint:lll
 	errDiffetentTransactions                       = xerrors.Wrap(errors.New("ydb: internal writer has messages from different trasactions. It is internal logic error, write issue please: https://github.com/ydb-platform/ydb-go-sdk/issues/new?assignees=&labels=bug&projects=&template=01_BUG_REPORT.md&title=bug%3A+")) //nolint:lll
@@ -52,6 +55,8 @@ type WriterReconnectorConfig struct {
 	WritersCommonConfig
 
 	MaxMessageSize               int
+	MaxMetadataItems             int
+	MaxMetadataSize              int
 	MaxQueueLen                  int
 	Common                       config.Common
 	AdditionalEncoders           map[rawtopiccommon.Codec]PublicCreateEncoderFunc
@@ -63,6 +68,7 @@ type WriterReconnectorConfig struct {
 	RetrySettings                topic.RetrySettings
 
 	connectTimeout time.Duration
+	spill          spillConfig
 }
 
 func (cfg *WriterReconnectorConfig) validate() error {
@@ -146,6 +152,9 @@ type WriterReconnector struct {
 	sessionID                      string
 	firstConnectionHandled         atomic.Bool
 	initDone                       bool
+	spill                          *spillStorage
+	spillSeqNoKnown                bool
+	spillWriteMutex                xsync.Mutex
 }
 
 func NewWriterReconnector(
@@ -156,6 +165,11 @@ func NewWriterReconnector(
 	}
 
 	res := newWriterReconnectorStopped(cfg)
+	if cfg.spill.Enabled() {
+		if err := res.openSpill(); err != nil {
+			return nil, err
+		}
+	}
 	res.start()
 
 	return res, nil
@@ -223,6 +237,12 @@ func (w *WriterReconnector) fillFields(messages []messageWithDataContent) error
 func (w *WriterReconnector) start() {
 	name := fmt.Sprintf("writer %q", w.cfg.topic)
 	w.background.Start(name+", connectionLoop", w.connectionLoop)
+	if w.spill != nil {
+		w.background.Start(name+", spillLoop", w.spillLoop)
+		if w.cfg.spill.SyncInterval > 0 && !w.cfg.spill.NoSync {
+			w.background.Start(name+", spillSyncLoop", w.spillSyncLoop)
+		}
+	}
 }
 
 func (w *WriterReconnector) Write(ctx context.Context, messages []PublicMessage) (resErr error) {
@@ -236,6 +256,37 @@ func (w *WriterReconnector) Write(ctx context.Context, messages []PublicMessage)
 		return nil
 	}
 
+	metadata := &messagesMetadata{
+		messages: messages,
+		maxItems: w.cfg.MaxMetadataItems,
+		maxSize:  w.cfg.MaxMetadataSize,
+	}
+	onWriteDone := trace.TopicOnWriterWriteMessages(
+		w.cfg.Tracer,
+		&ctx,
+		w.writerInstanceID,
+		w.cfg.topic,
+		w.cfg.producerID,
+		len(messages),
+		metadata,
+	)
+	messages = metadata.messages
+
+	ackTraced := false
+	defer func() {
+		if !ackTraced {
+			onWriteDone(resErr)
+		}
+	}()
+
+	if err := w.checkMetadata(messages); err != nil {
+		return err
+	}
+
+	if w.spill != nil {
+		return w.writeToSpill(ctx, messages)
+	}
+
 	semaphoreWeight := int64(len(messages))
 	if err := w.semaphore.Acquire(ctx, semaphoreWeight); err != nil {
 		return xerrors.WithStackTrace(
@@ -259,10 +310,15 @@ func (w *WriterReconnector) Write(ctx context.Context, messages []PublicMessage)
 		return err
 	}
 
+	if w.cfg.Tracer.OnWriterWriteMessages != nil {
+		// write is done after ack of the last message
+		messagesSlice[len(messagesSlice)-1].onAck = onWriteDone
+	}
 	waiter, err := w.addMessageToInternalQueueWithLock(messagesSlice, &semaphoreWeight)
 	if err != nil {
 		return err
 	}
+	ackTraced = messagesSlice[len(messagesSlice)-1].onAck != nil
 	defer func() {
 		if resErr != nil {
 			resErr = xerrors.Join(resErr, ErrPublicMessagesPutToInternalQueueBeforeError)
@@ -305,9 +361,200 @@ func (w *WriterReconnector) addMessageToInternalQueueWithLock(
 	return waiter, err
 }
 
+func (w *WriterReconnector) openSpill() error {
+	spill, err := openSpillStorage(w.cfg.spill)
+	if err != nil {
+		return err
+	}
+	w.spill = spill
+	w.queue.OnAckedSeqNo = w.onAckedSeqNo
+
+	// seqno of messages, written to the spill before restart, continue from last spilled message
+	// without wait connection to server
+	if lastSeqNo := spill.LastSeqNo(); lastSeqNo >= 0 {
+		w.lastSeqNo = lastSeqNo
+		w.spillSeqNoKnown = true
+	}
+
+	return nil
+}
+
+// writeToSpill saves messages to local spill, they will be sent to server by spillLoop
+func (w *WriterReconnector) writeToSpill(ctx context.Context, messages []PublicMessage) error {
+	messagesSlice := make([]messageWithDataContent, len(messages))
+	records := make([]spillRecord, len(messages))
+	for i := range messages {
+		if messages[i].tx != nil {
+			return xerrors.WithStackTrace(errSpillTransaction)
+		}
+
+		var data []byte
+		if messages[i].Data != nil {
+			var err error
+			data, err = io.ReadAll(messages[i].Data)
+			if err != nil {
+				return xerrors.WithStackTrace(err)
+			}
+		}
+		if size := len(data) + rawtopiccommon.MetadataSize(messages[i].Metadata); size > w.cfg.MaxMessageSize {
+			return xerrors.WithStackTrace(fmt.Errorf("message size bytes %v: %w", size, errLargeMessage))
+		}
+		messagesSlice[i] = messageWithDataContent{PublicMessage: messages[i]}
+		records[i].Data = data
+	}
+
+	if err := w.waitSpillSeqNo(ctx); err != nil {
+		return err
+	}
+
+	// spillWriteMutex keeps order of seqno in the spill, w.m isn't held while append to disk
+	w.spillWriteMutex.Lock()
+	defer w.spillWriteMutex.Unlock()
+
+	var (
+		prevLastSeqNo int64
+		err           error
+	)
+	w.m.WithLock(func() {
+		prevLastSeqNo = w.lastSeqNo
+		if err = w.fillFields(messagesSlice); err != nil {
+			w.lastSeqNo = prevLastSeqNo
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range messagesSlice {
+		records[i].SeqNo = messagesSlice[i].SeqNo
+		records[i].CreatedAt = messagesSlice[i].CreatedAt
+		records[i].Metadata = messagesSlice[i].Metadata
+	}
+	lastSeqNo := records[len(records)-1].SeqNo
+
+	if err = w.spill.Append(records); err != nil {
+		w.m.WithLock(func() {
+			// seqno of the messages are free if no messages written after them
+			if w.lastSeqNo == lastSeqNo {
+				w.lastSeqNo = prevLastSeqNo
+			}
+		})
+
+		return err
+	}
+
+	if !w.cfg.WaitServerAck {
+		return nil
+	}
+
+	return w.spill.WaitAcked(ctx, lastSeqNo)
+}
+
+// waitSpillSeqNo waits first connection to server if seqno of new messages is unknown yet.
+// Seqno of messages must be greater, then last seqno of the producer on server, else server skips
+// the messages as duplicates. The seqno continues from the spill without connection to server,
+// but it is known after connect only if the spill is empty (first start or the spill dir was cleaned).
+func (w *WriterReconnector) waitSpillSeqNo(ctx context.Context) error {
+	if !w.cfg.AutoSetSeqNo {
+		return nil
+	}
+
+	var seqNoKnown bool
+	w.m.WithLock(func() {
+		seqNoKnown = w.spillSeqNoKnown || w.firstConnectionHandled.Load()
+	})
+	if seqNoKnown {
+		return nil
+	}
+
+	return w.waitFirstInitResponse(ctx)
+}
+
+// spillLoop moves messages from the spill to the queue for send to server
+func (w *WriterReconnector) spillLoop(ctx context.Context) {
+	if err := w.waitFirstInitResponse(ctx); err != nil {
+		return
+	}
+
+	for {
+		waiter := w.spill.NewRecordsWaiter()
+		records, err := w.spill.ReadNext(w.cfg.MaxQueueLen)
+		if err != nil {
+			_ = w.close(ctx, fmt.Errorf("ydb: failed to read topic writer spill: %w", err))
+
+			return
+		}
+
+		if len(records) == 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-waiter.Done():
+				continue
+			}
+		}
+
+		if err = w.addSpillRecordsToQueue(ctx, records); err != nil {
+			if ctx.Err() == nil {
+				_ = w.close(ctx, err)
+			}
+
+			return
+		}
+	}
+}
+
+func (w *WriterReconnector) addSpillRecordsToQueue(ctx context.Context, records []spillRecord) error {
+	messages := make([]PublicMessage, len(records))
+	for i := range records {
+		messages[i] = PublicMessage{
+			SeqNo:     records[i].SeqNo,
+			CreatedAt: records[i].CreatedAt,
+			Data:      bytes.NewReader(records[i].Data),
+			Metadata:  records[i].Metadata,
+		}
+	}
+
+	semaphoreWeight := int64(len(messages))
+	if err := w.semaphore.Acquire(ctx, semaphoreWeight); err != nil {
+		return err
+	}
+
+	messagesSlice, err := w.createMessagesWithContent(messages)
+	if err == nil {
+		err = w.queue.AddMessages(messagesSlice)
+	}
+	if err != nil {
+		w.semaphore.Release(semaphoreWeight)
+
+		return err
+	}
+
+	return nil
+}
+
+func (w *WriterReconnector) spillSyncLoop(ctx context.Context) {
+	ticker := w.cfg.clock.NewTicker(w.cfg.spill.SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.Chan():
+			if err := w.spill.Sync(); err != nil {
+				_ = w.close(ctx, fmt.Errorf("ydb: failed to sync topic writer spill: %w", err))
+
+				return
+			}
+		}
+	}
+}
+
 func (w *WriterReconnector) checkMessages(messages []messageWithDataContent) error {
 	for i := range messages {
-		size := messages[i].BufUncompressedSize
+		// metadata is sent within the message and limited by size of the message too
+		size := messages[i].BufUncompressedSize + rawtopiccommon.MetadataSize(messages[i].Metadata)
 		if size > w.cfg.MaxMessageSize {
 			return xerrors.WithStackTrace(fmt.Errorf("message size bytes %v: %w", size, errLargeMessage))
 		}
@@ -316,6 +563,20 @@ func (w *WriterReconnector) checkMessages(messages []messageWithDataContent) err
 	return nil
 }
 
+func (w *WriterReconnector) checkMetadata(messages []PublicMessage) error {
+	for i := range messages {
+		if count := len(messages[i].Metadata); w.cfg.MaxMetadataItems > 0 && count > w.cfg.MaxMetadataItems {
+			return xerrors.WithStackTrace(fmt.Errorf("metadata items count %v: %w", count, errLargeMetadata))
+		}
+		size := rawtopiccommon.MetadataSize(messages[i].Metadata)
+		if w.cfg.MaxMetadataSize > 0 && size > w.cfg.MaxMetadataSize {
+			return xerrors.WithStackTrace(fmt.Errorf("metadata size bytes %v: %w", size, errLargeMetadata))
+		}
+	}
+
+	return nil
+}
+
 func (w *WriterReconnector) createMessagesWithContent(messages []PublicMessage) ([]messageWithDataContent, error) {
 	res := make([]messageWithDataContent, 0, len(messages))
 	for i := range messages {
@@ -354,9 +615,20 @@ func (w *WriterReconnector) createMessagesWithContent(messages []PublicMessage)
 }
 
 func (w *WriterReconnector) Flush(ctx context.Context) error {
+	if w.spill != nil {
+		w.queue.requestFlush()
+
+		return w.spill.WaitAcked(ctx, w.spill.LastSeqNo())
+	}
+
 	return w.queue.WaitLastWritten(ctx)
 }
 
+// QueueLen returns count of written messages, which are not acked by server yet
+func (w *WriterReconnector) QueueLen() int {
+	return w.queue.Len()
+}
+
 func (w *WriterReconnector) Close(ctx context.Context) error {
 	reason := xerrors.WithStackTrace(errStopWriterReconnector)
 	w.queue.StopAddNewMessages(reason)
@@ -388,6 +660,13 @@ func (w *WriterReconnector) close(ctx context.Context, reason error) (resErr err
 		resErr = closeErr
 	}
 
+	if w.spill != nil {
+		// not acked messages stay in the spill and will be sent by next writer with the spill dir
+		if spillErr := w.spill.Close(); resErr == nil && spillErr != nil {
+			resErr = spillErr
+		}
+	}
+
 	return resErr
 }
 
@@ -550,6 +829,10 @@ func (w *WriterReconnector) onAckReceived(count int) {
 	w.semaphore.Release(int64(count))
 }
 
+func (w *WriterReconnector) onAckedSeqNo(seqNo int64) {
+	w.spill.Ack(seqNo)
+}
+
 func (w *WriterReconnector) onWriterChange(writerStream *SingleStreamWriter) {
 	isFirstInit := false
 	w.m.WithLock(func() {
@@ -567,7 +850,13 @@ func (w *WriterReconnector) onWriterChange(writerStream *SingleStreamWriter) {
 		isFirstInit = true
 
 		if writerStream.LastSeqNumRequested {
-			w.lastSeqNo = writerStream.ReceivedLastSeqNum
+			if w.spill != nil {
+				// messages from the spill, written by server before restart, are duplicates
+				w.spill.Ack(writerStream.ReceivedLastSeqNum)
+				w.lastSeqNo = max(w.lastSeqNo, writerStream.ReceivedLastSeqNum)
+			} else {
+				w.lastSeqNo = writerStream.ReceivedLastSeqNum
+			}
 		}
 	})
 
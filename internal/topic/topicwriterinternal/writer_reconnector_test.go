@@ -85,6 +85,83 @@ func TestWriterImpl_CheckMessages(t *testing.T) {
 		err = w.Write(ctx, []PublicMessage{{Data: bytes.NewReader(make([]byte, maxSize+1))}})
 		require.Error(t, err)
 	})
+	t.Run("MetadataLimits", func(t *testing.T) {
+		ctx := xtest.Context(t)
+		w := newWriterReconnectorStopped(NewWriterReconnectorConfig())
+		w.firstConnectionHandled.Store(true)
+		w.cfg.MaxMetadataItems = 2
+		w.cfg.MaxMetadataSize = 10
+
+		err := w.Write(ctx, []PublicMessage{{
+			Data:     bytes.NewReader(nil),
+			Metadata: map[string][]byte{"a": []byte("1234"), "b": []byte("1234")},
+		}})
+		require.NoError(t, err)
+
+		err = w.Write(ctx, []PublicMessage{{
+			Data:     bytes.NewReader(nil),
+			Metadata: map[string][]byte{"a": nil, "b": nil, "c": nil},
+		}})
+		require.ErrorIs(t, err, errLargeMetadata)
+
+		err = w.Write(ctx, []PublicMessage{{
+			Data:     bytes.NewReader(nil),
+			Metadata: map[string][]byte{"a": []byte("1234"), "b": []byte("12345")},
+		}})
+		require.ErrorIs(t, err, errLargeMetadata)
+	})
+	t.Run("MetadataInMessageSize", func(t *testing.T) {
+		ctx := xtest.Context(t)
+		w := newWriterReconnectorStopped(NewWriterReconnectorConfig())
+		w.firstConnectionHandled.Store(true)
+		w.cfg.MaxMessageSize = 5
+
+		err := w.Write(ctx, []PublicMessage{{
+			Data:     bytes.NewReader(make([]byte, 3)),
+			Metadata: map[string][]byte{"a": []byte("1")},
+		}})
+		require.NoError(t, err)
+
+		err = w.Write(ctx, []PublicMessage{{
+			Data:     bytes.NewReader(make([]byte, 3)),
+			Metadata: map[string][]byte{"a": []byte("12")},
+		}})
+		require.ErrorIs(t, err, errLargeMessage)
+	})
+	t.Run("MetadataFromTraceSkippedOnLimits", func(t *testing.T) {
+		ctx := xtest.Context(t)
+		w := newWriterReconnectorStopped(NewWriterReconnectorConfig())
+		w.firstConnectionHandled.Store(true)
+		w.cfg.MaxMetadataItems = 2
+		w.cfg.MaxMetadataSize = 10
+		w.cfg.Tracer = &trace.Topic{
+			OnWriterWriteMessages: func(info trace.TopicWriterWriteMessagesStartInfo) func(
+				trace.TopicWriterWriteMessagesDoneInfo,
+			) {
+				info.Metadata.SetMetadata("t", []byte("1"))
+
+				return nil
+			},
+		}
+
+		err := w.Write(ctx, []PublicMessage{
+			{Data: bytes.NewReader(nil), Metadata: map[string][]byte{"a": []byte("1234"), "b": []byte("1234")}},
+			{Data: bytes.NewReader(nil), Metadata: map[string][]byte{"a": []byte("12345678")}},
+			{Data: bytes.NewReader(nil), Metadata: map[string][]byte{"a": []byte("1")}},
+		})
+		require.NoError(t, err)
+
+		var metadata []map[string][]byte
+		w.queue.m.WithLock(func() {
+			for i := 1; i <= len(w.queue.messagesByOrder); i++ {
+				metadata = append(metadata, w.queue.messagesByOrder[i].Metadata)
+			}
+		})
+		require.Len(t, metadata, 3)
+		require.NotContains(t, metadata[0], "t")
+		require.NotContains(t, metadata[1], "t")
+		require.Equal(t, []byte("1"), metadata[2]["t"])
+	})
 }
 
 func TestWriterImpl_Write(t *testing.T) {
@@ -182,6 +259,68 @@ func TestWriterImpl_Write(t *testing.T) {
 	})
 }
 
+func TestWriterImpl_WriteTrace(t *testing.T) {
+	writeDone := make(chan error, 1)
+	tracer := &trace.Topic{
+		OnWriterWriteMessages: func(info trace.TopicWriterWriteMessagesStartInfo) func(
+			trace.TopicWriterWriteMessagesDoneInfo,
+		) {
+			require.Equal(t, 2, info.MessagesCount)
+			info.Metadata.SetMetadata("traceparent", []byte("parent"))
+			info.Metadata.SetMetadata("key", []byte("new"))
+
+			return func(info trace.TopicWriterWriteMessagesDoneInfo) {
+				writeDone <- info.Error
+			}
+		},
+	}
+	e := newTestEnv(t, &testEnvOptions{
+		writerOptions: []PublicWriterOption{WithTrace(tracer), WithCodec(rawtopiccommon.CodecRaw)},
+	})
+
+	sent := make(chan *rawtopicwriter.WriteRequest, 1)
+	e.stream.EXPECT().Send(gomock.Any()).DoAndReturn(func(message rawtopicwriter.ClientMessage) error {
+		sent <- message.(*rawtopicwriter.WriteRequest)
+
+		return nil
+	})
+
+	messages := []PublicMessage{
+		{SeqNo: 1, Metadata: map[string][]byte{"key": []byte("old")}},
+		{SeqNo: 2},
+	}
+	require.NoError(t, e.writer.Write(e.ctx, messages))
+
+	req := <-sent
+	for i := range req.Messages {
+		require.Contains(t, req.Messages[i].MetadataItems, rawtopiccommon.MetadataItem{
+			Key:   "traceparent",
+			Value: []byte("parent"),
+		})
+	}
+	require.Contains(t, req.Messages[0].MetadataItems, rawtopiccommon.MetadataItem{Key: "key", Value: []byte("old")})
+
+	// messages of caller not modified
+	require.Equal(t, map[string][]byte{"key": []byte("old")}, messages[0].Metadata)
+	require.Nil(t, messages[1].Metadata)
+
+	select {
+	case <-writeDone:
+		t.Fatal("write traced until ack")
+	default:
+	}
+
+	e.sendFromServer(&rawtopicwriter.WriteResult{
+		Acks: []rawtopicwriter.WriteAck{
+			{SeqNo: 1, MessageWriteStatus: rawtopicwriter.MessageWriteStatus{Type: rawtopicwriter.WriteStatusTypeWritten}},
+			{SeqNo: 2, MessageWriteStatus: rawtopicwriter.MessageWriteStatus{Type: rawtopicwriter.WriteStatusTypeWritten}},
+		},
+		PartitionID: e.partitionID,
+	})
+
+	require.NoError(t, <-writeDone)
+}
+
 func TestWriterImpl_WriteCodecs(t *testing.T) {
 	t.Run("ForceRaw", func(t *testing.T) {
 		var err error
@@ -1193,6 +1332,9 @@ func newTestEnv(t testing.TB, options *testEnvOptions) *testEnv {
 	writerOptions = append(writerOptions, options.writerOptions...)
 
 	res.writer = newWriterReconnectorStopped(NewWriterReconnectorConfig(writerOptions...))
+	if res.writer.cfg.spill.Enabled() {
+		require.NoError(t, res.writer.openSpill())
+	}
 
 	res.stream.EXPECT().Recv().DoAndReturn(res.receiveMessageHandler).AnyTimes()
 
@@ -202,6 +202,15 @@ func (w *SingleStreamWriter) receiveMessagesLoop(ctx context.Context) {
 
 		switch m := mess.(type) {
 		case *rawtopicwriter.WriteResult:
+			logCtx := w.cfg.LogContext
+			trace.TopicOnWriterReceiveResult(
+				w.cfg.Tracer,
+				&logCtx,
+				w.cfg.reconnectorInstanceID,
+				w.SessionID,
+				m.PartitionID,
+				m,
+			)
 			if err = w.cfg.queue.AcksReceived(m.Acks); err != nil && !errors.Is(err, errCloseClosedMessageQueue) {
 				reason := xerrors.WithStackTrace(err)
 				closeCtx, closeCtxCancel := xcontext.WithCancel(ctx)
@@ -232,7 +241,7 @@ func (w *SingleStreamWriter) receiveMessagesLoop(ctx context.Context) {
 
 func (w *SingleStreamWriter) sendMessagesFromQueueToStreamLoop(ctx context.Context) {
 	for {
-		messages, err := w.cfg.queue.GetMessagesForSend(ctx)
+		messages, err := w.getMessagesBatchForSend(ctx)
 		if err != nil {
 			_ = w.close(ctx, err)
 
@@ -248,6 +257,7 @@ func (w *SingleStreamWriter) sendMessagesFromQueueToStreamLoop(ctx context.Conte
 
 		err = sendMessagesToStream(w.cfg.stream, w.cfg.maxBytesPerMessage, targetCodec, messages)
 
+		uncompressedBytes, encodedBytes := messagesBytesSize(messages, targetCodec)
 		logCtx := w.cfg.LogContext
 		onSentComplete := trace.TopicOnWriterSendMessages(
 			w.cfg.Tracer,
@@ -257,6 +267,8 @@ func (w *SingleStreamWriter) sendMessagesFromQueueToStreamLoop(ctx context.Conte
 			targetCodec.ToInt32(),
 			messages[0].SeqNo,
 			len(messages),
+			uncompressedBytes,
+			encodedBytes,
 		)
 		onSentComplete(err)
 
@@ -269,6 +281,24 @@ func (w *SingleStreamWriter) sendMessagesFromQueueToStreamLoop(ctx context.Conte
 	}
 }
 
+// getMessagesBatchForSend waits messages for send and collects them to batch up to linger duration
+func (w *SingleStreamWriter) getMessagesBatchForSend(ctx context.Context) ([]messageWithDataContent, error) {
+	limits := sendBatchLimits{
+		maxMessages: w.cfg.maxBatchMessages,
+		maxBytes:    w.cfg.maxBatchBytes,
+	}
+
+	messages, err := w.cfg.queue.GetMessagesBatchForSend(ctx, limits)
+	if err != nil || w.cfg.linger <= 0 || limits.isFull(messages) {
+		return messages, err
+	}
+
+	lingerTimer := w.cfg.clock.NewTimer(w.cfg.linger)
+	defer lingerTimer.Stop()
+
+	return w.cfg.queue.FillBatchForSend(ctx, messages, limits, lingerTimer.Chan()), nil
+}
+
 func (w *SingleStreamWriter) updateTokenLoop(ctx context.Context) {
 	if ctx.Err() != nil {
 		return
@@ -343,6 +373,18 @@ func sendMessagesToStream(
 	return nil
 }
 
+// messagesBytesSize returns size of messages content before and after compression
+func messagesBytesSize(messages []messageWithDataContent, codec rawtopiccommon.Codec) (uncompressed, encoded int) {
+	for i := range messages {
+		uncompressed += messages[i].BufUncompressedSize
+		if data, err := messages[i].GetEncodedBytes(codec); err == nil {
+			encoded += len(data)
+		}
+	}
+
+	return uncompressed, encoded
+}
+
 func cutRequestBytes(req *rawtopicwriter.WriteRequest, maxBytes int) (head, rest *rawtopicwriter.WriteRequest) {
 	requestSize := req.Size()
 	requestMessagesCount := len(req.Messages)
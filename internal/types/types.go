@@ -73,7 +73,7 @@ func TypeFromYDB(x *Ydb.Type) Type {
 		return NewNull()
 
 	case *Ydb.Type_PgType:
-		return &PgType{
+		return PgType{
 			OID: x.GetPgType().GetOid(),
 		}
 
@@ -625,6 +625,10 @@ func TestPgType(t *testing.T) {
 		require.NotNil(t, ydbType)
 		require.Equal(t, uint32(123), ydbType.GetPgType().GetOid())
 	})
+	t.Run("FromYDB", func(t *testing.T) {
+		pg := PgType{OID: 123}
+		require.True(t, Equal(pg, TypeFromYDB(pg.ToYDB())))
+	})
 }
 
 func TestPrimitive(t *testing.T) {
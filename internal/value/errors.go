@@ -8,3 +8,11 @@ var (
 	errNilDestination               = errors.New("destination is nil")
 	ErrIssue1501BadUUID             = errors.New("ydb: uuid storage format was broken in go SDK. Now it fixed. And you should select variant for work: typed uuid (good) or use old format with explicit wrapper for read old data") //nolint:lll
 )
+
+var (
+	errUnsupportedType     = errors.New("unsupported type")
+	errUnknownType         = errors.New("cannot infer type")
+	errRecursiveType       = errors.New("recursive type")
+	errUnknownTypeOverride = errors.New("unknown type override")
+	errDecimalNotFit       = errors.New("decimal value doesn't fit declared precision and scale")
+)
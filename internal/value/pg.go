@@ -0,0 +1,235 @@
+package value
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/pg"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+	"github.com/ydb-platform/ydb-go-sdk/v3/pkg/xstring"
+)
+
+//nolint:funlen,gocyclo
+func (v pgValue) castTo(dst any) error {
+	switch dstValue := dst.(type) {
+	case *driver.Value:
+		*dstValue = v
+
+		return nil
+	case *string:
+		*dstValue = v.val
+
+		return nil
+	case *[]byte:
+		if v.t.OID == pg.OIDBytea {
+			bytes, err := pg.ParseBytea(v.val)
+			if err != nil {
+				return v.castError(dst, err)
+			}
+			*dstValue = bytes
+
+			return nil
+		}
+		*dstValue = xstring.ToBytes(v.val)
+
+		return nil
+	case *bool:
+		if v.t.OID != pg.OIDBool {
+			return v.castError(dst, nil)
+		}
+		b, err := pg.ParseBool(v.val)
+		if err != nil {
+			return v.castError(dst, err)
+		}
+		*dstValue = b
+
+		return nil
+	case *int16:
+		i, err := v.parseInt(dst, 16)
+		if err != nil {
+			return err
+		}
+		*dstValue = int16(i)
+
+		return nil
+	case *int32:
+		i, err := v.parseInt(dst, 32)
+		if err != nil {
+			return err
+		}
+		*dstValue = int32(i)
+
+		return nil
+	case *int64:
+		i, err := v.parseInt(dst, 64)
+		if err != nil {
+			return err
+		}
+		*dstValue = i
+
+		return nil
+	case *int:
+		i, err := v.parseInt(dst, strconv.IntSize)
+		if err != nil {
+			return err
+		}
+		*dstValue = int(i)
+
+		return nil
+	case *float32:
+		f, err := v.parseFloat(dst, 32)
+		if err != nil {
+			return err
+		}
+		*dstValue = float32(f)
+
+		return nil
+	case *float64:
+		f, err := v.parseFloat(dst, 64)
+		if err != nil {
+			return err
+		}
+		*dstValue = f
+
+		return nil
+	case *time.Time:
+		var (
+			t   time.Time
+			err error
+		)
+		switch v.t.OID {
+		case pg.OIDDate:
+			t, err = pg.ParseDate(v.val)
+		case pg.OIDTimestamp:
+			t, err = pg.ParseTimestamp(v.val)
+		case pg.OIDTimestamptz:
+			t, err = pg.ParseTimestamptz(v.val)
+		default:
+			return v.castError(dst, nil)
+		}
+		if err != nil {
+			return v.castError(dst, err)
+		}
+		*dstValue = t
+
+		return nil
+	case *uuid.UUID:
+		if v.t.OID != pg.OIDUUID {
+			return v.castError(dst, nil)
+		}
+		id, err := uuid.Parse(v.val)
+		if err != nil {
+			return v.castError(dst, err)
+		}
+		*dstValue = id
+
+		return nil
+	case *json.RawMessage:
+		if v.t.OID != pg.OIDJSON && v.t.OID != pg.OIDJSONB {
+			return v.castError(dst, nil)
+		}
+		*dstValue = json.RawMessage(v.val)
+
+		return nil
+	case json.Unmarshaler:
+		if v.t.OID != pg.OIDJSON && v.t.OID != pg.OIDJSONB {
+			return v.castError(dst, nil)
+		}
+		if err := dstValue.UnmarshalJSON(xstring.ToBytes(v.val)); err != nil {
+			return v.castError(dst, err)
+		}
+
+		return nil
+	}
+
+	if elemOID, ok := pg.ElemOID(v.t.OID); ok {
+		return v.castArrayTo(dst, elemOID)
+	}
+
+	return v.castError(dst, nil)
+}
+
+func (v pgValue) castArrayTo(dst any, elemOID uint32) error {
+	ptr := reflect.ValueOf(dst)
+	if ptr.Kind() != reflect.Pointer || ptr.Elem().Kind() != reflect.Slice {
+		return v.castError(dst, nil)
+	}
+
+	items, err := pg.ParseArray(v.val)
+	if err != nil {
+		return v.castError(dst, err)
+	}
+
+	sliceType := ptr.Elem().Type()
+	slice := reflect.MakeSlice(sliceType, len(items), len(items))
+	for i, item := range items {
+		elem := slice.Index(i)
+		if item == nil {
+			if elem.Kind() != reflect.Pointer {
+				return v.castError(dst, fmt.Errorf("NULL item #%d", i))
+			}
+
+			continue
+		}
+		if elem.Kind() == reflect.Pointer {
+			elem.Set(reflect.New(elem.Type().Elem()))
+			elem = elem.Elem()
+		}
+		if err := PgValue(elemOID, *item).castTo(elem.Addr().Interface()); err != nil {
+			return v.castError(dst, fmt.Errorf("item #%d: %w", i, err))
+		}
+	}
+	ptr.Elem().Set(slice)
+
+	return nil
+}
+
+func (v pgValue) parseInt(dst any, bitSize int) (int64, error) {
+	switch v.t.OID {
+	case pg.OIDInt2, pg.OIDInt4, pg.OIDInt8, pg.OIDNumeric:
+	default:
+		return 0, v.castError(dst, nil)
+	}
+
+	i, err := strconv.ParseInt(v.val, 10, bitSize)
+	if err != nil {
+		return 0, v.castError(dst, err)
+	}
+
+	return i, nil
+}
+
+func (v pgValue) parseFloat(dst any, bitSize int) (float64, error) {
+	switch v.t.OID {
+	case pg.OIDFloat4, pg.OIDFloat8, pg.OIDNumeric, pg.OIDInt2, pg.OIDInt4, pg.OIDInt8:
+	default:
+		return 0, v.castError(dst, nil)
+	}
+
+	f, err := strconv.ParseFloat(v.val, bitSize)
+	if err != nil {
+		return 0, v.castError(dst, err)
+	}
+
+	return f, nil
+}
+
+func (v pgValue) castError(dst any, err error) error {
+	if err != nil {
+		return xerrors.WithStackTrace(fmt.Errorf(
+			"%w '%s(%+v)' to '%T' destination: %w",
+			ErrCannotCast, v.Type().Yql(), v, dst, err,
+		))
+	}
+
+	return xerrors.WithStackTrace(fmt.Errorf(
+		"%w '%s(%+v)' to '%T' destination",
+		ErrCannotCast, v.Type().Yql(), v, dst,
+	))
+}
@@ -0,0 +1,138 @@
+package value
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/pg"
+)
+
+func TestPgValueCastToGoTypes(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		value Value
+		dst   any
+		exp   any
+	}{
+		{
+			name:  "Int2",
+			value: PgValue(pg.OIDInt2, "123"),
+			dst:   ptr[int16](),
+			exp:   int16(123),
+		},
+		{
+			name:  "Int4",
+			value: PgValue(pg.OIDInt4, "123"),
+			dst:   ptr[int](),
+			exp:   123,
+		},
+		{
+			name:  "Int8ToFloat",
+			value: PgValue(pg.OIDInt8, "123"),
+			dst:   ptr[float64](),
+			exp:   float64(123),
+		},
+		{
+			name:  "Numeric",
+			value: PgValue(pg.OIDNumeric, "1.5"),
+			dst:   ptr[float64](),
+			exp:   1.5,
+		},
+		{
+			name:  "Text",
+			value: PgValue(pg.OIDText, "test"),
+			dst:   ptr[string](),
+			exp:   "test",
+		},
+		{
+			name:  "Bytea",
+			value: PgValue(pg.OIDBytea, `\x0102`),
+			dst:   ptr[[]byte](),
+			exp:   []byte{1, 2},
+		},
+		{
+			name:  "Bool",
+			value: PgValue(pg.OIDBool, "t"),
+			dst:   ptr[bool](),
+			exp:   true,
+		},
+		{
+			name:  "Date",
+			value: PgValue(pg.OIDDate, "2024-01-02"),
+			dst:   ptr[time.Time](),
+			exp:   time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "Timestamp",
+			value: PgValue(pg.OIDTimestamp, "2024-01-02 03:04:05.5"),
+			dst:   ptr[time.Time](),
+			exp:   time.Date(2024, 1, 2, 3, 4, 5, 500000000, time.UTC),
+		},
+		{
+			name:  "UUID",
+			value: PgValue(pg.OIDUUID, "6e73b41c-4ede-4d08-9cfb-b7462d9e498b"),
+			dst:   ptr[uuid.UUID](),
+			exp:   uuid.MustParse("6e73b41c-4ede-4d08-9cfb-b7462d9e498b"),
+		},
+		{
+			name:  "JSONB",
+			value: PgValue(pg.OIDJSONB, `{"a":1}`),
+			dst:   ptr[json.RawMessage](),
+			exp:   json.RawMessage(`{"a":1}`),
+		},
+		{
+			name:  "Int8Array",
+			value: PgValue(pg.OIDInt8Array, "{1,2,3}"),
+			dst:   ptr[[]int64](),
+			exp:   []int64{1, 2, 3},
+		},
+		{
+			name:  "TextArrayWithNulls",
+			value: PgValue(pg.OIDTextArray, `{a,NULL}`),
+			dst:   ptr[[]*string](),
+			exp:   []*string{value2ptr("a"), nil},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, CastTo(tt.value, tt.dst))
+			require.Equal(t, tt.exp, unwrapPtr(tt.dst))
+		})
+	}
+}
+
+func TestPgValueCastToErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		value Value
+		dst   any
+	}{
+		{
+			name:  "TextToInt",
+			value: PgValue(pg.OIDText, "123"),
+			dst:   ptr[int64](),
+		},
+		{
+			name:  "Int2Overflow",
+			value: PgValue(pg.OIDInt4, "100000"),
+			dst:   ptr[int16](),
+		},
+		{
+			name:  "ArrayWithNullToValues",
+			value: PgValue(pg.OIDInt4Array, "{1,NULL}"),
+			dst:   ptr[[]int32](),
+		},
+		{
+			name:  "IntToTime",
+			value: PgValue(pg.OIDInt8, "123"),
+			dst:   ptr[time.Time](),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorIs(t, CastTo(tt.value, tt.dst), ErrCannotCast)
+		})
+	}
+}
@@ -0,0 +1,731 @@
+package value
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/decimal"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/types"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+	"github.com/ydb-platform/ydb-go-sdk/v3/pkg/xstring"
+)
+
+const (
+	// reflectNameTag is a struct tag with a name of struct field.
+	// The same tag is used for scanning query results into structs
+	reflectNameTag = "sql"
+
+	// reflectTypeTag is a struct tag with YDB type of struct field which overrides
+	// default mapping from Go type. For example `ydb:"Date"` or `ydb:"Decimal(35,10)"`
+	reflectTypeTag = "ydb"
+)
+
+var (
+	reflectValueType    = reflect.TypeOf((*Value)(nil)).Elem()
+	reflectUUIDType     = reflect.TypeOf(uuid.UUID{})
+	reflectTimeType     = reflect.TypeOf(time.Time{})
+	reflectDurationType = reflect.TypeOf(time.Duration(0))
+	reflectBigIntType   = reflect.TypeOf(big.Int{})
+	reflectDecimalType  = reflect.TypeOf(decimal.Decimal{})
+)
+
+// reflectOverride describes a type override from struct tag
+type reflectOverride struct {
+	t types.Type
+
+	// precision and scale are set only for Decimal override
+	precision uint32
+	scale     uint32
+}
+
+type reflectEncoderKey struct {
+	t        reflect.Type
+	override string
+}
+
+type reflectEncoder struct {
+	// t is a YDB type of encoded values. It is nil if type is known only at runtime
+	// (for example, for fields with interface type)
+	t      types.Type
+	encode func(rv reflect.Value) (Value, error)
+}
+
+// reflectEncoders caches encoders per reflect.Type and type override
+var reflectEncoders sync.Map // map[reflectEncoderKey]*reflectEncoder
+
+// Of converts arbitrary Go value into Value using reflection.
+//
+// Mapping of Go types into YDB types:
+//   - bool, intN, uintN, floatN -> corresponding primitive types (int and uint are Int64 and Uint64)
+//   - string -> Text, []byte -> Bytes
+//   - uuid.UUID -> Uuid
+//   - time.Time -> Timestamp, time.Duration -> Interval
+//   - big.Int (unscaled value) and decimal.Decimal -> Decimal(22,9) or Decimal of `ydb` tag, decimal.Decimal
+//     values are rescaled to the type, values which don't fit the type without loss of digits are errors
+//   - pointer -> Optional (nil pointer is a typed Null)
+//   - slice -> List, array -> Tuple, map -> Dict, map[K]struct{} -> Set
+//   - struct -> Struct with exported fields named by `sql` tag or field name (`sql:"-"` skips field)
+//   - Value -> as is
+//
+// Struct fields can override default mapping with `ydb` tag, for example `ydb:"Date"`,
+// `ydb:"Decimal(35,10)"` or `ydb:"Json"`. Override passes through pointers, slices and arrays.
+//
+// Encoders are cached per reflect.Type, so repeated conversions of the same type are cheap.
+func Of(v any) (Value, error) {
+	if v == nil {
+		return VoidValue(), nil
+	}
+
+	if vv, ok := v.(Value); ok {
+		return vv, nil
+	}
+
+	enc, err := reflectEncoderOf(reflect.TypeOf(v), "", nil)
+	if err != nil {
+		return nil, xerrors.WithStackTrace(err)
+	}
+
+	vv, err := enc.encode(reflect.ValueOf(v))
+	if err != nil {
+		return nil, xerrors.WithStackTrace(err)
+	}
+
+	return vv, nil
+}
+
+func reflectEncoderOf(
+	rt reflect.Type, override string, visiting map[reflectEncoderKey]struct{},
+) (*reflectEncoder, error) {
+	key := reflectEncoderKey{t: rt, override: override}
+
+	if enc, has := reflectEncoders.Load(key); has {
+		return enc.(*reflectEncoder), nil //nolint:forcetypeassert
+	}
+
+	if _, has := visiting[key]; has {
+		return nil, xerrors.WithStackTrace(fmt.Errorf("%w: %s", errRecursiveType, rt))
+	}
+
+	if visiting == nil {
+		visiting = make(map[reflectEncoderKey]struct{})
+	}
+	visiting[key] = struct{}{}
+	defer delete(visiting, key)
+
+	enc, err := newReflectEncoder(rt, override, visiting)
+	if err != nil {
+		return nil, xerrors.WithStackTrace(err)
+	}
+
+	actual, _ := reflectEncoders.LoadOrStore(key, enc)
+
+	return actual.(*reflectEncoder), nil //nolint:forcetypeassert
+}
+
+//nolint:funlen,gocyclo
+func newReflectEncoder(
+	rt reflect.Type, override string, visiting map[reflectEncoderKey]struct{},
+) (*reflectEncoder, error) {
+	if rt.Kind() == reflect.Interface {
+		return &reflectEncoder{
+			encode: func(rv reflect.Value) (Value, error) {
+				if rv.IsNil() {
+					return VoidValue(), nil
+				}
+
+				return Of(rv.Elem().Interface())
+			},
+		}, nil
+	}
+
+	if rt.Implements(reflectValueType) {
+		return &reflectEncoder{
+			encode: func(rv reflect.Value) (Value, error) {
+				return rv.Interface().(Value), nil //nolint:forcetypeassert
+			},
+		}, nil
+	}
+
+	if rt.Kind() == reflect.Pointer {
+		return newReflectOptionalEncoder(rt, override, visiting)
+	}
+
+	o, err := parseReflectOverride(override)
+	if err != nil {
+		return nil, xerrors.WithStackTrace(err)
+	}
+
+	if o.t == types.JSON || o.t == types.JSONDocument {
+		return newReflectJSONEncoder(o.t), nil
+	}
+
+	switch rt {
+	case reflectUUIDType:
+		return newReflectPrimitiveEncoder(types.UUID, func(rv reflect.Value) Value {
+			return Uuid(rv.Interface().(uuid.UUID)) //nolint:forcetypeassert
+		}), nil
+	case reflectTimeType:
+		return newReflectTimeEncoder(o)
+	case reflectDurationType:
+		return newReflectDurationEncoder(o)
+	case reflectBigIntType:
+		precision, scale := o.decimalParams()
+
+		return &reflectEncoder{
+			t: types.NewDecimal(precision, scale),
+			encode: func(rv reflect.Value) (Value, error) {
+				v := rv.Interface().(big.Int) //nolint:forcetypeassert
+
+				return reflectDecimalValue(&v, scale, precision, scale)
+			},
+		}, nil
+	case reflectDecimalType:
+		precision, scale := o.decimalParams()
+
+		return &reflectEncoder{
+			t: types.NewDecimal(precision, scale),
+			encode: func(rv reflect.Value) (Value, error) {
+				v := rv.Interface().(decimal.Decimal) //nolint:forcetypeassert
+
+				return reflectDecimalValue(decimal.FromInt128(v.Bytes, v.Precision, v.Scale), v.Scale, precision, scale)
+			},
+		}, nil
+	}
+
+	if o.t != nil && rt.Kind() != reflect.String && !isReflectBytes(rt) &&
+		rt.Kind() != reflect.Slice && rt.Kind() != reflect.Array {
+		return nil, xerrors.WithStackTrace(fmt.Errorf("%w: cannot override %s with %s", errUnsupportedType, rt, override))
+	}
+
+	switch rt.Kind() {
+	case reflect.Bool:
+		return newReflectPrimitiveEncoder(types.Bool, func(rv reflect.Value) Value {
+			return BoolValue(rv.Bool())
+		}), nil
+	case reflect.Int8:
+		return newReflectPrimitiveEncoder(types.Int8, func(rv reflect.Value) Value {
+			return Int8Value(int8(rv.Int()))
+		}), nil
+	case reflect.Int16:
+		return newReflectPrimitiveEncoder(types.Int16, func(rv reflect.Value) Value {
+			return Int16Value(int16(rv.Int()))
+		}), nil
+	case reflect.Int32:
+		return newReflectPrimitiveEncoder(types.Int32, func(rv reflect.Value) Value {
+			return Int32Value(int32(rv.Int()))
+		}), nil
+	case reflect.Int, reflect.Int64:
+		return newReflectPrimitiveEncoder(types.Int64, func(rv reflect.Value) Value {
+			return Int64Value(rv.Int())
+		}), nil
+	case reflect.Uint8:
+		return newReflectPrimitiveEncoder(types.Uint8, func(rv reflect.Value) Value {
+			return Uint8Value(uint8(rv.Uint()))
+		}), nil
+	case reflect.Uint16:
+		return newReflectPrimitiveEncoder(types.Uint16, func(rv reflect.Value) Value {
+			return Uint16Value(uint16(rv.Uint()))
+		}), nil
+	case reflect.Uint32:
+		return newReflectPrimitiveEncoder(types.Uint32, func(rv reflect.Value) Value {
+			return Uint32Value(uint32(rv.Uint()))
+		}), nil
+	case reflect.Uint, reflect.Uint64:
+		return newReflectPrimitiveEncoder(types.Uint64, func(rv reflect.Value) Value {
+			return Uint64Value(rv.Uint())
+		}), nil
+	case reflect.Float32:
+		return newReflectPrimitiveEncoder(types.Float, func(rv reflect.Value) Value {
+			return FloatValue(float32(rv.Float()))
+		}), nil
+	case reflect.Float64:
+		return newReflectPrimitiveEncoder(types.Double, func(rv reflect.Value) Value {
+			return DoubleValue(rv.Float())
+		}), nil
+	case reflect.String:
+		return newReflectBytesEncoder(o, func(rv reflect.Value) []byte {
+			return xstring.ToBytes(rv.String())
+		}, types.Text)
+	case reflect.Slice:
+		if isReflectBytes(rt) {
+			return newReflectBytesEncoder(o, func(rv reflect.Value) []byte {
+				return rv.Bytes()
+			}, types.Bytes)
+		}
+
+		return newReflectListEncoder(rt, override, visiting)
+	case reflect.Array:
+		if rt.Elem().Kind() == reflect.Uint8 && rt.Len() == 16 {
+			return nil, xerrors.Wrap(ErrIssue1501BadUUID)
+		}
+
+		return newReflectTupleEncoder(rt, override, visiting)
+	case reflect.Map:
+		return newReflectDictEncoder(rt, visiting)
+	case reflect.Struct:
+		return newReflectStructEncoder(rt, visiting)
+	default:
+		return nil, xerrors.WithStackTrace(fmt.Errorf("%w: %s", errUnsupportedType, rt))
+	}
+}
+
+func isReflectBytes(rt reflect.Type) bool {
+	return rt.Kind() == reflect.Slice && rt.Elem().Kind() == reflect.Uint8
+}
+
+func newReflectPrimitiveEncoder(t types.Type, f func(rv reflect.Value) Value) *reflectEncoder {
+	return &reflectEncoder{
+		t: t,
+		encode: func(rv reflect.Value) (Value, error) {
+			return f(rv), nil
+		},
+	}
+}
+
+func newReflectOptionalEncoder(
+	rt reflect.Type, override string, visiting map[reflectEncoderKey]struct{},
+) (*reflectEncoder, error) {
+	inner, err := reflectEncoderOf(rt.Elem(), override, visiting)
+	if err != nil {
+		return nil, xerrors.WithStackTrace(err)
+	}
+
+	var t types.Type
+	if inner.t != nil {
+		t = types.NewOptional(inner.t)
+	}
+
+	return &reflectEncoder{
+		t: t,
+		encode: func(rv reflect.Value) (Value, error) {
+			if rv.IsNil() {
+				if inner.t == nil {
+					return nil, xerrors.WithStackTrace(fmt.Errorf("%w: nil %s", errUnknownType, rt))
+				}
+
+				return NullValue(inner.t), nil
+			}
+
+			v, err := inner.encode(rv.Elem())
+			if err != nil {
+				return nil, xerrors.WithStackTrace(err)
+			}
+
+			return OptionalValue(v), nil
+		},
+	}, nil
+}
+
+func newReflectJSONEncoder(t types.Type) *reflectEncoder {
+	return &reflectEncoder{
+		t: t,
+		encode: func(rv reflect.Value) (Value, error) {
+			bytes, err := json.Marshal(rv.Interface())
+			if err != nil {
+				return nil, xerrors.WithStackTrace(err)
+			}
+
+			if t == types.JSONDocument {
+				return JSONDocumentValue(xstring.FromBytes(bytes)), nil
+			}
+
+			return JSONValue(xstring.FromBytes(bytes)), nil
+		},
+	}
+}
+
+func newReflectBytesEncoder(
+	o reflectOverride, f func(rv reflect.Value) []byte, defaultType types.Type,
+) (*reflectEncoder, error) {
+	t := defaultType
+	if o.t != nil {
+		t = o.t
+	}
+
+	switch t {
+	case types.Text:
+		return newReflectPrimitiveEncoder(t, func(rv reflect.Value) Value {
+			return TextValue(string(f(rv)))
+		}), nil
+	case types.Bytes:
+		return newReflectPrimitiveEncoder(t, func(rv reflect.Value) Value {
+			return BytesValue(append([]byte(nil), f(rv)...))
+		}), nil
+	case types.YSON:
+		return newReflectPrimitiveEncoder(t, func(rv reflect.Value) Value {
+			return YSONValue(append([]byte(nil), f(rv)...))
+		}), nil
+	default:
+		return nil, xerrors.WithStackTrace(fmt.Errorf("%w: cannot override string with %s", errUnsupportedType, t))
+	}
+}
+
+func newReflectTimeEncoder(o reflectOverride) (*reflectEncoder, error) {
+	t := o.t
+	if t == nil {
+		t = types.Timestamp
+	}
+
+	var f func(v time.Time) Value
+	switch t {
+	case types.Date:
+		f = func(v time.Time) Value { return DateValueFromTime(v) }
+	case types.Date32:
+		f = func(v time.Time) Value { return Date32ValueFromTime(v) }
+	case types.Datetime:
+		f = func(v time.Time) Value { return DatetimeValueFromTime(v) }
+	case types.Datetime64:
+		f = func(v time.Time) Value { return Datetime64ValueFromTime(v) }
+	case types.Timestamp:
+		f = func(v time.Time) Value { return TimestampValueFromTime(v) }
+	case types.Timestamp64:
+		f = func(v time.Time) Value { return Timestamp64ValueFromTime(v) }
+	case types.TzDate:
+		f = func(v time.Time) Value { return TzDateValueFromTime(v) }
+	case types.TzDatetime:
+		f = func(v time.Time) Value { return TzDatetimeValueFromTime(v) }
+	case types.TzTimestamp:
+		f = func(v time.Time) Value { return TzTimestampValueFromTime(v) }
+	default:
+		return nil, xerrors.WithStackTrace(fmt.Errorf("%w: cannot override time.Time with %s", errUnsupportedType, t))
+	}
+
+	return newReflectPrimitiveEncoder(t, func(rv reflect.Value) Value {
+		return f(rv.Interface().(time.Time)) //nolint:forcetypeassert
+	}), nil
+}
+
+func newReflectDurationEncoder(o reflectOverride) (*reflectEncoder, error) {
+	switch o.t {
+	case nil, types.Interval:
+		return newReflectPrimitiveEncoder(types.Interval, func(rv reflect.Value) Value {
+			return IntervalValueFromDuration(time.Duration(rv.Int()))
+		}), nil
+	case types.Interval64:
+		return newReflectPrimitiveEncoder(types.Interval64, func(rv reflect.Value) Value {
+			return Interval64ValueFromDuration(time.Duration(rv.Int()))
+		}), nil
+	default:
+		return nil, xerrors.WithStackTrace(fmt.Errorf("%w: cannot override time.Duration with %s", errUnsupportedType, o.t))
+	}
+}
+
+func newReflectListEncoder(
+	rt reflect.Type, override string, visiting map[reflectEncoderKey]struct{},
+) (*reflectEncoder, error) {
+	item, err := reflectEncoderOf(rt.Elem(), override, visiting)
+	if err != nil {
+		return nil, xerrors.WithStackTrace(err)
+	}
+
+	t := types.Type(types.NewEmptyList())
+	if item.t != nil {
+		t = types.NewList(item.t)
+	}
+
+	return &reflectEncoder{
+		t: t,
+		encode: func(rv reflect.Value) (Value, error) {
+			if rv.Len() == 0 {
+				return &listValue{t: t}, nil
+			}
+
+			var err error
+			items := make([]Value, rv.Len())
+			for i := range items {
+				items[i], err = item.encode(rv.Index(i))
+				if err != nil {
+					return nil, xerrors.WithStackTrace(fmt.Errorf("item #%d: %w", i, err))
+				}
+			}
+
+			return ListValue(items...), nil
+		},
+	}, nil
+}
+
+func newReflectTupleEncoder(
+	rt reflect.Type, override string, visiting map[reflectEncoderKey]struct{},
+) (*reflectEncoder, error) {
+	item, err := reflectEncoderOf(rt.Elem(), override, visiting)
+	if err != nil {
+		return nil, xerrors.WithStackTrace(err)
+	}
+
+	var t types.Type
+	if item.t != nil {
+		itemTypes := make([]types.Type, rt.Len())
+		for i := range itemTypes {
+			itemTypes[i] = item.t
+		}
+		t = types.NewTuple(itemTypes...)
+	}
+
+	return &reflectEncoder{
+		t: t,
+		encode: func(rv reflect.Value) (Value, error) {
+			var err error
+			items := make([]Value, rv.Len())
+			for i := range items {
+				items[i], err = item.encode(rv.Index(i))
+				if err != nil {
+					return nil, xerrors.WithStackTrace(fmt.Errorf("item #%d: %w", i, err))
+				}
+			}
+
+			return TupleValue(items...), nil
+		},
+	}, nil
+}
+
+func newReflectDictEncoder(rt reflect.Type, visiting map[reflectEncoderKey]struct{}) (*reflectEncoder, error) {
+	key, err := reflectEncoderOf(rt.Key(), "", visiting)
+	if err != nil {
+		return nil, xerrors.WithStackTrace(err)
+	}
+
+	if rt.Elem().Kind() == reflect.Struct && rt.Elem().NumField() == 0 {
+		t := types.Type(types.EmptySet())
+		if key.t != nil {
+			t = types.NewSet(key.t)
+		}
+
+		return &reflectEncoder{
+			t: t,
+			encode: func(rv reflect.Value) (Value, error) {
+				if rv.Len() == 0 {
+					return &setValue{t: t}, nil
+				}
+
+				items := make([]Value, 0, rv.Len())
+				for iter := rv.MapRange(); iter.Next(); {
+					k, err := key.encode(iter.Key())
+					if err != nil {
+						return nil, xerrors.WithStackTrace(err)
+					}
+					items = append(items, k)
+				}
+
+				return SetValue(items...), nil
+			},
+		}, nil
+	}
+
+	payload, err := reflectEncoderOf(rt.Elem(), "", visiting)
+	if err != nil {
+		return nil, xerrors.WithStackTrace(err)
+	}
+
+	t := types.Type(types.NewEmptyDict())
+	if key.t != nil && payload.t != nil {
+		t = types.NewDict(key.t, payload.t)
+	}
+
+	return &reflectEncoder{
+		t: t,
+		encode: func(rv reflect.Value) (Value, error) {
+			if rv.Len() == 0 {
+				return &dictValue{t: t}, nil
+			}
+
+			fields := make([]DictValueField, 0, rv.Len())
+			for iter := rv.MapRange(); iter.Next(); {
+				k, err := key.encode(iter.Key())
+				if err != nil {
+					return nil, xerrors.WithStackTrace(fmt.Errorf("key %v: %w", iter.Key().Interface(), err))
+				}
+				v, err := payload.encode(iter.Value())
+				if err != nil {
+					return nil, xerrors.WithStackTrace(fmt.Errorf("value of key %v: %w", iter.Key().Interface(), err))
+				}
+				fields = append(fields, DictValueField{K: k, V: v})
+			}
+
+			return DictValue(fields...), nil
+		},
+	}, nil
+}
+
+type reflectStructField struct {
+	index []int
+	name  string
+	enc   *reflectEncoder
+}
+
+func newReflectStructEncoder(rt reflect.Type, visiting map[reflectEncoderKey]struct{}) (*reflectEncoder, error) {
+	fields := make([]reflectStructField, 0, rt.NumField())
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name := f.Name
+		if tag, has := f.Tag.Lookup(reflectNameTag); has {
+			name = tag
+		}
+		if name == "-" {
+			continue
+		}
+
+		enc, err := reflectEncoderOf(f.Type, f.Tag.Get(reflectTypeTag), visiting)
+		if err != nil {
+			return nil, xerrors.WithStackTrace(fmt.Errorf("field %q of %s: %w", f.Name, rt, err))
+		}
+
+		fields = append(fields, reflectStructField{
+			index: f.Index,
+			name:  name,
+			enc:   enc,
+		})
+	}
+
+	var t types.Type
+	structFields := make([]types.StructField, 0, len(fields))
+	for _, f := range fields {
+		if f.enc.t == nil {
+			structFields = nil
+
+			break
+		}
+		structFields = append(structFields, types.StructField{Name: f.name, T: f.enc.t})
+	}
+	if structFields != nil {
+		sort.Slice(structFields, func(i, j int) bool {
+			return structFields[i].Name < structFields[j].Name
+		})
+		t = types.NewStruct(structFields...)
+	}
+
+	return &reflectEncoder{
+		t: t,
+		encode: func(rv reflect.Value) (Value, error) {
+			values := make([]StructValueField, len(fields))
+			for i, f := range fields {
+				v, err := f.enc.encode(rv.FieldByIndex(f.index))
+				if err != nil {
+					return nil, xerrors.WithStackTrace(fmt.Errorf("field %q: %w", f.name, err))
+				}
+				values[i] = StructValueField{Name: f.name, V: v}
+			}
+
+			return StructValue(values...), nil
+		},
+	}, nil
+}
+
+func parseReflectOverride(s string) (o reflectOverride, err error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return o, nil
+	}
+
+	if strings.HasPrefix(s, "Decimal(") && strings.HasSuffix(s, ")") {
+		params := strings.Split(s[len("Decimal("):len(s)-1], ",")
+		if len(params) != 2 { //nolint:gomnd
+			return o, xerrors.WithStackTrace(fmt.Errorf("%w: %q", errUnknownTypeOverride, s))
+		}
+		precision, err := strconv.ParseUint(strings.TrimSpace(params[0]), 10, 32)
+		if err != nil {
+			return o, xerrors.WithStackTrace(fmt.Errorf("%w: %q: %w", errUnknownTypeOverride, s, err))
+		}
+		scale, err := strconv.ParseUint(strings.TrimSpace(params[1]), 10, 32)
+		if err != nil {
+			return o, xerrors.WithStackTrace(fmt.Errorf("%w: %q: %w", errUnknownTypeOverride, s, err))
+		}
+
+		return reflectOverride{
+			t:         types.NewDecimal(uint32(precision), uint32(scale)),
+			precision: uint32(precision),
+			scale:     uint32(scale),
+		}, nil
+	}
+
+	switch s {
+	case "Date":
+		o.t = types.Date
+	case "Date32":
+		o.t = types.Date32
+	case "Datetime":
+		o.t = types.Datetime
+	case "Datetime64":
+		o.t = types.Datetime64
+	case "Timestamp":
+		o.t = types.Timestamp
+	case "Timestamp64":
+		o.t = types.Timestamp64
+	case "Interval":
+		o.t = types.Interval
+	case "Interval64":
+		o.t = types.Interval64
+	case "TzDate":
+		o.t = types.TzDate
+	case "TzDatetime":
+		o.t = types.TzDatetime
+	case "TzTimestamp":
+		o.t = types.TzTimestamp
+	case "Text", "Utf8":
+		o.t = types.Text
+	case "Bytes", "String":
+		o.t = types.Bytes
+	case "Yson", "YSON":
+		o.t = types.YSON
+	case "Json", "JSON":
+		o.t = types.JSON
+	case "JsonDocument", "JSONDocument":
+		o.t = types.JSONDocument
+	default:
+		return o, xerrors.WithStackTrace(fmt.Errorf("%w: %q", errUnknownTypeOverride, s))
+	}
+
+	return o, nil
+}
+
+// reflectDecimalValue makes decimal value of declared precision and scale from unscaled value x with scale
+// fromScale. Values, which don't fit declared type without loss of digits, returns error
+func reflectDecimalValue(x *big.Int, fromScale, precision, scale uint32) (Value, error) {
+	if decimal.IsInf(x) || decimal.IsNaN(x) {
+		return DecimalValueFromBigInt(x, precision, scale), nil
+	}
+
+	v := new(big.Int).Set(x)
+	switch {
+	case scale > fromScale:
+		v.Mul(v, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale-fromScale)), nil)) //nolint:mnd
+	case scale < fromScale:
+		var rem big.Int
+		v.QuoRem(v, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(fromScale-scale)), nil), &rem) //nolint:mnd
+		if rem.Sign() != 0 {
+			return nil, reflectDecimalNotFitError(x, fromScale, precision, scale)
+		}
+	}
+
+	if v.CmpAbs(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(precision)), nil)) >= 0 { //nolint:mnd
+		return nil, reflectDecimalNotFitError(x, fromScale, precision, scale)
+	}
+
+	return DecimalValueFromBigInt(v, precision, scale), nil
+}
+
+func reflectDecimalNotFitError(x *big.Int, fromScale, precision, scale uint32) error {
+	return xerrors.WithStackTrace(fmt.Errorf("%w: %se-%d to Decimal(%d,%d)",
+		errDecimalNotFit, x, fromScale, precision, scale,
+	))
+}
+
+func (o reflectOverride) decimalParams() (precision, scale uint32) {
+	if _, ok := o.t.(*types.Decimal); ok {
+		return o.precision, o.scale
+	}
+
+	return decimalPrecision, decimalScale
+}
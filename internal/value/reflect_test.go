@@ -0,0 +1,235 @@
+package value
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/decimal"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/types"
+)
+
+func TestOf(t *testing.T) {
+	type item struct {
+		Name  string  `sql:"name"`
+		Price float64 `sql:"price"`
+	}
+	type order struct {
+		ID        uuid.UUID  `sql:"id"`
+		CreatedAt time.Time  `sql:"created_at"`
+		Day       time.Time  `sql:"day" ydb:"Date"`
+		Comment   *string    `sql:"comment"`
+		Items     []item     `sql:"items"`
+		Amount    big.Int    `sql:"amount" ydb:"Decimal(35,2)"`
+		Tags      []string   `sql:"tags"`
+		Ignored   int        `sql:"-"`
+		Extra     any        `sql:"extra"`
+		Payload   item       `sql:"payload" ydb:"Json"`
+		Deleted   *time.Time `sql:"deleted" ydb:"Datetime"`
+		internal  int
+	}
+	id := uuid.MustParse("6e73b41c-4ede-4d08-9cfb-b7462d9e498b")
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, tt := range []struct {
+		name string
+		src  any
+		exp  Value
+	}{
+		{
+			name: "nil",
+			src:  nil,
+			exp:  VoidValue(),
+		},
+		{
+			name: "Value",
+			src:  TextValue("test"),
+			exp:  TextValue("test"),
+		},
+		{
+			name: "int",
+			src:  123,
+			exp:  Int64Value(123),
+		},
+		{
+			name: "uint16",
+			src:  uint16(123),
+			exp:  Uint16Value(123),
+		},
+		{
+			name: "string",
+			src:  "test",
+			exp:  TextValue("test"),
+		},
+		{
+			name: "bytes",
+			src:  []byte("test"),
+			exp:  BytesValue([]byte("test")),
+		},
+		{
+			name: "nil pointer",
+			src:  (*int32)(nil),
+			exp:  NullValue(types.Int32),
+		},
+		{
+			name: "pointer",
+			src:  func(v int32) *int32 { return &v }(123),
+			exp:  OptionalValue(Int32Value(123)),
+		},
+		{
+			name: "time.Duration",
+			src:  time.Second,
+			exp:  IntervalValueFromDuration(time.Second),
+		},
+		{
+			name: "empty slice",
+			src:  []int32{},
+			exp:  ZeroValue(types.NewList(types.Int32)),
+		},
+		{
+			name: "slice",
+			src:  []int32{1, 2},
+			exp:  ListValue(Int32Value(1), Int32Value(2)),
+		},
+		{
+			name: "array",
+			src:  [2]bool{true, false},
+			exp:  TupleValue(BoolValue(true), BoolValue(false)),
+		},
+		{
+			name: "map",
+			src:  map[string]int8{"a": 1, "b": 2},
+			exp: DictValue(
+				DictValueField{K: TextValue("a"), V: Int8Value(1)},
+				DictValueField{K: TextValue("b"), V: Int8Value(2)},
+			),
+		},
+		{
+			name: "set",
+			src:  map[uint64]struct{}{1: {}, 2: {}},
+			exp:  SetValue(Uint64Value(1), Uint64Value(2)),
+		},
+		{
+			name: "struct",
+			src: order{
+				ID:        id,
+				CreatedAt: ts,
+				Day:       ts,
+				Items:     []item{{Name: "a", Price: 1.5}},
+				Amount:    *big.NewInt(12345),
+				Tags:      nil,
+				Ignored:   1,
+				Extra:     uint8(1),
+				Payload:   item{Name: "b", Price: 2},
+				internal:  1,
+			},
+			exp: StructValue(
+				StructValueField{Name: "id", V: Uuid(id)},
+				StructValueField{Name: "created_at", V: TimestampValueFromTime(ts)},
+				StructValueField{Name: "day", V: DateValueFromTime(ts)},
+				StructValueField{Name: "comment", V: NullValue(types.Text)},
+				StructValueField{Name: "items", V: ListValue(StructValue(
+					StructValueField{Name: "name", V: TextValue("a")},
+					StructValueField{Name: "price", V: DoubleValue(1.5)},
+				))},
+				StructValueField{Name: "amount", V: DecimalValueFromBigInt(big.NewInt(12345), 35, 2)},
+				StructValueField{Name: "tags", V: ZeroValue(types.NewList(types.Text))},
+				StructValueField{Name: "extra", V: Uint8Value(1)},
+				StructValueField{Name: "payload", V: JSONValue(`{"Name":"b","Price":2}`)},
+				StructValueField{Name: "deleted", V: NullValue(types.Datetime)},
+			),
+		},
+		{
+			name: "decimal rescaled to declared type",
+			src: struct {
+				V decimal.Decimal `sql:"v" ydb:"Decimal(10,4)"`
+			}{V: decimal.Decimal{Bytes: [16]byte{15: 125}, Precision: 5, Scale: 2}},
+			exp: StructValue(StructValueField{Name: "v", V: DecimalValueFromBigInt(big.NewInt(12500), 10, 4)}),
+		},
+		{
+			name: "nil decimal",
+			src: struct {
+				V *decimal.Decimal `sql:"v" ydb:"Decimal(10,4)"`
+			}{},
+			exp: StructValue(StructValueField{Name: "v", V: NullValue(types.NewDecimal(10, 4))}),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := Of(tt.src)
+			require.NoError(t, err)
+			require.Equal(t, tt.exp.Type().Yql(), v.Type().Yql())
+			require.Equal(t, tt.exp.Yql(), v.Yql())
+		})
+	}
+}
+
+func TestOfErrors(t *testing.T) {
+	type recursive struct {
+		Next *recursive
+	}
+	type badOverride struct {
+		V int `ydb:"Date"`
+	}
+	type unknownOverride struct {
+		V time.Time `ydb:"Unknown"`
+	}
+	for _, tt := range []struct {
+		name string
+		src  any
+		err  error
+	}{
+		{
+			name: "recursive",
+			src:  recursive{},
+			err:  errRecursiveType,
+		},
+		{
+			name: "bad override",
+			src:  badOverride{},
+			err:  errUnsupportedType,
+		},
+		{
+			name: "unknown override",
+			src:  unknownOverride{},
+			err:  errUnknownTypeOverride,
+		},
+		{
+			name: "channel",
+			src:  make(chan int),
+			err:  errUnsupportedType,
+		},
+		{
+			name: "bytes array",
+			src:  [16]byte{},
+			err:  ErrIssue1501BadUUID,
+		},
+		{
+			name: "decimal loses digits",
+			src: struct {
+				V decimal.Decimal `ydb:"Decimal(10,1)"`
+			}{V: decimal.Decimal{Bytes: [16]byte{15: 125}, Precision: 5, Scale: 2}},
+			err: errDecimalNotFit,
+		},
+		{
+			name: "decimal overflow",
+			src: struct {
+				V decimal.Decimal `ydb:"Decimal(3,2)"`
+			}{V: decimal.Decimal{Bytes: [16]byte{15: 125}, Precision: 5, Scale: 0}},
+			err: errDecimalNotFit,
+		},
+		{
+			name: "big int overflow",
+			src: struct {
+				V big.Int `ydb:"Decimal(2,0)"`
+			}{V: *big.NewInt(100)},
+			err: errDecimalNotFit,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Of(tt.src)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
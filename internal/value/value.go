@@ -304,7 +304,7 @@ func fromYDB(t *Ydb.Type, v *Ydb.Value) (Value, error) {
 			ttt.Tuple,
 		), nil
 
-	case *types.PgType:
+	case types.PgType:
 		return &pgValue{
 			t: types.PgType{
 				OID: ttt.OID,
@@ -724,6 +724,10 @@ func (v *dictValue) castTo(dst any) error {
 }
 
 func (v *dictValue) Yql() string {
+	if t, ok := v.t.(*types.Dict); ok && len(v.values) == 0 {
+		return "DictCreate(" + t.KeyType().Yql() + "," + t.ValueType().Yql() + ")"
+	}
+
 	buffer := xstring.Buffer()
 	defer buffer.Free()
 	buffer.WriteByte('{')
@@ -1519,6 +1523,10 @@ func (v *listValue) castTo(dst any) error {
 }
 
 func (v *listValue) Yql() string {
+	if t, ok := v.t.(*types.List); ok && len(v.items) == 0 {
+		return "ListCreate(" + t.ItemType().Yql() + ")"
+	}
+
 	buffer := xstring.Buffer()
 	defer buffer.Free()
 	buffer.WriteByte('[')
@@ -1573,20 +1581,6 @@ type pgValue struct {
 	val string
 }
 
-func (v pgValue) castTo(dst any) error {
-	switch dstValue := dst.(type) {
-	case *driver.Value:
-		*dstValue = v
-
-		return nil
-	default:
-		return xerrors.WithStackTrace(fmt.Errorf(
-			"%w '%s(%+v)' to '%T' destination",
-			ErrCannotCast, v.Type().Yql(), v, dstValue,
-		))
-	}
-}
-
 func (v pgValue) Type() types.Type {
 	return v.t
 }
@@ -1600,7 +1594,7 @@ func (v pgValue) toYDB() *Ydb.Value {
 }
 
 func (v pgValue) Yql() string {
-	return fmt.Sprintf(`PgConst("%v", PgType(%v))`, v.val, v.t.OID)
+	return fmt.Sprintf(`PgConst(%q, PgType(%v))`, v.val, v.t.OID)
 }
 
 type setValue struct {
@@ -1650,6 +1644,10 @@ func (v *setValue) castTo(dst any) error {
 }
 
 func (v *setValue) Yql() string {
+	if t, ok := v.t.(*types.Set); ok && len(v.items) == 0 {
+		return "SetCreate(" + t.ItemType().Yql() + ")"
+	}
+
 	buffer := xstring.Buffer()
 	defer buffer.Free()
 	buffer.WriteByte('{')
@@ -3042,7 +3040,7 @@ func ZeroValue(t types.Type) Value {
 		}
 	case *types.Dict:
 		return &dictValue{
-			t: t.ValueType(),
+			t: t,
 		}
 	case *types.EmptyDict:
 		return &dictValue{
@@ -3079,6 +3077,79 @@ func ZeroValue(t types.Type) Value {
 	}
 }
 
+// HasZeroValue returns true if ZeroValue makes value of type t
+func HasZeroValue(t types.Type) bool {
+	switch t := t.(type) {
+	case types.Primitive:
+		switch t {
+		case types.Bool, types.Int8, types.Uint8, types.Int16, types.Uint16, types.Int32, types.Uint32,
+			types.Int64, types.Uint64, types.Float, types.Double, types.Date, types.Datetime, types.Timestamp,
+			types.Interval, types.Text, types.YSON, types.JSON, types.JSONDocument, types.DyNumber,
+			types.TzDate, types.TzDatetime, types.TzTimestamp, types.Bytes, types.UUID:
+			return true
+		default:
+			return false
+		}
+	case types.Optional, *types.Void, *types.List, *types.EmptyList, *types.Set, *types.Dict, *types.EmptyDict,
+		*types.Decimal:
+		return true
+	case *types.Tuple:
+		for _, tt := range t.InnerTypes() {
+			if !HasZeroValue(tt) {
+				return false
+			}
+		}
+
+		return true
+	case *types.Struct:
+		for _, field := range t.Fields() {
+			if !HasZeroValue(field.T) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return false
+	}
+}
+
+// YQLExpressionValue returns value of type t, which is rendered into YQL as expression yql.
+// The value is for render YQL text only: it can't be casted and it isn't valid query parameter.
+func YQLExpressionValue(t types.Type, yql string) Value {
+	return &yqlExpressionValue{
+		t:   t,
+		yql: yql,
+	}
+}
+
+type yqlExpressionValue struct {
+	t   types.Type
+	yql string
+}
+
+func (v *yqlExpressionValue) castTo(dst any) error {
+	return xerrors.WithStackTrace(fmt.Errorf(
+		"%w YQL expression '%s' to '%T' destination",
+		ErrCannotCast, v.yql, dst,
+	))
+}
+
+func (v *yqlExpressionValue) Yql() string {
+	return v.yql
+}
+
+func (v *yqlExpressionValue) Type() types.Type {
+	return v.t
+}
+
+func (v *yqlExpressionValue) toYDB() *Ydb.Value {
+	// expression has no value, server rejects the parameter with type error
+	return &Ydb.Value{
+		Value: &Ydb.Value_NullFlagValue{},
+	}
+}
+
 type bytesValue []byte
 
 func (v bytesValue) castTo(dst any) error {
@@ -550,6 +550,22 @@ func TestValueYql(t *testing.T) {
 			value:   PgValue(pg.OIDUnknown, "123"),
 			literal: `PgConst("123", PgType(705))`,
 		},
+		{
+			value:   PgValue(pg.OIDText, `a"b`),
+			literal: `PgConst("a\"b", PgType(25))`,
+		},
+		{
+			value:   ZeroValue(types.NewList(types.Int32)),
+			literal: `ListCreate(Int32)`,
+		},
+		{
+			value:   ZeroValue(types.NewSet(types.Text)),
+			literal: `SetCreate(Utf8)`,
+		},
+		{
+			value:   ZeroValue(types.NewDict(types.Text, types.Int32)),
+			literal: `DictCreate(Utf8,Int32)`,
+		},
 		{
 			value: FromProtobuf(&Ydb.TypedValue{
 				Type: &Ydb.Type{
@@ -2007,7 +2023,7 @@ func TestPgValueCastTo(t *testing.T) {
 	v := PgValue(pg.OIDInt4, "123")
 
 	t.Run("CastToInvalid", func(t *testing.T) {
-		var result int
+		var result bool
 		err := v.castTo(&result)
 		require.Error(t, err)
 	})
@@ -2678,3 +2694,27 @@ func TestBytesValueCastTo(t *testing.T) {
 		require.Equal(t, []byte("test"), result)
 	})
 }
+
+func TestHasZeroValue(t *testing.T) {
+	for _, tt := range []struct {
+		t   types.Type
+		has bool
+	}{
+		{types.Int32, true},
+		{types.Date32, false},
+		{types.NewOptional(types.NewVariantTuple(types.Int32)), true},
+		{types.NewList(types.NewVariantTuple(types.Int32)), true},
+		{types.NewTuple(types.Text, types.NewDecimal(22, 9)), true},
+		{types.NewStruct(types.StructField{Name: "v", T: types.NewVariantTuple(types.Int32)}), false},
+		{types.NewVariantTuple(types.Int32), false},
+	} {
+		t.Run(tt.t.Yql(), func(t *testing.T) {
+			require.Equal(t, tt.has, HasZeroValue(tt.t))
+			if tt.has {
+				require.NotPanics(t, func() { ZeroValue(tt.t) })
+			} else {
+				require.Panics(t, func() { ZeroValue(tt.t) })
+			}
+		})
+	}
+}
@@ -0,0 +1,331 @@
+package metrics
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
+)
+
+var (
+	topicBytesBuckets            = []float64{0, 1 << 10, 4 << 10, 16 << 10, 64 << 10, 256 << 10, 1 << 20, 4 << 20, 16 << 20}
+	topicCompressionRatioBuckets = []float64{1, 1.25, 1.5, 2, 3, 5, 10, 20}
+)
+
+// topicTrace makes trace.Topic with publishing metrics of topic readers and writers
+func topicTrace(config Config) trace.Topic {
+	config = config.WithSystem("topic")
+	reader := topicReader(config.WithSystem("reader"))
+	writer := topicWriter(config.WithSystem("writer"))
+
+	return *reader.Compose(&writer)
+}
+
+type topicReaderPartitionSessionKey struct {
+	readerConnectionID string
+	partitionSessionID int64
+}
+
+//nolint:funlen
+func topicReader(config Config) (t trace.Topic) {
+	messages := config.CounterVec("messages", "topic")
+	bytes := config.HistogramVec("bytes", topicBytesBuckets, "topic")
+	commits := config.CounterVec("commits", "status", "topic")
+	commitLatency := config.TimerVec("commit_latency", "topic")
+	reconnects := config.CounterVec("reconnects", "status")
+	partitionSessions := config.GaugeVec("partition_sessions", "topic")
+
+	var (
+		sessionsMu sync.Mutex
+		sessions   = make(map[topicReaderPartitionSessionKey]string)
+	)
+
+	t.OnReaderReadMessages = func(info trace.TopicReaderReadMessagesStartInfo) func(
+		trace.TopicReaderReadMessagesDoneInfo,
+	) {
+		if config.Details()&trace.TopicReaderMessageEvents == 0 {
+			return nil
+		}
+
+		return func(info trace.TopicReaderReadMessagesDoneInfo) {
+			if info.Error != nil || info.MessagesCount == 0 {
+				return
+			}
+			labels := map[string]string{
+				"topic": info.Topic,
+			}
+			counter := messages.With(labels)
+			for i := 0; i < info.MessagesCount; i++ {
+				counter.Inc()
+			}
+			bytes.With(labels).Record(float64(info.BytesSize))
+		}
+	}
+	t.OnReaderCommit = func(info trace.TopicReaderCommitStartInfo) func(trace.TopicReaderCommitDoneInfo) {
+		if config.Details()&trace.TopicReaderStreamEvents == 0 {
+			return nil
+		}
+		topic := info.Topic
+		start := time.Now()
+
+		return func(info trace.TopicReaderCommitDoneInfo) {
+			commits.With(map[string]string{
+				"status": errorBrief(info.Error),
+				"topic":  topic,
+			}).Inc()
+			commitLatency.With(map[string]string{
+				"topic": topic,
+			}).Record(time.Since(start))
+		}
+	}
+	t.OnReaderReconnect = func(info trace.TopicReaderReconnectStartInfo) func(trace.TopicReaderReconnectDoneInfo) {
+		if config.Details()&trace.TopicReaderStreamLifeCycleEvents == 0 {
+			return nil
+		}
+
+		return func(info trace.TopicReaderReconnectDoneInfo) {
+			reconnects.With(map[string]string{
+				"status": errorBrief(info.Error),
+			}).Inc()
+		}
+	}
+	t.OnReaderPartitionReadStartResponse = func(info trace.TopicReaderPartitionReadStartResponseStartInfo) func(
+		trace.TopicReaderPartitionReadStartResponseDoneInfo,
+	) {
+		if config.Details()&trace.TopicReaderPartitionEvents == 0 {
+			return nil
+		}
+		key := topicReaderPartitionSessionKey{
+			readerConnectionID: info.ReaderConnectionID,
+			partitionSessionID: info.PartitionSessionID,
+		}
+		topic := info.Topic
+
+		return func(info trace.TopicReaderPartitionReadStartResponseDoneInfo) {
+			if info.Error != nil {
+				return
+			}
+			sessionsMu.Lock()
+			defer sessionsMu.Unlock()
+
+			if _, has := sessions[key]; !has {
+				sessions[key] = topic
+				partitionSessions.With(map[string]string{"topic": topic}).Add(1)
+			}
+		}
+	}
+	removeSessions := func(remove func(key topicReaderPartitionSessionKey) bool) {
+		sessionsMu.Lock()
+		defer sessionsMu.Unlock()
+
+		for key, topic := range sessions {
+			if remove(key) {
+				delete(sessions, key)
+				partitionSessions.With(map[string]string{"topic": topic}).Add(-1)
+			}
+		}
+	}
+	t.OnReaderPartitionReadStopResponse = func(info trace.TopicReaderPartitionReadStopResponseStartInfo) func(
+		trace.TopicReaderPartitionReadStopResponseDoneInfo,
+	) {
+		if config.Details()&trace.TopicReaderPartitionEvents == 0 {
+			return nil
+		}
+		stopped := topicReaderPartitionSessionKey{
+			readerConnectionID: info.ReaderConnectionID,
+			partitionSessionID: info.PartitionSessionID,
+		}
+		removeSessions(func(key topicReaderPartitionSessionKey) bool {
+			return key == stopped
+		})
+
+		return nil
+	}
+	t.OnReaderClose = func(info trace.TopicReaderCloseStartInfo) func(trace.TopicReaderCloseDoneInfo) {
+		if config.Details()&trace.TopicReaderPartitionEvents == 0 {
+			return nil
+		}
+		// partition sessions of the stream closed with the stream
+		readerConnectionID := info.ReaderConnectionID
+		removeSessions(func(key topicReaderPartitionSessionKey) bool {
+			return key.readerConnectionID == readerConnectionID
+		})
+
+		return nil
+	}
+
+	return t
+}
+
+// topicWriterSentBatch is messages, sent to server in one request and waiting for acks
+type topicWriterSentBatch struct {
+	lastSeqNo     int64
+	messagesCount int
+	sent          time.Time
+}
+
+type topicWriterState struct {
+	topic   string
+	batches []topicWriterSentBatch
+}
+
+//nolint:funlen
+func topicWriter(config Config) (t trace.Topic) {
+	messages := config.CounterVec("messages", "topic")
+	bytes := config.HistogramVec("bytes", topicBytesBuckets, "topic")
+	compressionRatio := config.HistogramVec("compression_ratio", topicCompressionRatioBuckets, "topic", "codec")
+	ackLatency := config.TimerVec("ack_latency", "topic")
+	inflight := config.GaugeVec("inflight_messages", "topic")
+	reconnects := config.CounterVec("reconnects", "status", "topic")
+
+	var (
+		writersMu sync.Mutex
+		writers   = make(map[string]*topicWriterState)
+	)
+
+	// dropBatches removes batches from head of the queue and returns count of removed messages
+	dropBatches := func(state *topicWriterState, drop func(batch topicWriterSentBatch) bool) (messagesCount int) {
+		for len(state.batches) > 0 && drop(state.batches[0]) {
+			messagesCount += state.batches[0].messagesCount
+			state.batches = state.batches[1:]
+		}
+
+		return messagesCount
+	}
+
+	t.OnWriterReconnect = func(info trace.TopicWriterReconnectStartInfo) func(
+		trace.TopicWriterReconnectConnectedInfo,
+	) func(trace.TopicWriterReconnectDoneInfo) {
+		if config.Details()&trace.TopicWriterStreamLifeCycleEvents == 0 {
+			return nil
+		}
+		topic := info.Topic
+
+		writersMu.Lock()
+		_, reconnect := writers[info.WriterInstanceID]
+		if !reconnect {
+			writers[info.WriterInstanceID] = &topicWriterState{topic: topic}
+		}
+		writersMu.Unlock()
+
+		return func(info trace.TopicWriterReconnectConnectedInfo) func(trace.TopicWriterReconnectDoneInfo) {
+			if reconnect {
+				reconnects.With(map[string]string{
+					"status": errorBrief(info.ConnectionResult),
+					"topic":  topic,
+				}).Inc()
+			}
+
+			return nil
+		}
+	}
+	t.OnWriterClose = func(info trace.TopicWriterCloseStartInfo) func(trace.TopicWriterCloseDoneInfo) {
+		if config.Details()&trace.TopicWriterStreamLifeCycleEvents == 0 {
+			return nil
+		}
+
+		writersMu.Lock()
+		defer writersMu.Unlock()
+
+		if state, has := writers[info.WriterInstanceID]; has {
+			delete(writers, info.WriterInstanceID)
+			dropped := dropBatches(state, func(topicWriterSentBatch) bool { return true })
+			inflight.With(map[string]string{"topic": state.topic}).Add(-float64(dropped))
+		}
+
+		return nil
+	}
+	t.OnWriterSendMessages = func(info trace.TopicWriterSendMessagesStartInfo) func(
+		trace.TopicWriterSendMessagesDoneInfo,
+	) {
+		if config.Details()&trace.TopicWriterStreamEvents == 0 {
+			return nil
+		}
+		var (
+			writerInstanceID  = info.WriterInstanceID
+			codec             = strconv.Itoa(int(info.Codec))
+			firstSeqNo        = info.FirstSeqNo
+			messagesCount     = info.MessagesCount
+			uncompressedBytes = info.UncompressedBytes
+			encodedBytes      = info.EncodedBytes
+			start             = time.Now()
+		)
+
+		return func(info trace.TopicWriterSendMessagesDoneInfo) {
+			if info.Error != nil {
+				return
+			}
+
+			writersMu.Lock()
+			defer writersMu.Unlock()
+
+			state, has := writers[writerInstanceID]
+			if !has {
+				return
+			}
+			labels := map[string]string{
+				"topic": state.topic,
+			}
+			counter := messages.With(labels)
+			for i := 0; i < messagesCount; i++ {
+				counter.Inc()
+			}
+			bytes.With(labels).Record(float64(encodedBytes))
+			if encodedBytes > 0 {
+				compressionRatio.With(map[string]string{
+					"topic": state.topic,
+					"codec": codec,
+				}).Record(float64(uncompressedBytes) / float64(encodedBytes))
+			}
+
+			// messages resent after reconnect of the writer, forget previous send of them
+			resent := 0
+			for i := len(state.batches) - 1; i >= 0 && state.batches[i].lastSeqNo >= firstSeqNo; i-- {
+				resent += state.batches[i].messagesCount
+				state.batches = state.batches[:i]
+			}
+
+			// seqno of messages in one request expected consecutive, as auto seqno does
+			state.batches = append(state.batches, topicWriterSentBatch{
+				lastSeqNo:     firstSeqNo + int64(messagesCount) - 1,
+				messagesCount: messagesCount,
+				sent:          start,
+			})
+			inflight.With(labels).Add(float64(messagesCount - resent))
+		}
+	}
+	t.OnWriterReceiveResult = func(info trace.TopicWriterResultMessagesInfo) {
+		if config.Details()&trace.TopicWriterStreamEvents == 0 {
+			return
+		}
+		acks := info.Acks.GetAcks()
+		if acks.AcksCount == 0 {
+			return
+		}
+
+		writersMu.Lock()
+		defer writersMu.Unlock()
+
+		state, has := writers[info.WriterInstanceID]
+		if !has {
+			return
+		}
+		labels := map[string]string{
+			"topic": state.topic,
+		}
+		now := time.Now()
+		latency := ackLatency.With(labels)
+		acked := dropBatches(state, func(batch topicWriterSentBatch) bool {
+			if batch.lastSeqNo > acks.SeqNoMax {
+				return false
+			}
+			latency.Record(now.Sub(batch.sent))
+
+			return true
+		})
+		inflight.With(labels).Add(-float64(acked))
+	}
+
+	return t
+}
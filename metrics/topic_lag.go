@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic"
+)
+
+// WithTopicLagMetrics returns option for topic.LagMonitor, which exports consumers lag as gauges.
+// Gauges of partitions, which disappeared from the topic, are set to zero.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithTopicLagMetrics(config Config) topic.LagMonitorOption {
+	if config == nil {
+		return nil
+	}
+	config = config.WithSystem("topic").WithSystem("consumer")
+	messages := config.GaugeVec("lag_messages", "topic", "consumer", "partition")
+	seconds := config.GaugeVec("lag_seconds", "topic", "consumer", "partition")
+	totalMessages := config.GaugeVec("total_lag_messages", "topic", "consumer")
+	maxSeconds := config.GaugeVec("max_lag_seconds", "topic", "consumer")
+	alerts := config.GaugeVec("lag_alert", "topic", "consumer", "partition")
+
+	type partitionKey struct {
+		topic       string
+		consumer    string
+		partitionID int64
+	}
+	knownPartitions := make(map[partitionKey]struct{})
+	knownPartitionsMu := sync.Mutex{}
+
+	return topic.MergeLagMonitorOptions(topic.WithLagMonitorOnLag(func(lag topic.ConsumerLag) {
+		knownPartitionsMu.Lock()
+		defer knownPartitionsMu.Unlock()
+
+		partitions := make(map[partitionKey]struct{}, len(lag.Partitions))
+		for _, p := range lag.Partitions {
+			key := partitionKey{topic: lag.Topic, consumer: lag.Consumer, partitionID: p.PartitionID}
+			partitions[key] = struct{}{}
+			knownPartitions[key] = struct{}{}
+			labels := map[string]string{
+				"topic":     lag.Topic,
+				"consumer":  lag.Consumer,
+				"partition": strconv.FormatInt(p.PartitionID, 10),
+			}
+			messages.With(labels).Set(float64(p.MessageLag))
+			seconds.With(labels).Set(p.TimeLag.Seconds())
+		}
+		for key := range knownPartitions {
+			if _, has := partitions[key]; has || key.topic != lag.Topic || key.consumer != lag.Consumer {
+				continue
+			}
+			delete(knownPartitions, key)
+			labels := map[string]string{
+				"topic":     key.topic,
+				"consumer":  key.consumer,
+				"partition": strconv.FormatInt(key.partitionID, 10),
+			}
+			messages.With(labels).Set(0)
+			seconds.With(labels).Set(0)
+		}
+		labels := map[string]string{
+			"topic":    lag.Topic,
+			"consumer": lag.Consumer,
+		}
+		totalMessages.With(labels).Set(float64(lag.MessageLag))
+		maxSeconds.With(labels).Set(lag.TimeLag.Seconds())
+	}), topic.WithLagMonitorOnAlert(func(alert topic.LagAlert) {
+		value := 0.0
+		if alert.Firing {
+			value = 1
+		}
+		alerts.With(map[string]string{
+			"topic":     alert.Topic,
+			"consumer":  alert.Consumer,
+			"partition": strconv.FormatInt(alert.Partition.PartitionID, 10),
+		}).Set(value)
+	}))
+}
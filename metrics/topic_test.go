@@ -0,0 +1,267 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicoptions"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topictypes"
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
+)
+
+type testMetric struct {
+	m      *sync.Mutex
+	values map[string]float64
+	key    string
+}
+
+func (m testMetric) Inc() {
+	m.Add(1)
+}
+
+func (m testMetric) Add(delta float64) {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	m.values[m.key] += delta
+}
+
+func (m testMetric) Set(value float64) {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	m.values[m.key] = value
+}
+
+func (m testMetric) Record(value float64) {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	m.values[m.key+":count"]++
+	m.values[m.key+":sum"] += value
+}
+
+type testMetricVec struct {
+	testMetric
+}
+
+func (v testMetricVec) With(labels map[string]string) testMetric {
+	keys := make([]string, 0, len(labels))
+	for k, v := range labels {
+		keys = append(keys, k+"="+v)
+	}
+	sort.Strings(keys)
+
+	return testMetric{m: v.m, values: v.values, key: v.key + "{" + strings.Join(keys, ",") + "}"}
+}
+
+type testCounterVec struct{ testMetricVec }
+
+func (v testCounterVec) With(labels map[string]string) Counter { return v.testMetricVec.With(labels) }
+
+type testGaugeVec struct{ testMetricVec }
+
+func (v testGaugeVec) With(labels map[string]string) Gauge { return v.testMetricVec.With(labels) }
+
+type testHistogramVec struct{ testMetricVec }
+
+func (v testHistogramVec) With(labels map[string]string) Histogram {
+	return v.testMetricVec.With(labels)
+}
+
+type testTimer struct{ testMetric }
+
+func (t testTimer) Record(value time.Duration) { t.testMetric.Record(value.Seconds()) }
+
+type testTimerVec struct{ testMetricVec }
+
+func (v testTimerVec) With(labels map[string]string) Timer {
+	return testTimer{v.testMetricVec.With(labels)}
+}
+
+type testConfig struct {
+	m      *sync.Mutex
+	values map[string]float64
+	prefix string
+}
+
+func newTestConfig() *testConfig {
+	return &testConfig{m: &sync.Mutex{}, values: make(map[string]float64)}
+}
+
+func (c *testConfig) vec(name string) testMetricVec {
+	return testMetricVec{testMetric{m: c.m, values: c.values, key: c.prefix + name}}
+}
+
+func (c *testConfig) CounterVec(name string, _ ...string) CounterVec {
+	return testCounterVec{c.vec(name)}
+}
+
+func (c *testConfig) GaugeVec(name string, _ ...string) GaugeVec {
+	return testGaugeVec{c.vec(name)}
+}
+
+func (c *testConfig) TimerVec(name string, _ ...string) TimerVec {
+	return testTimerVec{c.vec(name)}
+}
+
+func (c *testConfig) HistogramVec(name string, _ []float64, _ ...string) HistogramVec {
+	return testHistogramVec{c.vec(name)}
+}
+
+func (c *testConfig) Details() trace.Details {
+	return trace.DetailsAll
+}
+
+func (c *testConfig) WithSystem(subsystem string) Config {
+	return &testConfig{m: c.m, values: c.values, prefix: fmt.Sprintf("%s%s/", c.prefix, subsystem)}
+}
+
+func (c *testConfig) value(key string) float64 {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	return c.values[key]
+}
+
+type testAcks struct {
+	count              int
+	seqNoMin, seqNoMax int64
+}
+
+func (a testAcks) GetAcks() (res struct {
+	AcksCount        int
+	SeqNoMin         int64
+	SeqNoMax         int64
+	WrittenOffsetMin int64
+	WrittenOffsetMax int64
+	WrittenCount     int
+	WrittenInTxCount int
+	SkipCount        int
+},
+) {
+	res.AcksCount = a.count
+	res.SeqNoMin = a.seqNoMin
+	res.SeqNoMax = a.seqNoMax
+
+	return res
+}
+
+func TestTopicWriterMetrics(t *testing.T) {
+	config := newTestConfig()
+	tr := topicTrace(config)
+	ctx := context.Background()
+
+	trace.TopicOnWriterReconnect(&tr, &ctx, "writer", "topic", "producer", 0)(nil)(nil)
+	trace.TopicOnWriterSendMessages(&tr, &ctx, "writer", "session", 1, 1, 2, 200, 100)(nil)
+	trace.TopicOnWriterSendMessages(&tr, &ctx, "writer", "session", 1, 3, 3, 300, 100)(nil)
+
+	require.Equal(t, 5.0, config.value("topic/writer/messages{topic=topic}"))
+	require.Equal(t, 2.0, config.value("topic/writer/bytes{topic=topic}:count"))
+	require.Equal(t, 200.0, config.value("topic/writer/bytes{topic=topic}:sum"))
+	require.Equal(t, 5.0, config.value("topic/writer/compression_ratio{codec=1,topic=topic}:sum"))
+	require.Equal(t, 5.0, config.value("topic/writer/inflight_messages{topic=topic}"))
+
+	trace.TopicOnWriterReceiveResult(&tr, &ctx, "writer", "session", 0, testAcks{count: 2, seqNoMin: 1, seqNoMax: 2})
+	require.Equal(t, 3.0, config.value("topic/writer/inflight_messages{topic=topic}"))
+	require.Equal(t, 1.0, config.value("topic/writer/ack_latency{topic=topic}:count"))
+
+	// reconnect and resend not acked messages
+	trace.TopicOnWriterReconnect(&tr, &ctx, "writer", "topic", "producer", 1)(errors.New("test"))(nil)
+	trace.TopicOnWriterReconnect(&tr, &ctx, "writer", "topic", "producer", 2)(nil)(nil)
+	trace.TopicOnWriterSendMessages(&tr, &ctx, "writer", "session2", 1, 3, 3, 300, 100)(nil)
+	require.Equal(t, 3.0, config.value("topic/writer/inflight_messages{topic=topic}"))
+	require.Equal(t, 1.0, config.value("topic/writer/reconnects{status=OK,topic=topic}"))
+
+	trace.TopicOnWriterClose(&tr, &ctx, "writer", nil)(nil)
+	require.Equal(t, 0.0, config.value("topic/writer/inflight_messages{topic=topic}"))
+}
+
+func TestTopicReaderMetrics(t *testing.T) {
+	config := newTestConfig()
+	tr := topicTrace(config)
+	ctx := context.Background()
+
+	trace.TopicOnReaderPartitionReadStartResponse(&tr, "conn", &ctx, "topic", 1, 10)(nil, nil, nil)
+	trace.TopicOnReaderPartitionReadStartResponse(&tr, "conn", &ctx, "topic", 2, 11)(nil, nil, nil)
+	require.Equal(t, 2.0, config.value("topic/reader/partition_sessions{topic=topic}"))
+
+	trace.TopicOnReaderReadMessages(&tr, &ctx, 1, 10, 100)(3, 30, nil, "topic", 1, 10, 0, 3, 100, nil)
+	require.Equal(t, 3.0, config.value("topic/reader/messages{topic=topic}"))
+	require.Equal(t, 30.0, config.value("topic/reader/bytes{topic=topic}:sum"))
+
+	trace.TopicOnReaderCommit(&tr, &ctx, "topic", 1, 10, 0, 3)(nil)
+	require.Equal(t, 1.0, config.value("topic/reader/commits{status=OK,topic=topic}"))
+	require.Equal(t, 1.0, config.value("topic/reader/commit_latency{topic=topic}:count"))
+
+	trace.TopicOnReaderPartitionReadStopResponse(&tr, "conn", ctx, "topic", 1, 10, 3, true)(nil)
+	// second stop message for the session
+	trace.TopicOnReaderPartitionReadStopResponse(&tr, "conn", ctx, "topic", 1, 10, 3, false)(nil)
+	require.Equal(t, 1.0, config.value("topic/reader/partition_sessions{topic=topic}"))
+
+	trace.TopicOnReaderClose(&tr, &ctx, "conn", nil)(nil)
+	require.Equal(t, 0.0, config.value("topic/reader/partition_sessions{topic=topic}"))
+}
+
+type lagTestClient struct {
+	m          sync.Mutex
+	partitions []topictypes.DescribeConsumerPartitionInfo
+}
+
+func (c *lagTestClient) DescribeTopicConsumer(
+	context.Context, string, string, ...topicoptions.DescribeConsumerOption,
+) (topictypes.TopicConsumerDescription, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	return topictypes.TopicConsumerDescription{Partitions: c.partitions}, nil
+}
+
+func (c *lagTestClient) set(ends ...int64) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.partitions = make([]topictypes.DescribeConsumerPartitionInfo, len(ends))
+	for i := range ends {
+		c.partitions[i].PartitionID = int64(i)
+		c.partitions[i].PartitionStats.PartitionsOffset.End = ends[i]
+	}
+}
+
+func TestTopicLagMetrics(t *testing.T) {
+	ctx := context.Background()
+	config := newTestConfig()
+	client := &lagTestClient{}
+	client.set(5, 7)
+
+	monitor, err := topic.NewLagMonitor(client, []topic.LagMonitorConsumer{{Topic: "topic", Consumer: "consumer"}},
+		topic.WithLagMonitorInterval(time.Hour),
+		WithTopicLagMetrics(config),
+	)
+	require.NoError(t, err)
+	defer func() {
+		_ = monitor.Close(ctx)
+	}()
+
+	_, err = monitor.Check(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 7.0, config.value("topic/consumer/lag_messages{consumer=consumer,partition=1,topic=topic}"))
+	require.Equal(t, 12.0, config.value("topic/consumer/total_lag_messages{consumer=consumer,topic=topic}"))
+
+	// partition 1 disappeared
+	client.set(5)
+	_, err = monitor.Check(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 5.0, config.value("topic/consumer/lag_messages{consumer=consumer,partition=0,topic=topic}"))
+	require.Equal(t, 0.0, config.value("topic/consumer/lag_messages{consumer=consumer,partition=1,topic=topic}"))
+	require.Equal(t, 5.0, config.value("topic/consumer/total_lag_messages{consumer=consumer,topic=topic}"))
+}
@@ -21,5 +21,6 @@ func WithTraces(config Config) ydb.Option {
 		ydb.WithTraceDiscovery(discovery(config)),
 		ydb.WithTraceDatabaseSQL(DatabaseSQL(config)),
 		ydb.WithTraceRetry(retry(config)),
+		ydb.WithTraceTopic(topicTrace(config)),
 	)
 }
@@ -0,0 +1,40 @@
+// Package params contains helpers for rendering query parameters into YQL,
+// for example for reproducing production queries in YDB CLI or in bug reports
+package params
+
+import (
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/params"
+	"github.com/ydb-platform/ydb-go-sdk/v3/table/types"
+)
+
+type (
+	Parameters = params.Parameters
+
+	// Redactor masks values of sensitive parameters.
+	// Redactor returns replacement of value and true if value must be masked
+	Redactor = params.Redactor
+
+	Option = params.YQLOption
+)
+
+// ToYQL renders parameters into YQL named expressions like `$id = 1ul; -- Uint64`,
+// which can be prepended to the query text instead of passing parameters
+func ToYQL(p Parameters, opts ...Option) string {
+	return params.ToYQL(p, opts...)
+}
+
+// WithRedactor appends redactor for masking values of sensitive parameters
+func WithRedactor(r Redactor) Option {
+	return params.WithRedactor(r)
+}
+
+// RedactNames makes redactor which masks parameters with given names.
+// Masked value keeps the type of original value, so rendered script is still executable
+func RedactNames(names ...string) Redactor {
+	return params.RedactNames(names...)
+}
+
+// RedactedValue returns placeholder value of type t for custom redactors
+func RedactedValue(t types.Type) types.Value {
+	return params.RedactedValue(t)
+}
@@ -2,6 +2,7 @@ package query
 
 import (
 	"context"
+	"io"
 
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/query/arrow"
 )
@@ -17,4 +18,25 @@ type ArrowExecutor interface {
 	QueryArrow(ctx context.Context, sql string, opts ...ExecuteOption) (ArrowResult, error)
 }
 
-type ArrowResult = arrow.Result
+type (
+	ArrowResult = arrow.Result
+
+	// ArrowReader decodes parts of [ArrowResult] into record batches with typed columns
+	// without dependency on Apache Arrow Go module
+	//
+	// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+	ArrowReader      = arrow.Reader
+	ArrowSchema      = arrow.Schema
+	ArrowField       = arrow.Field
+	ArrowDataType    = arrow.DataType
+	ArrowRecordBatch = arrow.RecordBatch
+	ArrowColumn      = arrow.Column
+	ArrowBitmap      = arrow.Bitmap
+)
+
+// NewArrowReader makes ArrowReader over part of [ArrowResult]
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func NewArrowReader(part io.Reader) (*ArrowReader, error) {
+	return arrow.NewReader(part)
+}
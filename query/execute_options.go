@@ -73,3 +73,10 @@ func WithCallOptions(opts ...grpc.CallOption) ExecuteOption {
 func WithResourcePool(id string) ExecuteOption {
 	return options.WithResourcePool(id)
 }
+
+// Debug renders query text with inlined parameters as executable YQL script.
+// Declarations of inlined parameters are removed from query text.
+// Values of sensitive parameters can be masked with params.WithRedactor option
+func Debug(sql string, parameters params.Parameters, opts ...params.YQLOption) string {
+	return params.Inline(sql, parameters, opts...)
+}
@@ -0,0 +1,185 @@
+package spans
+
+import (
+	"strings"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/kv"
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
+)
+
+// TopicTraceParentMetadataKey is key of message metadata item with trace context of the message producer
+const TopicTraceParentMetadataKey = "traceparent"
+
+// remoteSpan is span of other process, restored from traceparent for link spans
+type remoteSpan struct {
+	traceID string
+	spanID  string
+}
+
+func (s remoteSpan) ID() (string, bool) {
+	return s.spanID, true
+}
+
+func (s remoteSpan) TraceID() (string, bool) {
+	return s.traceID, true
+}
+
+func (remoteSpan) Link(Span, ...KeyValue) {}
+
+func (remoteSpan) Log(string, ...KeyValue) {}
+
+func (remoteSpan) Warn(error, ...KeyValue) {}
+
+func (remoteSpan) Error(error, ...KeyValue) {}
+
+func (remoteSpan) End(...KeyValue) {}
+
+// parseTraceparent parses traceparent in format 00-{trace-id}-{span-id}-{flags}
+func parseTraceparent(traceparent string) (_ remoteSpan, ok bool) {
+	const (
+		traceIDLen = 32
+		spanIDLen  = 16
+	)
+
+	parts := strings.Split(traceparent, "-")
+	if len(parts) != 4 || len(parts[1]) != traceIDLen || len(parts[2]) != spanIDLen { //nolint:gomnd
+		return remoteSpan{}, false
+	}
+
+	return remoteSpan{traceID: parts[1], spanID: parts[2]}, true
+}
+
+// TopicProducerSpan returns span of the producer of topic message by message metadata.
+// The span may be used for link processing span of the message with producer span.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func TopicProducerSpan(metadata map[string][]byte) (_ Span, ok bool) {
+	return parseTraceparent(string(metadata[TopicTraceParentMetadataKey]))
+}
+
+func readMetadataSize(metadata trace.TopicReaderMessagesMetadata) int {
+	if metadata == nil {
+		return 0
+	}
+
+	return metadata.MetadataSize()
+}
+
+func linkTopicProducers(s Span, metadata trace.TopicReaderMessagesMetadata) {
+	if metadata == nil {
+		return
+	}
+
+	linked := make(map[string]struct{})
+	for _, value := range metadata.MetadataValues(TopicTraceParentMetadataKey) {
+		if _, has := linked[string(value)]; has {
+			continue
+		}
+		linked[string(value)] = struct{}{}
+
+		if producer, ok := parseTraceparent(string(value)); ok {
+			s.Link(producer)
+		}
+	}
+}
+
+// topic makes trace.Topic with publishing spans of topic writers and readers
+//
+//nolint:funlen
+func topic(adapter Adapter) (t trace.Topic) {
+	t.OnWriterWriteMessages = func(info trace.TopicWriterWriteMessagesStartInfo) func(
+		trace.TopicWriterWriteMessagesDoneInfo,
+	) {
+		if adapter.Details()&trace.TopicWriterStreamEvents == 0 {
+			return nil
+		}
+		start := childSpanWithReplaceCtx(
+			adapter,
+			info.Context,
+			"ydb.topic.writer.write",
+			kv.String("topic", info.Topic),
+			kv.String("producer_id", info.ProducerID),
+			kv.String("writer_instance_id", info.WriterInstanceID),
+			kv.Int("messages_count", info.MessagesCount),
+		)
+		if id, valid := start.ID(); valid {
+			if traceID, valid := start.TraceID(); valid && info.Metadata != nil {
+				info.Metadata.SetMetadata(TopicTraceParentMetadataKey, []byte(traceparent(traceID, id)))
+			}
+		}
+		metadataSize := 0
+		if info.Metadata != nil {
+			metadataSize = info.Metadata.MetadataSize()
+		}
+
+		return func(info trace.TopicWriterWriteMessagesDoneInfo) {
+			finish(start, info.Error, kv.Int("metadata_size", metadataSize))
+		}
+	}
+	t.OnReaderReadMessages = func(info trace.TopicReaderReadMessagesStartInfo) func(
+		trace.TopicReaderReadMessagesDoneInfo,
+	) {
+		if adapter.Details()&trace.TopicReaderMessageEvents == 0 {
+			return nil
+		}
+		start := childSpanWithReplaceCtx(
+			adapter,
+			info.Context,
+			"ydb.topic.reader.read",
+			kv.Int("min_count", info.MinCount),
+			kv.Int("max_count", info.MaxCount),
+		)
+
+		return func(info trace.TopicReaderReadMessagesDoneInfo) {
+			linkTopicProducers(start, info.Metadata)
+			finish(start, info.Error,
+				kv.String("topic", info.Topic),
+				kv.Int64("partition_id", info.PartitionID),
+				kv.Int64("offset_start", info.OffsetStart),
+				kv.Int64("offset_end", info.OffsetEnd),
+				kv.Int("messages_count", info.MessagesCount),
+				kv.Int("metadata_size", readMetadataSize(info.Metadata)),
+			)
+		}
+	}
+	t.OnReaderCommit = func(info trace.TopicReaderCommitStartInfo) func(trace.TopicReaderCommitDoneInfo) {
+		if adapter.Details()&trace.TopicReaderStreamEvents == 0 {
+			return nil
+		}
+		start := childSpanWithReplaceCtx(
+			adapter,
+			info.Context,
+			"ydb.topic.reader.commit",
+			kv.String("topic", info.Topic),
+			kv.Int64("partition_id", info.PartitionID),
+			kv.Int64("offset_start", info.StartOffset),
+			kv.Int64("offset_end", info.EndOffset),
+		)
+
+		return func(info trace.TopicReaderCommitDoneInfo) {
+			finish(start, info.Error)
+		}
+	}
+	t.OnPartitionWorkerHandlerCall = func(info trace.TopicPartitionWorkerHandlerCallStartInfo) func(
+		trace.TopicPartitionWorkerHandlerCallDoneInfo,
+	) {
+		if adapter.Details()&trace.TopicListenerWorkerEvents == 0 {
+			return nil
+		}
+		start := childSpanWithReplaceCtx(
+			adapter,
+			info.Context,
+			"ydb.topic.listener."+info.HandlerType,
+			kv.String("topic", info.Topic),
+			kv.Int64("partition_id", info.PartitionID),
+			kv.Int("messages_count", info.MessagesCount),
+		)
+		linkTopicProducers(start, info.Metadata)
+
+		return func(info trace.TopicPartitionWorkerHandlerCallDoneInfo) {
+			finish(start, info.Error)
+		}
+	}
+
+	return t
+}
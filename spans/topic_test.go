@@ -0,0 +1,102 @@
+package spans
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/trace"
+)
+
+type testSpan struct {
+	remoteSpan
+
+	links []Span
+	ended bool
+}
+
+func (s *testSpan) Link(link Span, _ ...KeyValue) {
+	s.links = append(s.links, link)
+}
+
+func (s *testSpan) End(...KeyValue) {
+	s.ended = true
+}
+
+type testAdapter struct {
+	spans []*testSpan
+}
+
+func (a *testAdapter) Details() trace.Details {
+	return trace.DetailsAll
+}
+
+func (a *testAdapter) SpanFromContext(context.Context) Span {
+	return &testSpan{}
+}
+
+func (a *testAdapter) Start(ctx context.Context, _ string, _ ...KeyValue) (context.Context, Span) {
+	s := &testSpan{remoteSpan: remoteSpan{
+		traceID: "8e3790822789a6917883e08d0eeb783e",
+		spanID:  "729d847ca290963e",
+	}}
+	a.spans = append(a.spans, s)
+
+	return ctx, s
+}
+
+type testWriterMetadata map[string][]byte
+
+func (m testWriterMetadata) SetMetadata(key string, value []byte) {
+	m[key] = value
+}
+
+func (m testWriterMetadata) MetadataSize() int {
+	return 0
+}
+
+type testReaderMetadata [][]byte
+
+func (m testReaderMetadata) MetadataValues(string) [][]byte {
+	return m
+}
+
+func (m testReaderMetadata) MetadataSize() int {
+	return 0
+}
+
+func TestTopicTraceContextPropagation(t *testing.T) {
+	adapter := &testAdapter{}
+	tr := topic(adapter)
+	ctx := context.Background()
+
+	metadata := testWriterMetadata{}
+	onWriteDone := trace.TopicOnWriterWriteMessages(&tr, &ctx, "writer", "topic", "producer", 1, metadata)
+	require.Equal(t,
+		"00-8e3790822789a6917883e08d0eeb783e-729d847ca290963e-01",
+		string(metadata[TopicTraceParentMetadataKey]),
+	)
+	require.False(t, adapter.spans[0].ended)
+	onWriteDone(nil)
+	require.True(t, adapter.spans[0].ended)
+
+	producer, ok := TopicProducerSpan(metadata)
+	require.True(t, ok)
+	id, _ := producer.ID()
+	require.Equal(t, "729d847ca290963e", id)
+
+	_, ok = TopicProducerSpan(map[string][]byte{TopicTraceParentMetadataKey: []byte("bad")})
+	require.False(t, ok)
+
+	trace.TopicOnPartitionWorkerHandlerCall(&tr, &ctx, "listener", "", 1, 2, "topic", "OnReadMessages", 3,
+		testReaderMetadata{
+			metadata[TopicTraceParentMetadataKey],
+			metadata[TopicTraceParentMetadataKey],
+			[]byte("bad"),
+		},
+	)(nil)
+	require.Len(t, adapter.spans, 2)
+	require.Equal(t, []Span{producer}, adapter.spans[1].links)
+	require.True(t, adapter.spans[1].ended)
+}
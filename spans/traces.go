@@ -20,5 +20,6 @@ func WithTraces(adapter Adapter) ydb.Option {
 		ydb.WithTraceDiscovery(discovery(adapter)),
 		ydb.WithTraceDatabaseSQL(databaseSQL(adapter)),
 		ydb.WithTraceRetry(Retry(adapter)),
+		ydb.WithTraceTopic(topic(adapter)),
 	)
 }
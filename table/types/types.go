@@ -81,6 +81,13 @@ func Optional(t Type) Type {
 	return types.NewOptional(t)
 }
 
+// PgType returns postgres type with given OID
+//
+// OID constants listed in https://github.com/postgres/postgres/blob/master/src/include/catalog/pg_type.dat
+func PgType(oid uint32) Type {
+	return types.PgType{OID: oid}
+}
+
 var DefaultDecimal = DecimalType(decimalPrecision, decimalScale)
 
 func DecimalType(precision, scale uint32) Type {
@@ -191,6 +191,9 @@ func JSONDocumentValueFromBytes(v []byte) Value {
 
 func DyNumberValue(v string) Value { return value.DyNumberValue(v) }
 
+// PgValue makes postgres value of type with given OID from text representation
+func PgValue(oid uint32, v string) Value { return value.PgValue(oid, v) }
+
 func VoidValue() Value { return value.VoidValue() }
 
 func NullValue(t Type) Value { return value.NullValue(t) }
@@ -471,3 +474,13 @@ func NullableDyNumberValue(v *string) Value {
 func Nullable(t Type, v interface{}) Value {
 	return value.Nullable(t, v)
 }
+
+// ValueOf converts arbitrary Go value into Value using reflection.
+//
+// Structs become Struct (fields named by `sql` tag), maps become Dict, slices become List,
+// arrays become Tuple, pointers become Optional, uuid.UUID becomes Uuid, time.Time becomes
+// Timestamp and big.Int or Decimal become Decimal. Default mapping of struct field can be
+// overridden with `ydb` tag, for example `ydb:"Date"` or `ydb:"Decimal(35,10)"`.
+func ValueOf(v any) (Value, error) {
+	return value.Of(v)
+}
@@ -0,0 +1,67 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/query"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicsugar"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicwriter"
+)
+
+func TestTopicTableOffsetStore(t *testing.T) {
+	scope := newScope(t)
+	ctx := scope.Ctx
+	db := scope.Driver()
+
+	store := topicsugar.NewTableOffsetStore(db.Query(), path.Join(scope.Folder(), "offsets"), "group")
+	require.NoError(t, store.CreateTable(ctx))
+
+	require.NoError(t, scope.TopicWriter().Write(ctx,
+		topicwriter.Message{Data: strings.NewReader("1")},
+		topicwriter.Message{Data: strings.NewReader("2")},
+		topicwriter.Message{Data: strings.NewReader("3")},
+	))
+
+	reader := scope.TopicReader()
+	first, err := reader.ReadMessage(ctx)
+	require.NoError(t, err)
+	second, err := reader.ReadMessage(ctx)
+	require.NoError(t, err)
+	third, err := reader.ReadMessage(ctx)
+	require.NoError(t, err)
+
+	_, ok, err := store.Offset(ctx, scope.TopicPath(), first.PartitionID())
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	t.Run("CommitForwardOnly", func(t *testing.T) {
+		require.NoError(t, store.Commit(ctx, second))
+		require.NoError(t, store.Commit(ctx, first))
+
+		offset, ok, err := store.Offset(ctx, scope.TopicPath(), first.PartitionID())
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, second.Offset+1, offset)
+	})
+	t.Run("CommitTx", func(t *testing.T) {
+		require.NoError(t, db.Query().DoTx(ctx, func(ctx context.Context, tx query.TxActor) error {
+			return store.CommitTx(ctx, tx, first)
+		}))
+		require.NoError(t, db.Query().DoTx(ctx, func(ctx context.Context, tx query.TxActor) error {
+			return store.CommitTx(ctx, tx, third)
+		}))
+
+		offset, ok, err := store.Offset(ctx, scope.TopicPath(), first.PartitionID())
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, third.Offset+1, offset)
+	})
+}
@@ -0,0 +1,251 @@
+package testutil
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/types"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/value"
+)
+
+const missing = "<missing>"
+
+// Difference describes mismatch of expected and actual values at Path.
+// Path is a chain of struct members, list/tuple indexes and dict keys, like `.items[3].price`.
+// Empty path means the root value
+type Difference struct {
+	Path     string
+	Expected string
+	Actual   string
+}
+
+func (d Difference) String() string {
+	path := d.Path
+	if path == "" {
+		path = "<root>"
+	}
+
+	return fmt.Sprintf("%s: expected %s, actual %s", path, d.Expected, d.Actual)
+}
+
+// DiffValues compares expected and actual values structurally and returns all found differences.
+// Containers (Optional, List, Tuple, Struct, Dict, Set, Variant) are compared recursively.
+// Returns nil if values are equal
+func DiffValues(expected, actual value.Value) []Difference {
+	var d differ
+	d.diff("", value.ToYDB(expected), value.ToYDB(actual))
+
+	return d.differences
+}
+
+// TestingT is an interface wrapper around *testing.T
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+// AssertValuesEqual asserts that expected and actual values are equal and reports
+// each found difference into t otherwise
+func AssertValuesEqual(t TestingT, expected, actual value.Value, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+
+	differences := DiffValues(expected, actual)
+	if len(differences) == 0 {
+		return true
+	}
+
+	var msg strings.Builder
+	msg.WriteString("Values are not equal:")
+	for _, d := range differences {
+		msg.WriteString("\n\t")
+		msg.WriteString(d.String())
+	}
+	if len(msgAndArgs) > 0 {
+		msg.WriteString("\nMessages: ")
+		msg.WriteString(messageFromMsgAndArgs(msgAndArgs))
+	}
+	t.Errorf("%s", msg.String())
+
+	return false
+}
+
+func messageFromMsgAndArgs(msgAndArgs []interface{}) string {
+	if format, ok := msgAndArgs[0].(string); ok {
+		return fmt.Sprintf(format, msgAndArgs[1:]...)
+	}
+
+	return fmt.Sprintf("%+v", msgAndArgs[0])
+}
+
+type differ struct {
+	differences []Difference
+}
+
+func (d *differ) add(path string, expected, actual string) {
+	d.differences = append(d.differences, Difference{
+		Path:     path,
+		Expected: expected,
+		Actual:   actual,
+	})
+}
+
+func render(v *Ydb.TypedValue) (s string) {
+	if v == nil {
+		return missing
+	}
+
+	defer func() {
+		if recover() != nil {
+			s = v.String()
+		}
+	}()
+
+	return value.FromYDB(v.GetType(), v.GetValue()).Yql()
+}
+
+func renderWithType(v *Ydb.TypedValue) string {
+	if v == nil {
+		return missing
+	}
+
+	return render(v) + " of type " + types.TypeFromYDB(v.GetType()).Yql()
+}
+
+//nolint:funlen,gocyclo
+func (d *differ) diff(path string, expected, actual *Ydb.TypedValue) {
+	if expected == nil || actual == nil {
+		if expected != actual {
+			d.add(path, render(expected), render(actual))
+		}
+
+		return
+	}
+
+	if !proto.Equal(expected.GetType(), actual.GetType()) {
+		d.add(path, renderWithType(expected), renderWithType(actual))
+
+		return
+	}
+
+	t, lhs, rhs := expected.GetType(), expected.GetValue(), actual.GetValue()
+
+	switch {
+	case t.GetOptionalType() != nil:
+		_, lIsNull := lhs.GetValue().(*Ydb.Value_NullFlagValue)
+		_, rIsNull := rhs.GetValue().(*Ydb.Value_NullFlagValue)
+		if lIsNull || rIsNull {
+			if lIsNull != rIsNull {
+				d.add(path, render(expected), render(actual))
+			}
+
+			return
+		}
+		inner := t.GetOptionalType().GetItem()
+		if inner.GetOptionalType() != nil {
+			lhs, rhs = lhs.GetNestedValue(), rhs.GetNestedValue()
+		}
+		d.diff(path, &Ydb.TypedValue{Type: inner, Value: lhs}, &Ydb.TypedValue{Type: inner, Value: rhs})
+	case t.GetListType() != nil:
+		item := t.GetListType().GetItem()
+		d.diffItems(path, lhs.GetItems(), rhs.GetItems(), func(int) *Ydb.Type { return item })
+	case t.GetTupleType() != nil:
+		elements := t.GetTupleType().GetElements()
+		d.diffItems(path, lhs.GetItems(), rhs.GetItems(), func(i int) *Ydb.Type { return elements[i] })
+	case t.GetStructType() != nil:
+		for i, member := range t.GetStructType().GetMembers() {
+			d.diff(path+"."+member.GetName(),
+				&Ydb.TypedValue{Type: member.GetType(), Value: lhs.GetItems()[i]},
+				&Ydb.TypedValue{Type: member.GetType(), Value: rhs.GetItems()[i]},
+			)
+		}
+	case t.GetDictType() != nil:
+		d.diffPairs(path, t.GetDictType().GetKey(), t.GetDictType().GetPayload(), lhs.GetPairs(), rhs.GetPairs())
+	case t.GetVariantType() != nil:
+		if lhs.GetVariantIndex() != rhs.GetVariantIndex() {
+			d.add(path, render(expected), render(actual))
+
+			return
+		}
+		var (
+			idx       = lhs.GetVariantIndex()
+			innerPath string
+			innerType *Ydb.Type
+		)
+		switch vt := t.GetVariantType().GetType().(type) {
+		case *Ydb.VariantType_TupleItems:
+			innerPath = path + "[" + strconv.Itoa(int(idx)) + "]"
+			innerType = vt.TupleItems.GetElements()[idx]
+		case *Ydb.VariantType_StructItems:
+			innerPath = path + "." + vt.StructItems.GetMembers()[idx].GetName()
+			innerType = vt.StructItems.GetMembers()[idx].GetType()
+		}
+		d.diff(innerPath,
+			&Ydb.TypedValue{Type: innerType, Value: lhs.GetNestedValue()},
+			&Ydb.TypedValue{Type: innerType, Value: rhs.GetNestedValue()},
+		)
+	default:
+		if !proto.Equal(lhs, rhs) {
+			d.add(path, render(expected), render(actual))
+		}
+	}
+}
+
+func (d *differ) diffItems(path string, lhs, rhs []*Ydb.Value, itemType func(i int) *Ydb.Type) {
+	for i := 0; i < len(lhs) || i < len(rhs); i++ {
+		var l, r *Ydb.TypedValue
+		if i < len(lhs) {
+			l = &Ydb.TypedValue{Type: itemType(i), Value: lhs[i]}
+		}
+		if i < len(rhs) {
+			r = &Ydb.TypedValue{Type: itemType(i), Value: rhs[i]}
+		}
+		d.diff(path+"["+strconv.Itoa(i)+"]", l, r)
+	}
+}
+
+func (d *differ) diffPairs(path string, keyType, payloadType *Ydb.Type, lhs, rhs []*Ydb.ValuePair) {
+	type pair struct {
+		l, r *Ydb.TypedValue
+	}
+	pairs := make(map[string]*pair, len(lhs))
+	for _, p := range lhs {
+		key := render(&Ydb.TypedValue{Type: keyType, Value: p.GetKey()})
+		pairs[key] = &pair{l: &Ydb.TypedValue{Type: payloadType, Value: p.GetPayload()}}
+	}
+	for _, p := range rhs {
+		key := render(&Ydb.TypedValue{Type: keyType, Value: p.GetKey()})
+		if _, has := pairs[key]; !has {
+			pairs[key] = &pair{}
+		}
+		pairs[key].r = &Ydb.TypedValue{Type: payloadType, Value: p.GetPayload()}
+	}
+
+	keys := make([]string, 0, len(pairs))
+	for key := range pairs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	_, isSet := payloadType.GetType().(*Ydb.Type_VoidType)
+	for _, key := range keys {
+		p := pairs[key]
+		if isSet && (p.l == nil || p.r == nil) {
+			expected, actual := key, key
+			if p.l == nil {
+				expected = missing
+			} else {
+				actual = missing
+			}
+			d.add(path+"["+key+"]", expected, actual)
+
+			continue
+		}
+		d.diff(path+"["+key+"]", p.l, p.r)
+	}
+}
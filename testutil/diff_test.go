@@ -0,0 +1,159 @@
+package testutil
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/types"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/value"
+)
+
+func order(price string, qty uint32, comment value.Value) value.Value {
+	return value.StructValue(
+		value.StructValueField{Name: "id", V: value.Uint64Value(1)},
+		value.StructValueField{Name: "items", V: value.ListValue(
+			value.StructValue(
+				value.StructValueField{Name: "price", V: value.DecimalValueFromBigInt(big.NewInt(100), 22, 9)},
+				value.StructValueField{Name: "qty", V: value.Uint32Value(1)},
+			),
+			value.StructValue(
+				value.StructValueField{Name: "price", V: must(value.DecimalValueFromString(price, 22, 9))},
+				value.StructValueField{Name: "qty", V: value.Uint32Value(qty)},
+			),
+		)},
+		value.StructValueField{Name: "comment", V: comment},
+	)
+}
+
+func must(v value.Value, err error) value.Value {
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+func TestDiffValues(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		expected    value.Value
+		actual      value.Value
+		differences []Difference
+	}{
+		{
+			name:     "Equal",
+			expected: order("1.5", 2, value.OptionalValue(value.TextValue("a"))),
+			actual:   order("1.5", 2, value.OptionalValue(value.TextValue("a"))),
+		},
+		{
+			name:     "NestedStruct",
+			expected: order("1.5", 2, value.NullValue(types.Text)),
+			actual:   order("2.5", 3, value.NullValue(types.Text)),
+			differences: []Difference{
+				{Path: ".items[1].price", Expected: `Decimal("1.500000000",22,9)`, Actual: `Decimal("2.500000000",22,9)`},
+				{Path: ".items[1].qty", Expected: "2u", Actual: "3u"},
+			},
+		},
+		{
+			name:     "Null",
+			expected: order("1.5", 2, value.NullValue(types.Text)),
+			actual:   order("1.5", 2, value.OptionalValue(value.TextValue("a"))),
+			differences: []Difference{
+				{Path: ".comment", Expected: "Nothing(Optional<Utf8>)", Actual: `Just("a"u)`},
+			},
+		},
+		{
+			name:     "ListLength",
+			expected: value.ListValue(value.Int32Value(1)),
+			actual:   value.ListValue(value.Int32Value(1), value.Int32Value(2)),
+			differences: []Difference{
+				{Path: "[1]", Expected: "<missing>", Actual: "2"},
+			},
+		},
+		{
+			name:     "Types",
+			expected: value.Int32Value(1),
+			actual:   value.Int64Value(1),
+			differences: []Difference{
+				{Path: "", Expected: "1 of type Int32", Actual: "1l of type Int64"},
+			},
+		},
+		{
+			name:     "Tuple",
+			expected: value.TupleValue(value.Int32Value(1), value.TextValue("a")),
+			actual:   value.TupleValue(value.Int32Value(1), value.TextValue("b")),
+			differences: []Difference{
+				{Path: "[1]", Expected: `"a"u`, Actual: `"b"u`},
+			},
+		},
+		{
+			name: "Dict",
+			expected: value.DictValue(
+				value.DictValueField{K: value.TextValue("a"), V: value.Int32Value(1)},
+				value.DictValueField{K: value.TextValue("b"), V: value.Int32Value(2)},
+			),
+			actual: value.DictValue(
+				value.DictValueField{K: value.TextValue("a"), V: value.Int32Value(2)},
+				value.DictValueField{K: value.TextValue("c"), V: value.Int32Value(3)},
+			),
+			differences: []Difference{
+				{Path: `["a"u]`, Expected: "1", Actual: "2"},
+				{Path: `["b"u]`, Expected: "2", Actual: "<missing>"},
+				{Path: `["c"u]`, Expected: "<missing>", Actual: "3"},
+			},
+		},
+		{
+			name:     "Set",
+			expected: value.SetValue(value.Int32Value(1), value.Int32Value(2)),
+			actual:   value.SetValue(value.Int32Value(1), value.Int32Value(3)),
+			differences: []Difference{
+				{Path: "[2]", Expected: "2", Actual: "<missing>"},
+				{Path: "[3]", Expected: "<missing>", Actual: "3"},
+			},
+		},
+		{
+			name: "Variant",
+			expected: value.VariantValueStruct(value.Int32Value(1), "a", types.NewVariantStruct(
+				types.StructField{Name: "a", T: types.Int32},
+				types.StructField{Name: "b", T: types.Text},
+			)),
+			actual: value.VariantValueStruct(value.Int32Value(2), "a", types.NewVariantStruct(
+				types.StructField{Name: "a", T: types.Int32},
+				types.StructField{Name: "b", T: types.Text},
+			)),
+			differences: []Difference{
+				{Path: ".a", Expected: "1", Actual: "2"},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.differences, DiffValues(tt.expected, tt.actual))
+		})
+	}
+}
+
+type recordingT struct {
+	messages []string
+}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.messages = append(t.messages, fmt.Sprintf(format, args...))
+}
+
+func TestAssertValuesEqual(t *testing.T) {
+	rt := &recordingT{}
+	require.True(t, AssertValuesEqual(rt, value.Int32Value(1), value.Int32Value(1)))
+	require.Empty(t, rt.messages)
+
+	require.False(t, AssertValuesEqual(rt,
+		value.ListValue(value.Int32Value(1)),
+		value.ListValue(value.Int32Value(2)),
+		"row %d", 5,
+	))
+	require.Equal(t, []string{
+		"Values are not equal:\n\t[0]: expected 1, actual 2\nMessages: row 5",
+	}, rt.messages)
+}
@@ -0,0 +1,349 @@
+package topicfake
+
+import (
+	"context"
+	"path"
+
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Operations"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Scheme"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Topic"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func (s *Server) CreateTopic(
+	_ context.Context,
+	request *Ydb_Topic.CreateTopicRequest,
+) (*Ydb_Topic.CreateTopicResponse, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	topicPath := fullPath(request.GetPath())
+	if _, ok := s.topics[topicPath]; ok {
+		return &Ydb_Topic.CreateTopicResponse{Operation: operation(nil, newStatusError(
+			Ydb.StatusIds_ALREADY_EXISTS, "topic already exists: %v", request.GetPath(),
+		))}, nil
+	}
+
+	partitioning := request.GetPartitioningSettings()
+	if partitioning == nil {
+		partitioning = &Ydb_Topic.PartitioningSettings{}
+	}
+	partitioning = proto.Clone(partitioning).(*Ydb_Topic.PartitioningSettings) //nolint:forcetypeassert
+	if partitioning.GetMinActivePartitions() <= 0 {
+		partitioning.MinActivePartitions = 1
+	}
+	if partitioning.GetAutoPartitioningSettings() == nil {
+		partitioning.AutoPartitioningSettings = &Ydb_Topic.AutoPartitioningSettings{}
+	}
+
+	t := &topic{
+		path: topicPath,
+		description: &Ydb_Topic.DescribeTopicResult{
+			PartitioningSettings:              partitioning,
+			RetentionPeriod:                   request.GetRetentionPeriod(),
+			RetentionStorageMb:                request.GetRetentionStorageMb(),
+			SupportedCodecs:                   request.GetSupportedCodecs(),
+			PartitionWriteSpeedBytesPerSecond: request.GetPartitionWriteSpeedBytesPerSecond(),
+			PartitionWriteBurstBytes:          request.GetPartitionWriteBurstBytes(),
+			Attributes:                        request.GetAttributes(),
+			MeteringMode:                      request.GetMeteringMode(),
+		},
+	}
+	t.addPartitions(partitioning.GetMinActivePartitions())
+	for _, c := range request.GetConsumers() {
+		t.consumers = append(t.consumers, newConsumer(c))
+	}
+	s.topics[topicPath] = t
+
+	return &Ydb_Topic.CreateTopicResponse{Operation: operation(nil, nil)}, nil
+}
+
+func (s *Server) DescribeTopic(
+	_ context.Context,
+	request *Ydb_Topic.DescribeTopicRequest,
+) (*Ydb_Topic.DescribeTopicResponse, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	t, err := s.topic(request.GetPath())
+	if err != nil {
+		return &Ydb_Topic.DescribeTopicResponse{Operation: operation(nil, err)}, nil
+	}
+
+	result := proto.Clone(t.description).(*Ydb_Topic.DescribeTopicResult) //nolint:forcetypeassert
+	result.Self = schemeEntry(t.path)
+	for _, p := range t.partitions {
+		info := &Ydb_Topic.DescribeTopicResult_PartitionInfo{
+			PartitionId: p.id,
+			Active:      true,
+		}
+		if request.GetIncludeStats() {
+			info.PartitionStats = partitionStats(p)
+		}
+		result.Partitions = append(result.Partitions, info)
+	}
+	for _, c := range t.consumers {
+		result.Consumers = append(result.Consumers, c.description)
+	}
+
+	return &Ydb_Topic.DescribeTopicResponse{Operation: operation(result, nil)}, nil
+}
+
+func (s *Server) DescribeConsumer(
+	_ context.Context,
+	request *Ydb_Topic.DescribeConsumerRequest,
+) (*Ydb_Topic.DescribeConsumerResponse, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	t, err := s.topic(request.GetPath())
+	if err != nil {
+		return &Ydb_Topic.DescribeConsumerResponse{Operation: operation(nil, err)}, nil
+	}
+	c, err := t.consumer(request.GetConsumer())
+	if err != nil {
+		return &Ydb_Topic.DescribeConsumerResponse{Operation: operation(nil, err)}, nil
+	}
+
+	result := &Ydb_Topic.DescribeConsumerResult{
+		Self:     schemeEntry(path.Join(t.path, c.description.GetName())),
+		Consumer: c.description,
+	}
+	for _, p := range t.partitions {
+		info := &Ydb_Topic.DescribeConsumerResult_PartitionInfo{
+			PartitionId: p.id,
+			Active:      true,
+		}
+		if request.GetIncludeStats() {
+			info.PartitionStats = partitionStats(p)
+			info.PartitionConsumerStats = &Ydb_Topic.DescribeConsumerResult_PartitionConsumerStats{
+				CommittedOffset: c.committed[p.id],
+			}
+			if reader := c.readers[p.id]; reader != nil {
+				info.PartitionConsumerStats.ReadSessionId = reader.id
+				info.PartitionConsumerStats.LastReadOffset = reader.lastReadOffset(p)
+			}
+		}
+		result.Partitions = append(result.Partitions, info)
+	}
+
+	return &Ydb_Topic.DescribeConsumerResponse{Operation: operation(result, nil)}, nil
+}
+
+func (s *Server) AlterTopic(
+	_ context.Context,
+	request *Ydb_Topic.AlterTopicRequest,
+) (*Ydb_Topic.AlterTopicResponse, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	t, err := s.topic(request.GetPath())
+	if err != nil {
+		return &Ydb_Topic.AlterTopicResponse{Operation: operation(nil, err)}, nil
+	}
+
+	if err := alterConsumers(t, request); err != nil {
+		return &Ydb_Topic.AlterTopicResponse{Operation: operation(nil, err)}, nil
+	}
+
+	description := t.description
+	if settings := request.GetAlterPartitioningSettings(); settings != nil {
+		if settings.SetMinActivePartitions != nil {
+			if settings.GetSetMinActivePartitions() < int64(len(t.partitions)) {
+				return &Ydb_Topic.AlterTopicResponse{Operation: operation(nil, newStatusError(
+					Ydb.StatusIds_BAD_REQUEST, "partitions count can't be decreased",
+				))}, nil
+			}
+			description.PartitioningSettings.MinActivePartitions = settings.GetSetMinActivePartitions()
+			t.addPartitions(settings.GetSetMinActivePartitions())
+		}
+		if settings.SetMaxActivePartitions != nil {
+			description.PartitioningSettings.MaxActivePartitions = settings.GetSetMaxActivePartitions()
+		}
+	}
+	if request.GetSetRetentionPeriod() != nil {
+		description.RetentionPeriod = request.GetSetRetentionPeriod()
+	}
+	if request.SetRetentionStorageMb != nil {
+		description.RetentionStorageMb = request.GetSetRetentionStorageMb()
+	}
+	if request.GetSetSupportedCodecs() != nil {
+		description.SupportedCodecs = request.GetSetSupportedCodecs()
+	}
+	if request.SetPartitionWriteSpeedBytesPerSecond != nil {
+		description.PartitionWriteSpeedBytesPerSecond = request.GetSetPartitionWriteSpeedBytesPerSecond()
+	}
+	if request.SetPartitionWriteBurstBytes != nil {
+		description.PartitionWriteBurstBytes = request.GetSetPartitionWriteBurstBytes()
+	}
+	description.Attributes = alterAttributes(description.GetAttributes(), request.GetAlterAttributes())
+	if request.GetSetMeteringMode() != Ydb_Topic.MeteringMode_METERING_MODE_UNSPECIFIED {
+		description.MeteringMode = request.GetSetMeteringMode()
+	}
+	s.notifyChanged()
+
+	return &Ydb_Topic.AlterTopicResponse{Operation: operation(nil, nil)}, nil
+}
+
+func alterConsumers(t *topic, request *Ydb_Topic.AlterTopicRequest) error {
+	for _, name := range request.GetDropConsumers() {
+		if _, err := t.consumer(name); err != nil {
+			return err
+		}
+	}
+	for _, alter := range request.GetAlterConsumers() {
+		if _, err := t.consumer(alter.GetName()); err != nil {
+			return err
+		}
+	}
+	for _, c := range request.GetAddConsumers() {
+		if _, err := t.consumer(c.GetName()); err == nil {
+			return newStatusError(Ydb.StatusIds_BAD_REQUEST, "consumer %q already exists", c.GetName())
+		}
+	}
+
+	for _, name := range request.GetDropConsumers() {
+		for i := range t.consumers {
+			if t.consumers[i].description.GetName() == name {
+				t.consumers = append(t.consumers[:i], t.consumers[i+1:]...)
+
+				break
+			}
+		}
+	}
+	for _, alter := range request.GetAlterConsumers() {
+		c, _ := t.consumer(alter.GetName())
+		description := proto.Clone(c.description).(*Ydb_Topic.Consumer) //nolint:forcetypeassert
+		if alter.SetImportant != nil {
+			description.Important = alter.GetSetImportant()
+		}
+		if alter.GetSetReadFrom() != nil {
+			description.ReadFrom = alter.GetSetReadFrom()
+		}
+		if alter.GetSetSupportedCodecs() != nil {
+			description.SupportedCodecs = alter.GetSetSupportedCodecs()
+		}
+		description.Attributes = alterAttributes(description.GetAttributes(), alter.GetAlterAttributes())
+		c.description = description
+	}
+	for _, c := range request.GetAddConsumers() {
+		t.consumers = append(t.consumers, newConsumer(c))
+	}
+
+	return nil
+}
+
+func alterAttributes(attributes, alter map[string]string) map[string]string {
+	if len(alter) == 0 {
+		return attributes
+	}
+
+	res := make(map[string]string, len(attributes)+len(alter))
+	for k, v := range attributes {
+		res[k] = v
+	}
+	for k, v := range alter {
+		if v == "" {
+			delete(res, k)
+		} else {
+			res[k] = v
+		}
+	}
+
+	return res
+}
+
+func (s *Server) DropTopic(
+	_ context.Context,
+	request *Ydb_Topic.DropTopicRequest,
+) (*Ydb_Topic.DropTopicResponse, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	t, err := s.topic(request.GetPath())
+	if err != nil {
+		return &Ydb_Topic.DropTopicResponse{Operation: operation(nil, err)}, nil
+	}
+	delete(s.topics, t.path)
+	s.notifyChanged()
+
+	return &Ydb_Topic.DropTopicResponse{Operation: operation(nil, nil)}, nil
+}
+
+func (s *Server) CommitOffset(
+	_ context.Context,
+	request *Ydb_Topic.CommitOffsetRequest,
+) (*Ydb_Topic.CommitOffsetResponse, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	err := func() error {
+		t, err := s.topic(request.GetPath())
+		if err != nil {
+			return err
+		}
+		c, err := t.consumer(request.GetConsumer())
+		if err != nil {
+			return err
+		}
+		p, err := t.partition(request.GetPartitionId())
+		if err != nil {
+			return err
+		}
+		if request.GetOffset() < 0 || request.GetOffset() > p.endOffset() {
+			return newStatusError(Ydb.StatusIds_BAD_REQUEST, "offset %v out of partition range", request.GetOffset())
+		}
+		c.committed[p.id] = request.GetOffset()
+
+		return nil
+	}()
+
+	return &Ydb_Topic.CommitOffsetResponse{Operation: operation(nil, err)}, nil
+}
+
+func schemeEntry(entryPath string) *Ydb_Scheme.Entry {
+	return &Ydb_Scheme.Entry{
+		Name: path.Base(entryPath),
+		Type: Ydb_Scheme.Entry_TOPIC,
+	}
+}
+
+func partitionStats(p *partition) *Ydb_Topic.PartitionStats {
+	stats := &Ydb_Topic.PartitionStats{
+		PartitionOffsets: p.offsets(),
+	}
+	for _, m := range p.messages {
+		stats.StoreSizeBytes += int64(len(m.data.GetData()))
+	}
+	stats.LastWriteTime = timestamp(p.lastWriteTime())
+
+	return stats
+}
+
+// operation creates completed operation with the result or error
+func operation(result proto.Message, err error) *Ydb_Operations.Operation {
+	if err != nil {
+		statusErr := toStatusError(err)
+
+		return &Ydb_Operations.Operation{
+			Ready:  true,
+			Status: statusErr.status,
+			Issues: statusErr.issues(),
+		}
+	}
+
+	res := &Ydb_Operations.Operation{
+		Ready:  true,
+		Status: Ydb.StatusIds_SUCCESS,
+	}
+	if result != nil {
+		res.Result, err = anypb.New(result)
+		if err != nil {
+			return operation(nil, err)
+		}
+	}
+
+	return res
+}
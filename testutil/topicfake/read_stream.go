@@ -0,0 +1,447 @@
+package topicfake
+
+import (
+	"errors"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/ydb-platform/ydb-go-genproto/Ydb_Topic_V1"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Topic"
+)
+
+type readStream struct {
+	id       string
+	consumer string
+	settings []readSettings
+
+	budget        int64
+	lastSessionID int64
+	sessions      map[int64]*readPartitionSession
+	assigned      map[*partition]bool
+}
+
+type readSettings struct {
+	path       string // path of topic from client request
+	topic      *topic
+	partitions map[int64]bool // empty for all partitions
+	readFrom   time.Time
+}
+
+type readPartitionSession struct {
+	id         int64
+	settings   *readSettings
+	partition  *partition
+	consumer   *consumer // nil for read without consumer
+	started    bool
+	readOffset int64
+}
+
+func (s *Server) StreamRead(stream Ydb_Topic_V1.TopicService_StreamReadServer) error {
+	request, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	r, err := s.initReadStream(request.GetInitRequest())
+	if err != nil {
+		return sendReadError(stream, err)
+	}
+	defer s.releaseReadStream(r)
+
+	if err = stream.Send(&Ydb_Topic.StreamReadMessage_FromServer{
+		Status: Ydb.StatusIds_SUCCESS,
+		ServerMessage: &Ydb_Topic.StreamReadMessage_FromServer_InitResponse{
+			InitResponse: &Ydb_Topic.StreamReadMessage_InitResponse{SessionId: r.id},
+		},
+	}); err != nil {
+		return err
+	}
+
+	requests := make(chan *Ydb_Topic.StreamReadMessage_FromClient)
+	var recvErr error
+	go func() {
+		defer close(requests)
+
+		for {
+			request, err := stream.Recv()
+			if err != nil {
+				recvErr = err
+
+				return
+			}
+			select {
+			case requests <- request:
+			case <-stream.Context().Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		s.m.Lock()
+		changed := s.changed
+		responses := append(r.assignPartitions(), r.readResponses()...)
+		s.m.Unlock()
+
+		for _, response := range responses {
+			if err = stream.Send(response); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-changed:
+		case request, ok := <-requests:
+			if !ok {
+				if errors.Is(recvErr, io.EOF) {
+					return nil
+				}
+
+				return recvErr
+			}
+			response, err := s.handleReadRequest(r, request)
+			if err != nil {
+				return sendReadError(stream, err)
+			}
+			if response != nil {
+				if err = stream.Send(response); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+func (s *Server) initReadStream(request *Ydb_Topic.StreamReadMessage_InitRequest) (*readStream, error) {
+	if request == nil {
+		return nil, newStatusError(Ydb.StatusIds_BAD_REQUEST, "first message of read stream must be init request")
+	}
+	if request.GetDirectRead() {
+		return nil, newStatusError(Ydb.StatusIds_UNSUPPORTED, "direct read is not supported")
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	r := &readStream{
+		id:       s.nextID("read"),
+		consumer: request.GetConsumer(),
+		sessions: make(map[int64]*readPartitionSession),
+		assigned: make(map[*partition]bool),
+	}
+	for _, topicSettings := range request.GetTopicsReadSettings() {
+		t, err := s.topic(topicSettings.GetPath())
+		if err != nil {
+			return nil, err
+		}
+		if r.consumer != "" {
+			if _, err = t.consumer(r.consumer); err != nil {
+				return nil, err
+			}
+		}
+
+		settings := readSettings{
+			path:       topicSettings.GetPath(),
+			topic:      t,
+			partitions: make(map[int64]bool),
+		}
+		if topicSettings.GetReadFrom() != nil {
+			settings.readFrom = topicSettings.GetReadFrom().AsTime()
+		}
+		for _, id := range topicSettings.GetPartitionIds() {
+			if _, err = t.partition(id); err != nil {
+				return nil, err
+			}
+			settings.partitions[id] = true
+		}
+		r.settings = append(r.settings, settings)
+	}
+
+	return r, nil
+}
+
+// releaseReadStream releases partitions of the stream for other readers of the consumer
+func (s *Server) releaseReadStream(r *readStream) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	for _, session := range r.sessions {
+		session.release(r)
+	}
+	s.notifyChanged()
+}
+
+func (s *Server) handleReadRequest(
+	r *readStream,
+	request *Ydb_Topic.StreamReadMessage_FromClient,
+) (*Ydb_Topic.StreamReadMessage_FromServer, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	switch m := request.GetClientMessage().(type) {
+	case *Ydb_Topic.StreamReadMessage_FromClient_ReadRequest:
+		r.budget += m.ReadRequest.GetBytesSize()
+
+		return nil, nil
+	case *Ydb_Topic.StreamReadMessage_FromClient_StartPartitionSessionResponse:
+		return nil, r.startPartitionSession(m.StartPartitionSessionResponse)
+	case *Ydb_Topic.StreamReadMessage_FromClient_StopPartitionSessionResponse:
+		if session, ok := r.sessions[m.StopPartitionSessionResponse.GetPartitionSessionId()]; ok {
+			session.release(r)
+			delete(r.sessions, session.id)
+			s.notifyChanged()
+		}
+
+		return nil, nil
+	case *Ydb_Topic.StreamReadMessage_FromClient_CommitOffsetRequest:
+		return r.commit(m.CommitOffsetRequest)
+	case *Ydb_Topic.StreamReadMessage_FromClient_PartitionSessionStatusRequest:
+		return r.partitionSessionStatus(m.PartitionSessionStatusRequest.GetPartitionSessionId())
+	case *Ydb_Topic.StreamReadMessage_FromClient_UpdateTokenRequest:
+		return &Ydb_Topic.StreamReadMessage_FromServer{
+			Status: Ydb.StatusIds_SUCCESS,
+			ServerMessage: &Ydb_Topic.StreamReadMessage_FromServer_UpdateTokenResponse{
+				UpdateTokenResponse: &Ydb_Topic.UpdateTokenResponse{},
+			},
+		}, nil
+	default:
+		return nil, newStatusError(Ydb.StatusIds_BAD_REQUEST, "unexpected message in read stream: %T", m)
+	}
+}
+
+// assignPartitions starts partition sessions for free partitions of the stream topics,
+// must be called with locked s.m
+func (r *readStream) assignPartitions() (res []*Ydb_Topic.StreamReadMessage_FromServer) {
+	for i := range r.settings {
+		settings := &r.settings[i]
+
+		var c *consumer
+		if r.consumer != "" {
+			var err error
+			if c, err = settings.topic.consumer(r.consumer); err != nil {
+				continue
+			}
+		}
+
+		for _, p := range settings.topic.partitions {
+			if r.assigned[p] || (len(settings.partitions) > 0 && !settings.partitions[p.id]) {
+				continue
+			}
+			if c != nil {
+				if c.readers[p.id] != nil {
+					continue
+				}
+				c.readers[p.id] = r
+			}
+
+			r.assigned[p] = true
+			r.lastSessionID++
+			session := &readPartitionSession{
+				id:        r.lastSessionID,
+				settings:  settings,
+				partition: p,
+				consumer:  c,
+			}
+			r.sessions[session.id] = session
+
+			res = append(res, &Ydb_Topic.StreamReadMessage_FromServer{
+				Status: Ydb.StatusIds_SUCCESS,
+				ServerMessage: &Ydb_Topic.StreamReadMessage_FromServer_StartPartitionSessionRequest{
+					StartPartitionSessionRequest: &Ydb_Topic.StreamReadMessage_StartPartitionSessionRequest{
+						PartitionSession: &Ydb_Topic.StreamReadMessage_PartitionSession{
+							PartitionSessionId: session.id,
+							Path:               settings.path,
+							PartitionId:        p.id,
+						},
+						CommittedOffset:  session.committedOffset(),
+						PartitionOffsets: p.offsets(),
+					},
+				},
+			})
+		}
+	}
+
+	return res
+}
+
+func (r *readStream) startPartitionSession(response *Ydb_Topic.StreamReadMessage_StartPartitionSessionResponse) error {
+	session, err := r.session(response.GetPartitionSessionId())
+	if err != nil {
+		return err
+	}
+
+	session.started = true
+	session.readOffset = session.committedOffset()
+	if response.ReadOffset != nil {
+		session.readOffset = response.GetReadOffset()
+	}
+	if response.CommitOffset != nil && session.consumer != nil {
+		session.consumer.committed[session.partition.id] = response.GetCommitOffset()
+	}
+	if readFrom := session.settings.readFrom; !readFrom.IsZero() {
+		messages := session.partition.messages
+		for session.readOffset < int64(len(messages)) && messages[session.readOffset].writtenAt.Before(readFrom) {
+			session.readOffset++
+		}
+	}
+
+	return nil
+}
+
+// readResponses returns messages from started partition sessions within read budget of the client,
+// must be called with locked s.m
+func (r *readStream) readResponses() []*Ydb_Topic.StreamReadMessage_FromServer {
+	ids := make([]int64, 0, len(r.sessions))
+	for id := range r.sessions {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	response := &Ydb_Topic.StreamReadMessage_ReadResponse{}
+	for _, id := range ids {
+		session := r.sessions[id]
+		if !session.started {
+			continue
+		}
+
+		partitionData := &Ydb_Topic.StreamReadMessage_ReadResponse_PartitionData{PartitionSessionId: id}
+		var batch *Ydb_Topic.StreamReadMessage_ReadResponse_Batch
+		messages := session.partition.messages
+		for r.budget > 0 && session.readOffset < int64(len(messages)) {
+			m := messages[session.readOffset]
+			if batch == nil || batch.GetProducerId() != m.producerID || batch.GetCodec() != m.codec {
+				batch = &Ydb_Topic.StreamReadMessage_ReadResponse_Batch{
+					ProducerId:       m.producerID,
+					WriteSessionMeta: m.writeSessionMeta,
+					Codec:            m.codec,
+					WrittenAt:        timestamp(m.writtenAt),
+				}
+				partitionData.Batches = append(partitionData.Batches, batch)
+			}
+			batch.MessageData = append(batch.MessageData, m.data)
+
+			size := int64(len(m.data.GetData()))
+			r.budget -= size
+			response.BytesSize += size
+			session.readOffset++
+		}
+		if len(partitionData.GetBatches()) > 0 {
+			response.PartitionData = append(response.PartitionData, partitionData)
+		}
+	}
+
+	if len(response.GetPartitionData()) == 0 {
+		return nil
+	}
+
+	return []*Ydb_Topic.StreamReadMessage_FromServer{{
+		Status:        Ydb.StatusIds_SUCCESS,
+		ServerMessage: &Ydb_Topic.StreamReadMessage_FromServer_ReadResponse{ReadResponse: response},
+	}}
+}
+
+func (r *readStream) commit(request *Ydb_Topic.StreamReadMessage_CommitOffsetRequest) (*Ydb_Topic.StreamReadMessage_FromServer, error) {
+	response := &Ydb_Topic.StreamReadMessage_CommitOffsetResponse{}
+	for _, commit := range request.GetCommitOffsets() {
+		session, err := r.session(commit.GetPartitionSessionId())
+		if err != nil {
+			return nil, err
+		}
+		if session.consumer == nil {
+			return nil, newStatusError(Ydb.StatusIds_BAD_REQUEST, "commit is not allowed for read without consumer")
+		}
+
+		offsets := append([]*Ydb_Topic.OffsetsRange{}, commit.GetOffsets()...)
+		sort.Slice(offsets, func(i, j int) bool { return offsets[i].GetStart() < offsets[j].GetStart() })
+
+		committed := session.committedOffset()
+		for _, offset := range offsets {
+			if offset.GetStart() <= committed && offset.GetEnd() > committed {
+				committed = offset.GetEnd()
+			}
+		}
+		if committed > session.partition.endOffset() {
+			return nil, newStatusError(Ydb.StatusIds_BAD_REQUEST, "commit offset %v out of partition range", committed)
+		}
+		session.consumer.committed[session.partition.id] = committed
+
+		response.PartitionsCommittedOffsets = append(response.PartitionsCommittedOffsets,
+			&Ydb_Topic.StreamReadMessage_CommitOffsetResponse_PartitionCommittedOffset{
+				PartitionSessionId: session.id,
+				CommittedOffset:    committed,
+			},
+		)
+	}
+
+	return &Ydb_Topic.StreamReadMessage_FromServer{
+		Status:        Ydb.StatusIds_SUCCESS,
+		ServerMessage: &Ydb_Topic.StreamReadMessage_FromServer_CommitOffsetResponse{CommitOffsetResponse: response},
+	}, nil
+}
+
+func (r *readStream) partitionSessionStatus(id int64) (*Ydb_Topic.StreamReadMessage_FromServer, error) {
+	session, err := r.session(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Ydb_Topic.StreamReadMessage_FromServer{
+		Status: Ydb.StatusIds_SUCCESS,
+		ServerMessage: &Ydb_Topic.StreamReadMessage_FromServer_PartitionSessionStatusResponse{
+			PartitionSessionStatusResponse: &Ydb_Topic.StreamReadMessage_PartitionSessionStatusResponse{
+				PartitionSessionId:     id,
+				PartitionOffsets:       session.partition.offsets(),
+				CommittedOffset:        session.committedOffset(),
+				WriteTimeHighWatermark: timestamp(session.partition.lastWriteTime()),
+			},
+		},
+	}, nil
+}
+
+func (r *readStream) session(id int64) (*readPartitionSession, error) {
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, newStatusError(Ydb.StatusIds_BAD_REQUEST, "unknown partition session: %v", id)
+	}
+
+	return session, nil
+}
+
+// lastReadOffset returns offset of next message for read of the partition by the stream
+func (r *readStream) lastReadOffset(p *partition) int64 {
+	for _, session := range r.sessions {
+		if session.partition == p {
+			return session.readOffset
+		}
+	}
+
+	return 0
+}
+
+func (session *readPartitionSession) committedOffset() int64 {
+	if session.consumer == nil {
+		return 0
+	}
+
+	return session.consumer.committed[session.partition.id]
+}
+
+func (session *readPartitionSession) release(r *readStream) {
+	delete(r.assigned, session.partition)
+	if session.consumer != nil && session.consumer.readers[session.partition.id] == r {
+		delete(session.consumer.readers, session.partition.id)
+	}
+}
+
+func sendReadError(stream Ydb_Topic_V1.TopicService_StreamReadServer, err error) error {
+	statusErr := toStatusError(err)
+
+	return stream.Send(&Ydb_Topic.StreamReadMessage_FromServer{
+		Status: statusErr.status,
+		Issues: statusErr.issues(),
+	})
+}
@@ -0,0 +1,252 @@
+// Package topicfake is in-memory implementation of YDB topic service for unit tests.
+// Server runs Ydb_Topic_V1 grpc service over in-process connection and supports control plane of topics
+// (create, describe, alter, drop), write streams with acks and deduplication by producer seqno,
+// read streams with partition sessions and commit of offsets by consumers.
+//
+//	server := topicfake.New()
+//	defer server.Close()
+//
+//	db, err := server.Open(ctx)
+//	...
+//	err = db.Topic().Create(ctx, "topic", topicoptions.CreateWithConsumer(topictypes.Consumer{Name: "consumer"}))
+//
+// Server is deterministic: partition of writer is chosen by hash of producer id (or message group id),
+// partitions of a consumer are read by first read stream, which is interested in the partition,
+// and they are released only on close of the stream. Transactions and direct read are not supported.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+package topicfake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ydb-platform/ydb-go-genproto/Ydb_Topic_V1"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Issue"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Topic"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3"
+	"github.com/ydb-platform/ydb-go-sdk/v3/balancers"
+	"github.com/ydb-platform/ydb-go-sdk/v3/config"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/empty"
+)
+
+const (
+	// Database is name of database of the server, relative paths of topics are resolved from it
+	Database = "/local"
+
+	// DSN is connection string for the server, it works only with Options of the server
+	DSN = "grpc://topicfake:2135" + Database
+
+	bufferSize = 1024 * 1024
+)
+
+// Server is in-memory topic service
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type Server struct {
+	Ydb_Topic_V1.UnimplementedTopicServiceServer
+
+	listener   *bufconn.Listener
+	grpcServer *grpc.Server
+	closeOnce  sync.Once
+
+	lastID atomic.Int64
+
+	m       sync.Mutex
+	topics  map[string]*topic
+	changed empty.Chan // closed and replaced on every change of messages or partitions
+}
+
+// New starts the server
+func New() *Server {
+	s := &Server{
+		listener:   bufconn.Listen(bufferSize),
+		grpcServer: grpc.NewServer(),
+		topics:     make(map[string]*topic),
+		changed:    make(empty.Chan),
+	}
+	Ydb_Topic_V1.RegisterTopicServiceServer(s.grpcServer, s)
+
+	go func() {
+		_ = s.grpcServer.Serve(s.listener)
+	}()
+
+	return s
+}
+
+// Options returns options for connect to the server by ydb.Open with DSN
+func (s *Server) Options() []ydb.Option {
+	return []ydb.Option{
+		ydb.WithBalancer(balancers.SingleConn()),
+		ydb.With(config.WithGrpcOptions(grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return s.listener.DialContext(ctx)
+		}))),
+	}
+}
+
+// Open opens driver, connected to the server
+func (s *Server) Open(ctx context.Context, opts ...ydb.Option) (*ydb.Driver, error) {
+	return ydb.Open(ctx, DSN, append(s.Options(), opts...)...)
+}
+
+// Close stops the server, active streams are interrupted
+func (s *Server) Close() {
+	s.closeOnce.Do(func() {
+		s.grpcServer.Stop()
+		_ = s.listener.Close()
+	})
+}
+
+// notifyChanged wakes read streams, must be called with locked s.m
+func (s *Server) notifyChanged() {
+	close(s.changed)
+	s.changed = make(empty.Chan)
+}
+
+func (s *Server) nextID(prefix string) string {
+	return fmt.Sprintf("%s-%d", prefix, s.lastID.Add(1))
+}
+
+// topic returns topic by path, must be called with locked s.m
+func (s *Server) topic(topicPath string) (*topic, error) {
+	t, ok := s.topics[fullPath(topicPath)]
+	if !ok {
+		return nil, newStatusError(Ydb.StatusIds_SCHEME_ERROR, "topic not found: %v", topicPath)
+	}
+
+	return t, nil
+}
+
+func fullPath(topicPath string) string {
+	if !strings.HasPrefix(topicPath, "/") {
+		topicPath = Database + "/" + topicPath
+	}
+
+	return path.Clean(topicPath)
+}
+
+type topic struct {
+	path        string
+	description *Ydb_Topic.DescribeTopicResult // settings of the topic without partitions and consumers
+	partitions  []*partition
+	consumers   []*consumer
+}
+
+func (t *topic) consumer(name string) (*consumer, error) {
+	for _, c := range t.consumers {
+		if c.description.GetName() == name {
+			return c, nil
+		}
+	}
+
+	return nil, newStatusError(Ydb.StatusIds_SCHEME_ERROR, "consumer %q not found in topic %v", name, t.path)
+}
+
+func (t *topic) partition(id int64) (*partition, error) {
+	if id < 0 || id >= int64(len(t.partitions)) {
+		return nil, newStatusError(Ydb.StatusIds_BAD_REQUEST, "partition %v not found in topic %v", id, t.path)
+	}
+
+	return t.partitions[id], nil
+}
+
+func (t *topic) addPartitions(count int64) {
+	for int64(len(t.partitions)) < count {
+		t.partitions = append(t.partitions, &partition{
+			id:        int64(len(t.partitions)),
+			producers: make(map[string]int64),
+		})
+	}
+}
+
+type partition struct {
+	id        int64
+	messages  []*message
+	producers map[string]int64 // max written seqno by producer id
+}
+
+func (p *partition) endOffset() int64 {
+	return int64(len(p.messages))
+}
+
+func (p *partition) offsets() *Ydb_Topic.OffsetsRange {
+	return &Ydb_Topic.OffsetsRange{Start: 0, End: p.endOffset()}
+}
+
+func (p *partition) lastWriteTime() time.Time {
+	if len(p.messages) == 0 {
+		return time.Time{}
+	}
+
+	return p.messages[len(p.messages)-1].writtenAt
+}
+
+type message struct {
+	producerID       string
+	writeSessionMeta map[string]string
+	codec            int32
+	writtenAt        time.Time
+
+	data *Ydb_Topic.StreamReadMessage_ReadResponse_MessageData
+}
+
+type consumer struct {
+	description *Ydb_Topic.Consumer
+	committed   map[int64]int64       // committed offsets by partition id
+	readers     map[int64]*readStream // read streams by partition id
+}
+
+func newConsumer(description *Ydb_Topic.Consumer) *consumer {
+	return &consumer{
+		description: description,
+		committed:   make(map[int64]int64),
+		readers:     make(map[int64]*readStream),
+	}
+}
+
+type statusError struct {
+	status  Ydb.StatusIds_StatusCode
+	message string
+}
+
+func newStatusError(status Ydb.StatusIds_StatusCode, format string, args ...interface{}) *statusError {
+	return &statusError{status: status, message: fmt.Sprintf(format, args...)}
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%v: %v", e.status, e.message)
+}
+
+func (e *statusError) issues() []*Ydb_Issue.IssueMessage {
+	return []*Ydb_Issue.IssueMessage{{Message: e.message}}
+}
+
+func toStatusError(err error) *statusError {
+	var statusErr *statusError
+	if !errors.As(err, &statusErr) {
+		statusErr = newStatusError(Ydb.StatusIds_INTERNAL_ERROR, "%v", err)
+	}
+
+	return statusErr
+}
+
+// timestamp returns nil for zero time
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(t)
+}
@@ -0,0 +1,140 @@
+package topicfake
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3"
+	"github.com/ydb-platform/ydb-go-sdk/v3/pkg/xtest"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicoptions"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topictypes"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicwriter"
+)
+
+func openTestDriver(t *testing.T) *ydb.Driver {
+	t.Helper()
+
+	server := New()
+	t.Cleanup(server.Close)
+
+	ctx := xtest.Context(t)
+	db, err := server.Open(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close(ctx)
+	})
+
+	require.NoError(t, db.Topic().Create(ctx, "topic",
+		topicoptions.CreateWithMinActivePartitions(2),
+		topicoptions.CreateWithConsumer(topictypes.Consumer{Name: "consumer"}),
+	))
+
+	return db
+}
+
+func TestServer(t *testing.T) {
+	t.Run("WriteReadCommit", func(t *testing.T) {
+		ctx := xtest.Context(t)
+		db := openTestDriver(t)
+
+		writer, err := db.Topic().StartWriter("topic",
+			topicoptions.WithWriterProducerID("producer"),
+			topicoptions.WithWriterWaitServerAck(true),
+		)
+		require.NoError(t, err)
+		require.NoError(t, writer.Write(ctx,
+			topicwriter.Message{Data: strings.NewReader("1"), Metadata: map[string][]byte{"key": []byte("value")}},
+			topicwriter.Message{Data: strings.NewReader("2")},
+			topicwriter.Message{Data: strings.NewReader("3")},
+		))
+		require.NoError(t, writer.Close(ctx))
+
+		reader, err := db.Topic().StartReader("consumer", topicoptions.ReadTopic("topic"),
+			topicoptions.WithReaderCommitMode(topicoptions.CommitModeSync),
+		)
+		require.NoError(t, err)
+
+		for i, expected := range []string{"1", "2", "3"} {
+			mess, err := reader.ReadMessage(ctx)
+			require.NoError(t, err)
+			require.Equal(t, "topic", mess.Topic())
+			require.Equal(t, int64(i), mess.Offset)
+			require.Equal(t, int64(i+1), mess.SeqNo)
+			require.Equal(t, "producer", mess.ProducerID)
+			if i == 0 {
+				require.Equal(t, []byte("value"), mess.Metadata["key"])
+			}
+			data, err := io.ReadAll(mess)
+			require.NoError(t, err)
+			require.Equal(t, expected, string(data))
+			require.NoError(t, reader.Commit(ctx, mess))
+		}
+		require.NoError(t, reader.Close(ctx))
+
+		description, err := db.Topic().DescribeTopicConsumer(ctx, "topic", "consumer",
+			topicoptions.IncludeConsumerStats(),
+		)
+		require.NoError(t, err)
+
+		var committed int64
+		for _, p := range description.Partitions {
+			committed += p.PartitionConsumerStats.CommittedOffset
+		}
+		require.Equal(t, int64(3), committed)
+	})
+	t.Run("Deduplication", func(t *testing.T) {
+		ctx := xtest.Context(t)
+		db := openTestDriver(t)
+
+		for i := 0; i < 2; i++ {
+			writer, err := db.Topic().StartWriter("topic",
+				topicoptions.WithWriterProducerID("producer"),
+				topicoptions.WithWriterSetAutoSeqNo(false),
+				topicoptions.WithWriterWaitServerAck(true),
+			)
+			require.NoError(t, err)
+			require.NoError(t, writer.Write(ctx,
+				topicwriter.Message{SeqNo: 1, Data: strings.NewReader("1")},
+				topicwriter.Message{SeqNo: 2, Data: strings.NewReader("2")},
+			))
+			require.NoError(t, writer.Close(ctx))
+		}
+
+		description, err := db.Topic().Describe(ctx, "topic", topicoptions.IncludePartitionStats())
+		require.NoError(t, err)
+
+		var messagesCount int64
+		for _, p := range description.Partitions {
+			messagesCount += p.PartitionStats.PartitionsOffset.End
+		}
+		require.Equal(t, int64(2), messagesCount)
+	})
+	t.Run("ControlPlane", func(t *testing.T) {
+		ctx := xtest.Context(t)
+		db := openTestDriver(t)
+
+		require.Error(t, db.Topic().Create(ctx, "topic"))
+		require.NoError(t, db.Topic().Alter(ctx, "/local/topic",
+			topicoptions.AlterWithMinActivePartitions(3),
+			topicoptions.AlterWithAddConsumers(topictypes.Consumer{Name: "second"}),
+			topicoptions.AlterWithDropConsumers("consumer"),
+		))
+
+		description, err := db.Topic().Describe(ctx, "topic")
+		require.NoError(t, err)
+		require.Equal(t, "topic", description.Path)
+		require.Len(t, description.Partitions, 3)
+		require.Len(t, description.Consumers, 1)
+		require.Equal(t, "second", description.Consumers[0].Name)
+
+		_, err = db.Topic().DescribeTopicConsumer(ctx, "topic", "consumer")
+		require.Error(t, err)
+
+		require.NoError(t, db.Topic().Drop(ctx, "topic"))
+		_, err = db.Topic().Describe(ctx, "topic")
+		require.Error(t, err)
+	})
+}
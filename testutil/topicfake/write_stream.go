@@ -0,0 +1,201 @@
+package topicfake
+
+import (
+	"errors"
+	"hash/fnv"
+	"io"
+	"time"
+
+	"github.com/ydb-platform/ydb-go-genproto/Ydb_Topic_V1"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Topic"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type writeStream struct {
+	id         string
+	producerID string
+	meta       map[string]string
+	topic      *topic
+	partition  *partition
+}
+
+func (s *Server) StreamWrite(stream Ydb_Topic_V1.TopicService_StreamWriteServer) error {
+	request, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	w, initResponse, err := s.initWriteStream(request.GetInitRequest())
+	if err != nil {
+		return sendWriteError(stream, err)
+	}
+	if err = stream.Send(&Ydb_Topic.StreamWriteMessage_FromServer{
+		Status:        Ydb.StatusIds_SUCCESS,
+		ServerMessage: &Ydb_Topic.StreamWriteMessage_FromServer_InitResponse{InitResponse: initResponse},
+	}); err != nil {
+		return err
+	}
+
+	for {
+		request, err = stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return err
+		}
+
+		var response *Ydb_Topic.StreamWriteMessage_FromServer
+		switch m := request.GetClientMessage().(type) {
+		case *Ydb_Topic.StreamWriteMessage_FromClient_WriteRequest:
+			writeResponse, err := s.write(w, m.WriteRequest)
+			if err != nil {
+				return sendWriteError(stream, err)
+			}
+			response = &Ydb_Topic.StreamWriteMessage_FromServer{
+				ServerMessage: &Ydb_Topic.StreamWriteMessage_FromServer_WriteResponse{WriteResponse: writeResponse},
+			}
+		case *Ydb_Topic.StreamWriteMessage_FromClient_UpdateTokenRequest:
+			response = &Ydb_Topic.StreamWriteMessage_FromServer{
+				ServerMessage: &Ydb_Topic.StreamWriteMessage_FromServer_UpdateTokenResponse{
+					UpdateTokenResponse: &Ydb_Topic.UpdateTokenResponse{},
+				},
+			}
+		default:
+			return sendWriteError(stream, newStatusError(
+				Ydb.StatusIds_BAD_REQUEST, "unexpected message in write stream: %T", m,
+			))
+		}
+		response.Status = Ydb.StatusIds_SUCCESS
+		if err = stream.Send(response); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) initWriteStream(
+	request *Ydb_Topic.StreamWriteMessage_InitRequest,
+) (*writeStream, *Ydb_Topic.StreamWriteMessage_InitResponse, error) {
+	if request == nil {
+		return nil, nil, newStatusError(Ydb.StatusIds_BAD_REQUEST, "first message of write stream must be init request")
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	t, err := s.topic(request.GetPath())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var p *partition
+	switch partitioning := request.GetPartitioning().(type) {
+	case *Ydb_Topic.StreamWriteMessage_InitRequest_PartitionId:
+		p, err = t.partition(partitioning.PartitionId)
+	case *Ydb_Topic.StreamWriteMessage_InitRequest_PartitionWithGeneration:
+		p, err = t.partition(partitioning.PartitionWithGeneration.GetPartitionId())
+	case *Ydb_Topic.StreamWriteMessage_InitRequest_MessageGroupId:
+		p = t.partitions[hashPartition(partitioning.MessageGroupId, len(t.partitions))]
+	default:
+		p = t.partitions[hashPartition(request.GetProducerId(), len(t.partitions))]
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	w := &writeStream{
+		id:         s.nextID("write"),
+		producerID: request.GetProducerId(),
+		meta:       request.GetWriteSessionMeta(),
+		topic:      t,
+		partition:  p,
+	}
+
+	return w, &Ydb_Topic.StreamWriteMessage_InitResponse{
+		LastSeqNo:       p.producers[w.producerID],
+		SessionId:       w.id,
+		PartitionId:     p.id,
+		SupportedCodecs: t.description.GetSupportedCodecs(),
+	}, nil
+}
+
+func (s *Server) write(
+	w *writeStream,
+	request *Ydb_Topic.StreamWriteMessage_WriteRequest,
+) (*Ydb_Topic.StreamWriteMessage_WriteResponse, error) {
+	if request.GetTx() != nil {
+		return nil, newStatusError(Ydb.StatusIds_UNSUPPORTED, "transactions are not supported")
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	p := w.partition
+	now := time.Now()
+	response := &Ydb_Topic.StreamWriteMessage_WriteResponse{
+		PartitionId: p.id,
+		WriteStatistics: &Ydb_Topic.StreamWriteMessage_WriteResponse_WriteStatistics{
+			PersistingTime:         durationpb.New(0),
+			MinQueueWaitTime:       durationpb.New(0),
+			MaxQueueWaitTime:       durationpb.New(0),
+			PartitionQuotaWaitTime: durationpb.New(0),
+			TopicQuotaWaitTime:     durationpb.New(0),
+		},
+	}
+	for _, data := range request.GetMessages() {
+		ack := &Ydb_Topic.StreamWriteMessage_WriteResponse_WriteAck{SeqNo: data.GetSeqNo()}
+		response.Acks = append(response.Acks, ack)
+
+		if w.producerID != "" && data.GetSeqNo() <= p.producers[w.producerID] {
+			ack.MessageWriteStatus = &Ydb_Topic.StreamWriteMessage_WriteResponse_WriteAck_Skipped_{
+				Skipped: &Ydb_Topic.StreamWriteMessage_WriteResponse_WriteAck_Skipped{
+					Reason: Ydb_Topic.StreamWriteMessage_WriteResponse_WriteAck_Skipped_REASON_ALREADY_WRITTEN,
+				},
+			}
+
+			continue
+		}
+
+		offset := p.endOffset()
+		p.producers[w.producerID] = data.GetSeqNo()
+		p.messages = append(p.messages, &message{
+			producerID:       w.producerID,
+			writeSessionMeta: w.meta,
+			codec:            request.GetCodec(),
+			writtenAt:        now,
+			data: &Ydb_Topic.StreamReadMessage_ReadResponse_MessageData{
+				Offset:           offset,
+				SeqNo:            data.GetSeqNo(),
+				CreatedAt:        data.GetCreatedAt(),
+				Data:             data.GetData(),
+				UncompressedSize: data.GetUncompressedSize(),
+				MessageGroupId:   data.GetMessageGroupId(),
+				MetadataItems:    data.GetMetadataItems(),
+			},
+		})
+		ack.MessageWriteStatus = &Ydb_Topic.StreamWriteMessage_WriteResponse_WriteAck_Written_{
+			Written: &Ydb_Topic.StreamWriteMessage_WriteResponse_WriteAck_Written{Offset: offset},
+		}
+	}
+	s.notifyChanged()
+
+	return response, nil
+}
+
+func sendWriteError(stream Ydb_Topic_V1.TopicService_StreamWriteServer, err error) error {
+	statusErr := toStatusError(err)
+
+	return stream.Send(&Ydb_Topic.StreamWriteMessage_FromServer{
+		Status: statusErr.status,
+		Issues: statusErr.issues(),
+	})
+}
+
+func hashPartition(key string, partitionsCount int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+
+	return int(h.Sum32() % uint32(partitionsCount))
+}
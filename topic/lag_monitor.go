@@ -0,0 +1,415 @@
+package topic
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/background"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicoptions"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topictypes"
+)
+
+const defaultLagMonitorInterval = 30 * time.Second
+
+var (
+	errLagMonitorClosed      = xerrors.Wrap(errors.New("ydb: lag monitor closed"))
+	errLagMonitorNoConsumers = xerrors.Wrap(errors.New("ydb: lag monitor has no consumers for monitoring"))
+)
+
+// LagMonitorClient is part of Client, needed for LagMonitor
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type LagMonitorClient interface {
+	DescribeTopicConsumer(
+		ctx context.Context, path string, consumer string, opts ...topicoptions.DescribeConsumerOption,
+	) (topictypes.TopicConsumerDescription, error)
+}
+
+// LagMonitorConsumer is pair of topic and consumer for monitoring
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type LagMonitorConsumer struct {
+	Topic    string
+	Consumer string
+}
+
+// PartitionLag is lag of consumer for the partition
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type PartitionLag struct {
+	PartitionID     int64
+	Active          bool
+	CommittedOffset int64
+	EndOffset       int64
+
+	// MessageLag is count of written, but not committed messages
+	MessageLag int64
+
+	// TimeLag is estimation of age of oldest not committed message, it is lower bound of the age:
+	// not committed messages were written before last write to the partition, and messages before
+	// last read message of the consumer were written before the read message.
+	// It is zero if all messages committed.
+	TimeLag time.Duration
+}
+
+// ConsumerLag is lag of consumer for all partitions of the topic
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type ConsumerLag struct {
+	Topic      string
+	Consumer   string
+	CheckedAt  time.Time
+	Partitions []PartitionLag
+
+	// MessageLag is sum of message lags of partitions
+	MessageLag int64
+
+	// TimeLag is max of time lags of partitions
+	TimeLag time.Duration
+}
+
+// LagAlert is state change of alert for the partition lag
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type LagAlert struct {
+	Topic     string
+	Consumer  string
+	Partition PartitionLag
+
+	// Firing is true when lag exceeds the threshold and false when lag returns under the threshold
+	Firing bool
+}
+
+// LagMonitorOption set settings for LagMonitor
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type LagMonitorOption func(cfg *lagMonitorConfig)
+
+type lagMonitorConfig struct {
+	interval            time.Duration
+	messageLagThreshold int64
+	timeLagThreshold    time.Duration
+	onLag               []func(lag ConsumerLag)
+	onAlert             []func(alert LagAlert)
+	onError             []func(consumer LagMonitorConsumer, err error)
+}
+
+// WithLagMonitorInterval set interval between polls of consumers. Default is 30 seconds.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithLagMonitorInterval(interval time.Duration) LagMonitorOption {
+	return func(cfg *lagMonitorConfig) {
+		cfg.interval = interval
+	}
+}
+
+// WithLagMonitorMessageLagThreshold enable alerts when message lag of a partition greater than threshold
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithLagMonitorMessageLagThreshold(threshold int64) LagMonitorOption {
+	return func(cfg *lagMonitorConfig) {
+		cfg.messageLagThreshold = threshold
+	}
+}
+
+// WithLagMonitorTimeLagThreshold enable alerts when time lag of a partition greater than threshold
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithLagMonitorTimeLagThreshold(threshold time.Duration) LagMonitorOption {
+	return func(cfg *lagMonitorConfig) {
+		cfg.timeLagThreshold = threshold
+	}
+}
+
+// WithLagMonitorOnLag add callback, called after every poll of consumer
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithLagMonitorOnLag(f func(lag ConsumerLag)) LagMonitorOption {
+	return func(cfg *lagMonitorConfig) {
+		cfg.onLag = append(cfg.onLag, f)
+	}
+}
+
+// WithLagMonitorOnAlert add callback, called when partition lag exceeds threshold and when lag returns
+// under the threshold
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithLagMonitorOnAlert(f func(alert LagAlert)) LagMonitorOption {
+	return func(cfg *lagMonitorConfig) {
+		cfg.onAlert = append(cfg.onAlert, f)
+	}
+}
+
+// WithLagMonitorOnError add callback, called on failed poll of consumer
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithLagMonitorOnError(f func(consumer LagMonitorConsumer, err error)) LagMonitorOption {
+	return func(cfg *lagMonitorConfig) {
+		cfg.onError = append(cfg.onError, f)
+	}
+}
+
+// MergeLagMonitorOptions concatenates provided options to one cumulative value.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func MergeLagMonitorOptions(opts ...LagMonitorOption) LagMonitorOption {
+	return func(cfg *lagMonitorConfig) {
+		for _, opt := range opts {
+			if opt != nil {
+				opt(cfg)
+			}
+		}
+	}
+}
+
+type lagAlertKey struct {
+	topic       string
+	consumer    string
+	partitionID int64
+}
+
+// LagMonitor polls consumers stats on interval and computes lag of partitions
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type LagMonitor struct {
+	client    LagMonitorClient
+	consumers []LagMonitorConsumer
+	cfg       lagMonitorConfig
+	now       func() time.Time
+
+	background *background.Worker
+
+	m      sync.Mutex
+	last   map[LagMonitorConsumer]ConsumerLag
+	alerts map[lagAlertKey]bool
+}
+
+// NewLagMonitor create lag monitor and start poll consumers in background.
+// Call Close for stop the monitor.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func NewLagMonitor(
+	client LagMonitorClient,
+	consumers []LagMonitorConsumer,
+	opts ...LagMonitorOption,
+) (*LagMonitor, error) {
+	if len(consumers) == 0 {
+		return nil, xerrors.WithStackTrace(errLagMonitorNoConsumers)
+	}
+
+	m := newLagMonitor(client, consumers, opts...)
+	m.background.Start("poll consumers", m.pollLoop)
+
+	return m, nil
+}
+
+func newLagMonitor(client LagMonitorClient, consumers []LagMonitorConsumer, opts ...LagMonitorOption) *LagMonitor {
+	cfg := lagMonitorConfig{
+		interval: defaultLagMonitorInterval,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	if cfg.interval <= 0 {
+		cfg.interval = defaultLagMonitorInterval
+	}
+
+	return &LagMonitor{
+		client:     client,
+		consumers:  append([]LagMonitorConsumer(nil), consumers...),
+		cfg:        cfg,
+		now:        time.Now,
+		background: background.NewWorker(context.Background(), "topic lag monitor"),
+		last:       make(map[LagMonitorConsumer]ConsumerLag),
+		alerts:     make(map[lagAlertKey]bool),
+	}
+}
+
+// Check polls all consumers immediately and returns their lags
+func (m *LagMonitor) Check(ctx context.Context) ([]ConsumerLag, error) {
+	if err := m.background.CloseReason(); err != nil {
+		return nil, xerrors.WithStackTrace(errLagMonitorClosed)
+	}
+
+	var (
+		res  = make([]ConsumerLag, 0, len(m.consumers))
+		errs []error
+	)
+	for _, consumer := range m.consumers {
+		lag, err := m.check(ctx, consumer)
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+		res = append(res, lag)
+	}
+
+	return res, errors.Join(errs...)
+}
+
+// Lag returns result of last poll of the consumer
+func (m *LagMonitor) Lag(topic, consumer string) (_ ConsumerLag, ok bool) {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	lag, ok := m.last[LagMonitorConsumer{Topic: topic, Consumer: consumer}]
+
+	return lag, ok
+}
+
+// Close stops the monitor
+func (m *LagMonitor) Close(ctx context.Context) error {
+	return m.background.Close(ctx, errLagMonitorClosed)
+}
+
+func (m *LagMonitor) pollLoop(ctx context.Context) {
+	ticker := time.NewTicker(m.cfg.interval)
+	defer ticker.Stop()
+
+	for {
+		_, _ = m.Check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *LagMonitor) check(ctx context.Context, consumer LagMonitorConsumer) (ConsumerLag, error) {
+	description, err := m.client.DescribeTopicConsumer(
+		ctx, consumer.Topic, consumer.Consumer, topicoptions.IncludeConsumerStats(),
+	)
+	if err != nil {
+		for _, f := range m.cfg.onError {
+			f(consumer, err)
+		}
+
+		return ConsumerLag{}, xerrors.WithStackTrace(err)
+	}
+
+	lag := computeConsumerLag(consumer, &description, m.now())
+
+	m.m.Lock()
+	m.last[consumer] = lag
+	alerts := m.updateAlerts(&lag)
+	m.m.Unlock()
+
+	for _, f := range m.cfg.onLag {
+		f(lag)
+	}
+	for _, alert := range alerts {
+		for _, f := range m.cfg.onAlert {
+			f(alert)
+		}
+	}
+
+	return lag, nil
+}
+
+func (m *LagMonitor) updateAlerts(lag *ConsumerLag) (alerts []LagAlert) {
+	if m.cfg.messageLagThreshold <= 0 && m.cfg.timeLagThreshold <= 0 {
+		return nil
+	}
+
+	partitions := make(map[int64]bool, len(lag.Partitions))
+	for _, p := range lag.Partitions {
+		partitions[p.PartitionID] = true
+		key := lagAlertKey{topic: lag.Topic, consumer: lag.Consumer, partitionID: p.PartitionID}
+		firing := (m.cfg.messageLagThreshold > 0 && p.MessageLag > m.cfg.messageLagThreshold) ||
+			(m.cfg.timeLagThreshold > 0 && p.TimeLag > m.cfg.timeLagThreshold)
+		if firing == m.alerts[key] {
+			continue
+		}
+
+		if firing {
+			m.alerts[key] = true
+		} else {
+			delete(m.alerts, key)
+		}
+		alerts = append(alerts, LagAlert{
+			Topic:     lag.Topic,
+			Consumer:  lag.Consumer,
+			Partition: p,
+			Firing:    firing,
+		})
+	}
+
+	// partitions disappeared from the topic (for example after merge) have no lag
+	for key := range m.alerts {
+		if key.topic != lag.Topic || key.consumer != lag.Consumer || partitions[key.partitionID] {
+			continue
+		}
+		delete(m.alerts, key)
+		alerts = append(alerts, LagAlert{
+			Topic:     lag.Topic,
+			Consumer:  lag.Consumer,
+			Partition: PartitionLag{PartitionID: key.partitionID},
+			Firing:    false,
+		})
+	}
+
+	return alerts
+}
+
+func computeConsumerLag(
+	consumer LagMonitorConsumer,
+	description *topictypes.TopicConsumerDescription,
+	now time.Time,
+) ConsumerLag {
+	res := ConsumerLag{
+		Topic:      consumer.Topic,
+		Consumer:   consumer.Consumer,
+		CheckedAt:  now,
+		Partitions: make([]PartitionLag, len(description.Partitions)),
+	}
+
+	for i := range description.Partitions {
+		p := &description.Partitions[i]
+		partitionLag := PartitionLag{
+			PartitionID:     p.PartitionID,
+			Active:          p.Active,
+			CommittedOffset: p.PartitionConsumerStats.CommittedOffset,
+			EndOffset:       p.PartitionStats.PartitionsOffset.End,
+		}
+		if partitionLag.EndOffset > partitionLag.CommittedOffset {
+			partitionLag.MessageLag = partitionLag.EndOffset - partitionLag.CommittedOffset
+			partitionLag.TimeLag = partitionTimeLag(p, now)
+		}
+
+		res.Partitions[i] = partitionLag
+		res.MessageLag += partitionLag.MessageLag
+		if partitionLag.TimeLag > res.TimeLag {
+			res.TimeLag = partitionLag.TimeLag
+		}
+	}
+
+	return res
+}
+
+// partitionTimeLag estimates age of oldest not committed message. Every not committed message written
+// not later than last write to the partition. If the consumer read messages after committed offset,
+// not committed messages written not later than last read message, which was written at
+// LastReadTime - MaxWriteTimeLag of the consumer.
+func partitionTimeLag(p *topictypes.DescribeConsumerPartitionInfo, now time.Time) (lag time.Duration) {
+	if lastWrite := p.PartitionStats.LastWriteTime; lastWrite != nil && now.After(*lastWrite) {
+		lag = now.Sub(*lastWrite)
+	}
+
+	stats := &p.PartitionConsumerStats
+	if stats.LastReadTime == nil || stats.MaxWriteTimeLag == nil || stats.CommittedOffset > stats.LastReadOffset {
+		return lag
+	}
+	if readMessageWritten := stats.LastReadTime.Add(-*stats.MaxWriteTimeLag); now.Sub(readMessageWritten) > lag {
+		lag = now.Sub(readMessageWritten)
+	}
+
+	return lag
+}
@@ -0,0 +1,210 @@
+package topic
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/pkg/xtest"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicoptions"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topictypes"
+)
+
+type lagMonitorTestClient struct {
+	m            sync.Mutex
+	descriptions map[string]topictypes.TopicConsumerDescription
+	err          error
+}
+
+func (c *lagMonitorTestClient) DescribeTopicConsumer(
+	_ context.Context, path, consumer string, _ ...topicoptions.DescribeConsumerOption,
+) (topictypes.TopicConsumerDescription, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.err != nil {
+		return topictypes.TopicConsumerDescription{}, c.err
+	}
+
+	return c.descriptions[path+"/"+consumer], nil
+}
+
+func (c *lagMonitorTestClient) set(path, consumer string, partitions ...topictypes.DescribeConsumerPartitionInfo) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.descriptions[path+"/"+consumer] = topictypes.TopicConsumerDescription{Path: path, Partitions: partitions}
+}
+
+func testConsumerPartition(
+	partitionID, committed, end int64, lastWrite time.Time,
+) topictypes.DescribeConsumerPartitionInfo {
+	var p topictypes.DescribeConsumerPartitionInfo
+	p.PartitionID = partitionID
+	p.Active = true
+	p.PartitionStats.PartitionsOffset.End = end
+	p.PartitionStats.LastWriteTime = &lastWrite
+	p.PartitionConsumerStats.CommittedOffset = committed
+
+	return p
+}
+
+func TestLagMonitor(t *testing.T) {
+	ctx := xtest.Context(t)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	client := &lagMonitorTestClient{descriptions: map[string]topictypes.TopicConsumerDescription{}}
+	client.set("topic", "consumer",
+		testConsumerPartition(0, 10, 10, now.Add(-time.Hour)),
+		testConsumerPartition(1, 10, 25, now.Add(-time.Minute)),
+	)
+
+	var (
+		lags   []ConsumerLag
+		alerts []LagAlert
+	)
+	monitor := newLagMonitor(client, []LagMonitorConsumer{{Topic: "topic", Consumer: "consumer"}},
+		WithLagMonitorMessageLagThreshold(10),
+		WithLagMonitorOnLag(func(lag ConsumerLag) {
+			lags = append(lags, lag)
+		}),
+		WithLagMonitorOnAlert(func(alert LagAlert) {
+			alerts = append(alerts, alert)
+		}),
+	)
+	monitor.now = func() time.Time { return now }
+
+	res, err := monitor.Check(ctx)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.Equal(t, int64(15), res[0].MessageLag)
+	require.Equal(t, time.Minute, res[0].TimeLag)
+	require.Equal(t, []PartitionLag{
+		{PartitionID: 0, Active: true, CommittedOffset: 10, EndOffset: 10},
+		{PartitionID: 1, Active: true, CommittedOffset: 10, EndOffset: 25, MessageLag: 15, TimeLag: time.Minute},
+	}, res[0].Partitions)
+	require.Len(t, lags, 1)
+
+	last, ok := monitor.Lag("topic", "consumer")
+	require.True(t, ok)
+	require.Equal(t, res[0], last)
+
+	require.Len(t, alerts, 1)
+	require.True(t, alerts[0].Firing)
+	require.Equal(t, int64(1), alerts[0].Partition.PartitionID)
+
+	// alert fired once
+	_, err = monitor.Check(ctx)
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+
+	// consumer caught up
+	client.set("topic", "consumer",
+		testConsumerPartition(0, 10, 10, now.Add(-time.Hour)),
+		testConsumerPartition(1, 25, 25, now.Add(-time.Minute)),
+	)
+	_, err = monitor.Check(ctx)
+	require.NoError(t, err)
+	require.Len(t, alerts, 2)
+	require.False(t, alerts[1].Firing)
+	require.Zero(t, alerts[1].Partition.MessageLag)
+}
+
+func TestLagMonitorTimeLag(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	p := testConsumerPartition(0, 10, 30, now.Add(-time.Minute))
+
+	// consumer doesn't read the partition, oldest message written before last write
+	require.Equal(t, time.Minute, partitionTimeLag(&p, now))
+
+	// read message was written 10 minutes ago, not committed messages before it are older
+	lastRead := now.Add(-5 * time.Minute)
+	writeLag := 5 * time.Minute
+	p.PartitionConsumerStats.LastReadOffset = 20
+	p.PartitionConsumerStats.LastReadTime = &lastRead
+	p.PartitionConsumerStats.MaxWriteTimeLag = &writeLag
+	require.Equal(t, 10*time.Minute, partitionTimeLag(&p, now))
+
+	// read messages committed, not committed messages are newer than read message
+	p.PartitionConsumerStats.CommittedOffset = 21
+	require.Equal(t, time.Minute, partitionTimeLag(&p, now))
+}
+
+func TestLagMonitorPartitionDisappeared(t *testing.T) {
+	ctx := xtest.Context(t)
+	now := time.Now()
+	client := &lagMonitorTestClient{descriptions: map[string]topictypes.TopicConsumerDescription{}}
+	client.set("topic", "consumer",
+		testConsumerPartition(0, 0, 1, now),
+		testConsumerPartition(1, 0, 100, now),
+	)
+
+	var alerts []LagAlert
+	monitor := newLagMonitor(client, []LagMonitorConsumer{{Topic: "topic", Consumer: "consumer"}},
+		WithLagMonitorMessageLagThreshold(10),
+		WithLagMonitorOnAlert(func(alert LagAlert) {
+			alerts = append(alerts, alert)
+		}),
+	)
+
+	_, err := monitor.Check(ctx)
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	require.True(t, alerts[0].Firing)
+
+	client.set("topic", "consumer", testConsumerPartition(0, 0, 1, now))
+	_, err = monitor.Check(ctx)
+	require.NoError(t, err)
+	require.Len(t, alerts, 2)
+	require.False(t, alerts[1].Firing)
+	require.Equal(t, int64(1), alerts[1].Partition.PartitionID)
+	require.Empty(t, monitor.alerts)
+}
+
+func TestLagMonitorBackground(t *testing.T) {
+	ctx := xtest.Context(t)
+	client := &lagMonitorTestClient{descriptions: map[string]topictypes.TopicConsumerDescription{}}
+	client.set("topic", "consumer", testConsumerPartition(0, 1, 2, time.Now()))
+
+	polled := make(chan ConsumerLag, 1)
+	monitor, err := NewLagMonitor(client, []LagMonitorConsumer{{Topic: "topic", Consumer: "consumer"}},
+		WithLagMonitorInterval(time.Millisecond),
+		WithLagMonitorOnLag(func(lag ConsumerLag) {
+			select {
+			case polled <- lag:
+			default:
+			}
+		}),
+	)
+	require.NoError(t, err)
+
+	lag := <-polled
+	require.Equal(t, int64(1), lag.MessageLag)
+
+	require.NoError(t, monitor.Close(ctx))
+	_, err = monitor.Check(ctx)
+	require.ErrorIs(t, err, errLagMonitorClosed)
+}
+
+func TestLagMonitorErrors(t *testing.T) {
+	_, err := NewLagMonitor(&lagMonitorTestClient{}, nil)
+	require.ErrorIs(t, err, errLagMonitorNoConsumers)
+
+	ctx := xtest.Context(t)
+	testErr := errors.New("test error")
+	var callbackErr error
+	monitor := newLagMonitor(
+		&lagMonitorTestClient{err: testErr},
+		[]LagMonitorConsumer{{Topic: "topic", Consumer: "consumer"}},
+		WithLagMonitorOnError(func(consumer LagMonitorConsumer, err error) {
+			callbackErr = err
+		}),
+	)
+
+	_, err = monitor.Check(ctx)
+	require.ErrorIs(t, err, testErr)
+	require.ErrorIs(t, callbackErr, testErr)
+}
@@ -0,0 +1,513 @@
+package topic
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/background"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicoptions"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicreader"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicwriter"
+)
+
+var (
+	errMirrorClosed                 = xerrors.Wrap(errors.New("ydb: topic mirror closed"))
+	errMirrorNoDestinationPartition = xerrors.Wrap(errors.New("ydb: destination topic of mirror has no partitions"))
+)
+
+const defaultMirrorMaxWriters = 100
+
+// MirrorTopics is source topic with consumer and destination topic for Mirror
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type MirrorTopics struct {
+	Source      string
+	Consumer    string
+	Destination string
+}
+
+// MirrorTransformFunc may change message before write to destination topic.
+// The message prefilled with data, metadata, CreatedAt and SeqNo of the source message.
+// Return false for skip the message, error stops the mirror.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type MirrorTransformFunc func(
+	ctx context.Context,
+	source *topicreader.Message,
+	message *topicwriter.Message,
+) (keep bool, err error)
+
+// MirrorStats is counters of mirrored messages
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type MirrorStats struct {
+	// MessagesMirrored is count of messages, written to destination topic
+	MessagesMirrored int64
+
+	// MessagesSkipped is count of messages, skipped by transform func
+	MessagesSkipped int64
+
+	// LastCreatedAt is CreatedAt of last committed source message
+	LastCreatedAt time.Time
+}
+
+// MirrorOption set settings for Mirror
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type MirrorOption func(cfg *mirrorConfig)
+
+type mirrorConfig struct {
+	readerOptions []topicoptions.ReaderOption
+	writerOptions []topicoptions.WriterOption
+	transform     MirrorTransformFunc
+	maxWriters    int
+}
+
+// WithMirrorReaderOptions set options of source topic reader
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithMirrorReaderOptions(opts ...topicoptions.ReaderOption) MirrorOption {
+	return func(cfg *mirrorConfig) {
+		cfg.readerOptions = append(cfg.readerOptions, opts...)
+	}
+}
+
+// WithMirrorWriterOptions set options of destination topic writers.
+// Producer, partition, seqno and created at options are set by the mirror.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithMirrorWriterOptions(opts ...topicoptions.WriterOption) MirrorOption {
+	return func(cfg *mirrorConfig) {
+		cfg.writerOptions = append(cfg.writerOptions, opts...)
+	}
+}
+
+// WithMirrorMaxWriters set max count of opened destination writers, default 100.
+// The mirror opens writer for every pair of destination partition and producer id,
+// least recently used writers are closed after write of the batch, when the count is over the limit.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithMirrorMaxWriters(count int) MirrorOption {
+	return func(cfg *mirrorConfig) {
+		cfg.maxWriters = count
+	}
+}
+
+// WithMirrorTransform set func for filter and transform messages
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithMirrorTransform(f MirrorTransformFunc) MirrorOption {
+	return func(cfg *mirrorConfig) {
+		cfg.transform = f
+	}
+}
+
+type mirrorReader interface {
+	ReadMessagesBatch(ctx context.Context, opts ...topicreader.ReadBatchOption) (*topicreader.Batch, error)
+	Commit(ctx context.Context, obj topicreader.CommitRangeGetter) error
+	Close(ctx context.Context) error
+}
+
+type mirrorWriter interface {
+	Write(ctx context.Context, messages ...topicwriter.Message) error
+	Flush(ctx context.Context) error
+	Close(ctx context.Context) error
+}
+
+type mirrorStartWriterFunc func(partitionID int64, producerID string) (mirrorWriter, error)
+
+type mirrorWriterKey struct {
+	partitionID int64
+	producerID  string
+}
+
+type mirrorWriterItem struct {
+	writer   mirrorWriter
+	lastUsed uint64
+}
+
+// Mirror copies messages from source topic to destination topic, usually in other database.
+//
+// Messages of source partition written to destination partition with the same id, if destination topic has it.
+// Messages keep producer id, seqno, created at and metadata of source messages, then destination
+// server deduplicates messages, written again after restart of the mirror.
+// If source partition written to destination partition with other id, producer id gets suffix
+// with source partition id: else messages of same producer from several source partitions
+// would be deduplicated by seqno as one sequence.
+// Source offsets committed after ack of messages from destination server only.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type Mirror struct {
+	topics                MirrorTopics
+	cfg                   mirrorConfig
+	source                LagMonitorClient
+	reader                mirrorReader
+	startWriter           mirrorStartWriterFunc
+	destinationPartitions map[int64]bool
+	destinationIDs        []int64
+
+	background *background.Worker
+
+	writersMutex sync.Mutex
+	writers      map[mirrorWriterKey]*mirrorWriterItem
+	writersTick  uint64
+
+	m     sync.Mutex
+	stats MirrorStats
+}
+
+// StartMirror start copy messages from source topic to destination topic in background.
+// Source and destination may be clients of different databases.
+// Call Close for stop the mirror.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func StartMirror(
+	ctx context.Context,
+	source Client,
+	destination Client,
+	topics MirrorTopics,
+	opts ...MirrorOption,
+) (*Mirror, error) {
+	cfg := newMirrorConfig(opts)
+
+	description, err := destination.Describe(ctx, topics.Destination)
+	if err != nil {
+		return nil, xerrors.WithStackTrace(fmt.Errorf("ydb: failed to describe mirror destination topic: %w", err))
+	}
+	partitionIDs := make([]int64, 0, len(description.Partitions))
+	for i := range description.Partitions {
+		partitionIDs = append(partitionIDs, description.Partitions[i].PartitionID)
+	}
+
+	reader, err := source.StartReader(topics.Consumer, topicoptions.ReadTopic(topics.Source), cfg.readerOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	startWriter := func(partitionID int64, producerID string) (mirrorWriter, error) {
+		writerOptions := append([]topicoptions.WriterOption{
+			topicoptions.WithWriterProducerID(producerID),
+			topicoptions.WithWriterPartitionID(partitionID),
+		}, cfg.writerOptions...)
+		writerOptions = append(writerOptions,
+			topicoptions.WithWriterSetAutoSeqNo(false),
+			topicoptions.WithWriterSetAutoCreatedAt(false),
+		)
+
+		return destination.StartWriter(topics.Destination, writerOptions...)
+	}
+
+	m, err := newMirror(source, reader, startWriter, partitionIDs, topics, cfg)
+	if err != nil {
+		_ = reader.Close(ctx)
+
+		return nil, err
+	}
+	m.start()
+
+	return m, nil
+}
+
+func newMirrorConfig(opts []MirrorOption) mirrorConfig {
+	cfg := mirrorConfig{
+		maxWriters: defaultMirrorMaxWriters,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+
+	return cfg
+}
+
+func newMirror(
+	source LagMonitorClient,
+	reader mirrorReader,
+	startWriter mirrorStartWriterFunc,
+	destinationPartitionIDs []int64,
+	topics MirrorTopics,
+	cfg mirrorConfig, //nolint:gocritic
+) (*Mirror, error) {
+	if len(destinationPartitionIDs) == 0 {
+		return nil, xerrors.WithStackTrace(errMirrorNoDestinationPartition)
+	}
+
+	m := &Mirror{
+		topics:                topics,
+		cfg:                   cfg,
+		source:                source,
+		reader:                reader,
+		startWriter:           startWriter,
+		destinationPartitions: make(map[int64]bool, len(destinationPartitionIDs)),
+		destinationIDs:        append([]int64(nil), destinationPartitionIDs...),
+		background:            background.NewWorker(context.Background(), "topic mirror"),
+		writers:               make(map[mirrorWriterKey]*mirrorWriterItem),
+	}
+	for _, id := range destinationPartitionIDs {
+		m.destinationPartitions[id] = true
+	}
+
+	return m, nil
+}
+
+func (m *Mirror) start() {
+	m.background.Start("mirror messages", m.mirrorLoop)
+}
+
+// Stats returns counters of mirrored messages
+func (m *Mirror) Stats() MirrorStats {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	return m.stats
+}
+
+// Lag returns lag of the mirror consumer. Offsets committed after write to destination only,
+// then the lag is count of source messages, which are not written to destination yet.
+func (m *Mirror) Lag(ctx context.Context) (ConsumerLag, error) {
+	consumer := LagMonitorConsumer{Topic: m.topics.Source, Consumer: m.topics.Consumer}
+	description, err := m.source.DescribeTopicConsumer(
+		ctx, consumer.Topic, consumer.Consumer, topicoptions.IncludeConsumerStats(),
+	)
+	if err != nil {
+		return ConsumerLag{}, xerrors.WithStackTrace(err)
+	}
+
+	return computeConsumerLag(consumer, &description, time.Now()), nil
+}
+
+// WaitStop waits stop of the mirror by Close or by error and returns the reason
+func (m *Mirror) WaitStop(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-m.background.Done():
+		return m.background.CloseReason()
+	}
+}
+
+// Close stops the mirror and closes source reader and destination writers
+func (m *Mirror) Close(ctx context.Context) error {
+	bgErr := m.background.Close(ctx, errMirrorClosed)
+	if errors.Is(bgErr, background.ErrAlreadyClosed) {
+		// stopped by error, resources need release anyway
+		bgErr = nil
+	}
+
+	errs := []error{bgErr, m.reader.Close(ctx)}
+
+	m.writersMutex.Lock()
+	defer m.writersMutex.Unlock()
+
+	for key, item := range m.writers {
+		errs = append(errs, item.writer.Close(ctx))
+		delete(m.writers, key)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (m *Mirror) mirrorLoop(ctx context.Context) {
+	for {
+		batch, err := m.reader.ReadMessagesBatch(ctx)
+		if err == nil {
+			err = m.mirrorBatch(ctx, batch)
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				_ = m.background.Close(ctx, xerrors.WithStackTrace(fmt.Errorf("ydb: topic mirror failed: %w", err)))
+			}
+
+			return
+		}
+	}
+}
+
+func (m *Mirror) mirrorBatch(ctx context.Context, batch *topicreader.Batch) error {
+	var (
+		keys     []mirrorWriterKey
+		messages = make(map[mirrorWriterKey][]topicwriter.Message)
+		skipped  int64
+	)
+
+	partitionID := m.destinationPartition(batch.PartitionID())
+	for _, source := range batch.Messages {
+		message, keep, err := m.createMessage(ctx, source)
+		if err != nil {
+			return err
+		}
+		if !keep {
+			skipped++
+
+			continue
+		}
+
+		key := mirrorWriterKey{
+			partitionID: partitionID,
+			producerID:  mirrorProducerID(source.ProducerID, batch.PartitionID(), partitionID),
+		}
+		if source.ProducerID == "" {
+			// seqno of messages without producer continue offsets of source partition for deduplication
+			message.SeqNo = source.Offset + 1
+		}
+		if _, has := messages[key]; !has {
+			keys = append(keys, key)
+		}
+		messages[key] = append(messages[key], message)
+	}
+
+	mirrored := int64(0)
+	writers := make([]mirrorWriter, 0, len(keys))
+	for _, key := range keys {
+		writer, err := m.writer(key)
+		if err != nil {
+			return err
+		}
+		if err = writer.Write(ctx, messages[key]...); err != nil {
+			return err
+		}
+		writers = append(writers, writer)
+		mirrored += int64(len(messages[key]))
+	}
+	for _, writer := range writers {
+		if err := writer.Flush(ctx); err != nil {
+			return err
+		}
+	}
+
+	if err := m.closeLeastRecentlyUsedWriters(ctx); err != nil {
+		return err
+	}
+	if err := m.reader.Commit(ctx, batch); err != nil {
+		return err
+	}
+
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	m.stats.MessagesMirrored += mirrored
+	m.stats.MessagesSkipped += skipped
+	if len(batch.Messages) > 0 {
+		m.stats.LastCreatedAt = batch.Messages[len(batch.Messages)-1].CreatedAt
+	}
+
+	return nil
+}
+
+func (m *Mirror) createMessage(
+	ctx context.Context,
+	source *topicreader.Message,
+) (_ topicwriter.Message, keep bool, _ error) {
+	data, err := io.ReadAll(source)
+	if err != nil {
+		return topicwriter.Message{}, false, xerrors.WithStackTrace(err)
+	}
+
+	message := topicwriter.Message{
+		SeqNo:     source.SeqNo,
+		CreatedAt: source.CreatedAt,
+		Data:      bytes.NewReader(data),
+	}
+	if message.CreatedAt.IsZero() {
+		message.CreatedAt = source.WrittenAt
+	}
+	if len(source.Metadata) > 0 {
+		message.Metadata = make(map[string][]byte, len(source.Metadata))
+		for k, v := range source.Metadata {
+			message.Metadata[k] = v
+		}
+	}
+
+	if m.cfg.transform == nil {
+		return message, true, nil
+	}
+
+	keep, err = m.cfg.transform(ctx, source, &message)
+
+	return message, keep, err
+}
+
+// destinationPartition returns destination partition with the same id or
+// select partition by the source partition id if destination has no the same partition
+func (m *Mirror) destinationPartition(sourcePartitionID int64) int64 {
+	if m.destinationPartitions[sourcePartitionID] {
+		return sourcePartitionID
+	}
+
+	index := sourcePartitionID % int64(len(m.destinationIDs))
+	if index < 0 {
+		index = -index
+	}
+
+	return m.destinationIDs[index]
+}
+
+// mirrorProducerID returns producer id for destination writer.
+// Source producer id kept if source partition written to destination partition with the same id only,
+// else messages of the producer from other source partitions may be mapped to the same destination partition.
+func mirrorProducerID(sourceProducerID string, sourcePartitionID, destinationPartitionID int64) string {
+	switch {
+	case sourceProducerID == "":
+		return fmt.Sprintf("ydb-topic-mirror-%v", sourcePartitionID)
+	case sourcePartitionID == destinationPartitionID:
+		return sourceProducerID
+	default:
+		return fmt.Sprintf("%s-ydb-topic-mirror-%v", sourceProducerID, sourcePartitionID)
+	}
+}
+
+func (m *Mirror) writer(key mirrorWriterKey) (mirrorWriter, error) {
+	m.writersMutex.Lock()
+	defer m.writersMutex.Unlock()
+
+	m.writersTick++
+
+	if item, ok := m.writers[key]; ok {
+		item.lastUsed = m.writersTick
+
+		return item.writer, nil
+	}
+
+	writer, err := m.startWriter(key.partitionID, key.producerID)
+	if err != nil {
+		return nil, err
+	}
+	m.writers[key] = &mirrorWriterItem{writer: writer, lastUsed: m.writersTick}
+
+	return writer, nil
+}
+
+// closeLeastRecentlyUsedWriters closes writers over the max writers limit.
+// Called after flush of the batch, then closed writers have no messages in flight.
+func (m *Mirror) closeLeastRecentlyUsedWriters(ctx context.Context) error {
+	m.writersMutex.Lock()
+	defer m.writersMutex.Unlock()
+
+	if m.cfg.maxWriters <= 0 || len(m.writers) <= m.cfg.maxWriters {
+		return nil
+	}
+
+	keys := make([]mirrorWriterKey, 0, len(m.writers))
+	for key := range m.writers {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return m.writers[keys[i]].lastUsed < m.writers[keys[j]].lastUsed
+	})
+
+	var errs []error
+	for _, key := range keys[:len(keys)-m.cfg.maxWriters] {
+		errs = append(errs, m.writers[key].writer.Close(ctx))
+		delete(m.writers, key)
+	}
+
+	return xerrors.WithStackTrace(errors.Join(errs...))
+}
@@ -0,0 +1,321 @@
+package topic
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/topic/topicreadercommon"
+	"github.com/ydb-platform/ydb-go-sdk/v3/pkg/xtest"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicreader"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicwriter"
+)
+
+var (
+	_ mirrorReader = (*topicreader.Reader)(nil)
+	_ mirrorWriter = (*topicwriter.Writer)(nil)
+)
+
+type mirrorTestReader struct {
+	batches   chan *topicreader.Batch
+	committed chan *topicreader.Batch
+	closed    bool
+}
+
+func (r *mirrorTestReader) ReadMessagesBatch(
+	ctx context.Context,
+	_ ...topicreader.ReadBatchOption,
+) (*topicreader.Batch, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case batch := <-r.batches:
+		return batch, nil
+	}
+}
+
+func (r *mirrorTestReader) Commit(_ context.Context, obj topicreader.CommitRangeGetter) error {
+	r.committed <- obj.(*topicreader.Batch) //nolint:forcetypeassert
+
+	return nil
+}
+
+func (r *mirrorTestReader) Close(_ context.Context) error {
+	r.closed = true
+
+	return nil
+}
+
+type mirrorTestWrittenMessage struct {
+	partitionID int64
+	producerID  string
+	seqNo       int64
+	createdAt   time.Time
+	metadata    map[string][]byte
+	data        string
+}
+
+type mirrorTestWriters struct {
+	m        sync.Mutex
+	written  []mirrorTestWrittenMessage
+	flushed  int
+	closed   int
+	writeErr error
+}
+
+func (w *mirrorTestWriters) start(partitionID int64, producerID string) (mirrorWriter, error) {
+	return &mirrorTestWriter{writers: w, partitionID: partitionID, producerID: producerID}, nil
+}
+
+func (w *mirrorTestWriters) messages() []mirrorTestWrittenMessage {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	return append([]mirrorTestWrittenMessage(nil), w.written...)
+}
+
+type mirrorTestWriter struct {
+	writers     *mirrorTestWriters
+	partitionID int64
+	producerID  string
+}
+
+func (w *mirrorTestWriter) Write(_ context.Context, messages ...topicwriter.Message) error {
+	w.writers.m.Lock()
+	defer w.writers.m.Unlock()
+
+	if w.writers.writeErr != nil {
+		return w.writers.writeErr
+	}
+	for i := range messages {
+		data, err := io.ReadAll(messages[i].Data)
+		if err != nil {
+			return err
+		}
+		w.writers.written = append(w.writers.written, mirrorTestWrittenMessage{
+			partitionID: w.partitionID,
+			producerID:  w.producerID,
+			seqNo:       messages[i].SeqNo,
+			createdAt:   messages[i].CreatedAt,
+			metadata:    messages[i].Metadata,
+			data:        string(data),
+		})
+	}
+
+	return nil
+}
+
+func (w *mirrorTestWriter) Flush(_ context.Context) error {
+	w.writers.m.Lock()
+	defer w.writers.m.Unlock()
+
+	w.writers.flushed++
+
+	return nil
+}
+
+func (w *mirrorTestWriter) Close(_ context.Context) error {
+	w.writers.m.Lock()
+	defer w.writers.m.Unlock()
+
+	w.writers.closed++
+
+	return nil
+}
+
+func newMirrorTestMessage(
+	offset int64,
+	producerID string,
+	seqNo int64,
+	data string,
+) *topicreadercommon.PublicMessageBuilder {
+	return topicreadercommon.NewPublicMessageBuilder().
+		Offset(offset).
+		ProducerID(producerID).
+		Seqno(seqNo).
+		CreatedAt(time.Unix(offset, 0)).
+		DataAndUncompressedSize([]byte(data))
+}
+
+func newTestMirror(
+	t *testing.T,
+	destinationPartitions []int64,
+	opts ...MirrorOption,
+) (*Mirror, *mirrorTestReader, *mirrorTestWriters) {
+	reader := &mirrorTestReader{
+		batches:   make(chan *topicreader.Batch, 10),
+		committed: make(chan *topicreader.Batch, 10),
+	}
+	writers := &mirrorTestWriters{}
+	client := &lagMonitorTestClient{}
+	topics := MirrorTopics{Source: "source", Consumer: "consumer", Destination: "destination"}
+
+	m, err := newMirror(client, reader, writers.start, destinationPartitions, topics, newMirrorConfig(opts))
+	require.NoError(t, err)
+
+	return m, reader, writers
+}
+
+func TestMirror(t *testing.T) {
+	t.Run("CopyMessages", func(t *testing.T) {
+		ctx := xtest.Context(t)
+		m, reader, writers := newTestMirror(t, []int64{0, 1})
+		m.start()
+
+		writtenAt := time.Unix(100, 0)
+		batch := topicreadercommon.NewPublicBatchBuilder("source", 1).Messages(
+			newMirrorTestMessage(5, "producer", 10, "a").
+				Metadata(map[string][]byte{"key": []byte("val")}),
+			newMirrorTestMessage(6, "", 0, "b").
+				CreatedAt(time.Time{}).
+				WrittenAt(writtenAt),
+			newMirrorTestMessage(7, "producer", 11, "c"),
+		).Build()
+		reader.batches <- batch
+		require.Equal(t, batch, <-reader.committed)
+
+		require.Equal(t, []mirrorTestWrittenMessage{
+			{
+				partitionID: 1,
+				producerID:  "producer",
+				seqNo:       10,
+				createdAt:   time.Unix(5, 0),
+				metadata:    map[string][]byte{"key": []byte("val")},
+				data:        "a",
+			},
+			{
+				partitionID: 1,
+				producerID:  "producer",
+				seqNo:       11,
+				createdAt:   time.Unix(7, 0),
+				data:        "c",
+			},
+			{
+				partitionID: 1,
+				producerID:  "ydb-topic-mirror-1",
+				seqNo:       7,
+				createdAt:   writtenAt,
+				data:        "b",
+			},
+		}, writers.messages())
+
+		writers.m.Lock()
+		require.Equal(t, 2, writers.flushed)
+		writers.m.Unlock()
+
+		stats := m.Stats()
+		require.Equal(t, int64(3), stats.MessagesMirrored)
+		require.Zero(t, stats.MessagesSkipped)
+		require.Equal(t, time.Unix(7, 0), stats.LastCreatedAt)
+
+		require.NoError(t, m.Close(ctx))
+		require.True(t, reader.closed)
+		require.Equal(t, 2, writers.closed)
+		require.ErrorIs(t, m.WaitStop(ctx), errMirrorClosed)
+	})
+	t.Run("PartitionMapping", func(t *testing.T) {
+		m, _, _ := newTestMirror(t, []int64{10, 20})
+		require.Equal(t, int64(10), m.destinationPartition(10))
+		require.Equal(t, int64(20), m.destinationPartition(20))
+		require.Equal(t, int64(10), m.destinationPartition(0))
+		require.Equal(t, int64(20), m.destinationPartition(3))
+	})
+	t.Run("ProducerIDOfMappedPartition", func(t *testing.T) {
+		ctx := xtest.Context(t)
+		m, reader, writers := newTestMirror(t, []int64{0})
+		m.start()
+		defer func() {
+			_ = m.Close(ctx)
+		}()
+
+		reader.batches <- topicreadercommon.NewPublicBatchBuilder("source", 0).Messages(newMirrorTestMessage(0, "producer", 1, "a")).Build()
+		<-reader.committed
+		reader.batches <- topicreadercommon.NewPublicBatchBuilder("source", 1).Messages(newMirrorTestMessage(0, "producer", 1, "b")).Build()
+		<-reader.committed
+
+		messages := writers.messages()
+		require.Len(t, messages, 2)
+		require.Equal(t, "producer", messages[0].producerID)
+		require.Equal(t, "producer-ydb-topic-mirror-1", messages[1].producerID)
+		require.Equal(t, int64(0), messages[1].partitionID)
+	})
+	t.Run("MaxWriters", func(t *testing.T) {
+		ctx := xtest.Context(t)
+		m, reader, writers := newTestMirror(t, []int64{0}, WithMirrorMaxWriters(2))
+		m.start()
+		defer func() {
+			_ = m.Close(ctx)
+		}()
+
+		for _, producerID := range []string{"p1", "p2", "p1", "p3"} {
+			reader.batches <- topicreadercommon.NewPublicBatchBuilder("source", 0).Messages(newMirrorTestMessage(0, producerID, 1, "a")).Build()
+			<-reader.committed
+		}
+
+		writers.m.Lock()
+		require.Equal(t, 1, writers.closed)
+		writers.m.Unlock()
+
+		m.writersMutex.Lock()
+		defer m.writersMutex.Unlock()
+
+		require.Len(t, m.writers, 2)
+		require.Contains(t, m.writers, mirrorWriterKey{partitionID: 0, producerID: "p1"})
+		require.Contains(t, m.writers, mirrorWriterKey{partitionID: 0, producerID: "p3"})
+	})
+	t.Run("Transform", func(t *testing.T) {
+		ctx := xtest.Context(t)
+		m, reader, writers := newTestMirror(t, []int64{0}, WithMirrorTransform(
+			func(ctx context.Context, source *topicreader.Message, message *topicwriter.Message) (bool, error) {
+				if source.Offset%2 == 1 {
+					return false, nil
+				}
+				message.Metadata = map[string][]byte{"mirrored": nil}
+
+				return true, nil
+			},
+		))
+		m.start()
+		defer func() {
+			_ = m.Close(ctx)
+		}()
+
+		reader.batches <- topicreadercommon.NewPublicBatchBuilder("source", 0).Messages(
+			newMirrorTestMessage(0, "producer", 1, "a"),
+			newMirrorTestMessage(1, "producer", 2, "b"),
+			newMirrorTestMessage(2, "producer", 3, "c"),
+		).Build()
+		<-reader.committed
+
+		messages := writers.messages()
+		require.Len(t, messages, 2)
+		require.Equal(t, "a", messages[0].data)
+		require.Equal(t, "c", messages[1].data)
+		require.Equal(t, map[string][]byte{"mirrored": nil}, messages[1].metadata)
+
+		stats := m.Stats()
+		require.Equal(t, int64(2), stats.MessagesMirrored)
+		require.Equal(t, int64(1), stats.MessagesSkipped)
+	})
+	t.Run("WriteErrorWithoutCommit", func(t *testing.T) {
+		ctx := xtest.Context(t)
+		m, reader, writers := newTestMirror(t, []int64{0})
+		testErr := errors.New("test")
+		writers.writeErr = testErr
+		m.start()
+
+		reader.batches <- topicreadercommon.NewPublicBatchBuilder("source", 0).Messages(newMirrorTestMessage(0, "producer", 1, "a")).Build()
+		require.ErrorIs(t, m.WaitStop(ctx), testErr)
+		require.Empty(t, reader.committed)
+		require.NoError(t, m.Close(ctx))
+	})
+	t.Run("NoDestinationPartitions", func(t *testing.T) {
+		_, err := newMirror(&lagMonitorTestClient{}, &mirrorTestReader{}, nil, nil, MirrorTopics{}, mirrorConfig{})
+		require.ErrorIs(t, err, errMirrorNoDestinationPartition)
+	})
+}
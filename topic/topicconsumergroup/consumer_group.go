@@ -0,0 +1,207 @@
+// Package topicconsumergroup is adapter of topic listener to the shape of kafka consumer groups
+// (Setup/Cleanup/ConsumeClaim with claim per partition) for simplify migration of kafka consumers to YDB topics.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+package topicconsumergroup
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xcontext"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topiclistener"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicoptions"
+)
+
+var errGroupClosed = xerrors.Wrap(errors.New("ydb: topic consumer group closed"))
+
+// ListenerStarter starts topic listener, it is implemented by topic.Client
+type ListenerStarter interface {
+	StartListener(
+		consumer string,
+		handler topiclistener.EventHandler,
+		readSelectors topicoptions.ReadSelectors,
+		opts ...topicoptions.ListenerOption,
+	) (*topiclistener.TopicListener, error)
+}
+
+// Handler handles claims of the consumer group session, like kafka consumer group handler.
+// ConsumeClaim called in own goroutine for every claim, so the methods must be safe for concurrent use.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type Handler interface {
+	// Setup called at start of the session, before any ConsumeClaim call
+	Setup(session *Session) error
+
+	// Cleanup called at end of the session, after all ConsumeClaim calls returned
+	Cleanup(session *Session) error
+
+	// ConsumeClaim must read messages from claim.Messages() until the channel is closed, it is closed
+	// when server stops the partition session or at end of the consumer group session.
+	// Error of ConsumeClaim stops the session, Consume returns the error.
+	ConsumeClaim(session *Session, claim *Claim) error
+}
+
+type groupListener interface {
+	WaitStop(ctx context.Context) error
+	Close(ctx context.Context) error
+}
+
+type startListenerFunc func(handler topiclistener.EventHandler, topics []string) (groupListener, error)
+
+// ConsumerGroup reads topics with the consumer and passes messages to claims of Handler.
+// Every started partition session of the listener is a claim, marked offsets are committed to YDB
+// for the consumer.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type ConsumerGroup struct {
+	startListener startListenerFunc
+
+	m       sync.Mutex
+	closed  bool
+	cancels map[*Session]context.CancelCauseFunc
+}
+
+// New creates consumer group, which reads topics by the consumer. Options are used for start listeners.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func New(client ListenerStarter, consumer string, opts ...topicoptions.ListenerOption) *ConsumerGroup {
+	return newConsumerGroup(func(handler topiclistener.EventHandler, topics []string) (groupListener, error) {
+		selectors := make(topicoptions.ReadSelectors, len(topics))
+		for i := range topics {
+			selectors[i] = topicoptions.ReadSelector{Path: topics[i]}
+		}
+
+		return client.StartListener(consumer, handler, selectors, opts...)
+	})
+}
+
+func newConsumerGroup(startListener startListenerFunc) *ConsumerGroup {
+	return &ConsumerGroup{
+		startListener: startListener,
+		cancels:       make(map[*Session]context.CancelCauseFunc),
+	}
+}
+
+// Consume joins to the consumer group and runs session of the handler for the topics.
+// It blocks until ctx cancelled, Close called or error of the handler or the listener.
+// Returns nil if the session stopped by ctx or Close.
+func (g *ConsumerGroup) Consume(ctx context.Context, topics []string, handler Handler) error {
+	sessionCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	session := newSession(sessionCtx, cancel)
+	if err := g.addSession(session, cancel); err != nil {
+		return err
+	}
+	defer g.removeSession(session)
+
+	if err := handler.Setup(session); err != nil {
+		return err
+	}
+
+	listener, err := g.startListener(&listenerHandler{session: session, handler: handler}, topics)
+	if err != nil {
+		return errors.Join(err, handler.Cleanup(session))
+	}
+
+	err = listener.WaitStop(sessionCtx)
+	if sessionCtx.Err() != nil {
+		// stopped by ctx, Close or failed ConsumeClaim
+		err = nil
+		if cause := context.Cause(sessionCtx); ctx.Err() == nil && !errors.Is(cause, errGroupClosed) {
+			err = cause
+		}
+	}
+	if closeErr := listener.Close(xcontext.ValueOnly(ctx)); err == nil && sessionCtx.Err() == nil {
+		err = closeErr
+	}
+
+	session.stop()
+
+	return errors.Join(err, handler.Cleanup(session))
+}
+
+// Close stops all sessions of the group, calls of Consume will return errors after close.
+func (g *ConsumerGroup) Close() error {
+	g.m.Lock()
+	defer g.m.Unlock()
+
+	if g.closed {
+		return xerrors.WithStackTrace(errGroupClosed)
+	}
+	g.closed = true
+	for _, cancel := range g.cancels {
+		cancel(errGroupClosed)
+	}
+
+	return nil
+}
+
+func (g *ConsumerGroup) addSession(session *Session, cancel context.CancelCauseFunc) error {
+	g.m.Lock()
+	defer g.m.Unlock()
+
+	if g.closed {
+		return xerrors.WithStackTrace(errGroupClosed)
+	}
+	g.cancels[session] = cancel
+
+	return nil
+}
+
+func (g *ConsumerGroup) removeSession(session *Session) {
+	g.m.Lock()
+	defer g.m.Unlock()
+
+	delete(g.cancels, session)
+}
+
+// listenerHandler maps events of topic listener to claims of the session
+type listenerHandler struct {
+	topiclistener.BaseHandler
+
+	session *Session
+	handler Handler
+}
+
+func (h *listenerHandler) OnStartPartitionSessionRequest(
+	ctx context.Context,
+	event *topiclistener.EventStartPartitionSession,
+) error {
+	h.session.startClaim(h.handler, event.PartitionSession, event.CommittedOffset, event.PartitionOffsets.End)
+	event.Confirm()
+
+	return nil
+}
+
+func (h *listenerHandler) OnReadMessages(ctx context.Context, event *topiclistener.ReadMessages) error {
+	if claim := h.session.claim(event.PartitionSession.PartitionSessionID); claim != nil {
+		claim.push(ctx, event.Batch, event.Confirm)
+	}
+
+	return nil
+}
+
+func (h *listenerHandler) OnStopPartitionSessionRequest(
+	ctx context.Context,
+	event *topiclistener.EventStopPartitionSession,
+) error {
+	if claim := h.session.removeClaim(event.PartitionSession.PartitionSessionID); claim != nil {
+		claim.close()
+
+		// wait ConsumeClaim for commit processed messages before release the partition
+		if event.Graceful {
+			select {
+			case <-claim.done:
+			case <-ctx.Done():
+			case <-h.session.ctx.Done():
+			}
+		}
+	}
+	event.Confirm()
+
+	return nil
+}
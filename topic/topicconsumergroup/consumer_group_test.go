@@ -0,0 +1,181 @@
+package topicconsumergroup
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/empty"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/topic/topiclistenerinternal"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/topic/topicreadercommon"
+	"github.com/ydb-platform/ydb-go-sdk/v3/pkg/xtest"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topiclistener"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicreader"
+)
+
+type testListener struct {
+	stopped empty.Chan
+	closed  atomic.Bool
+}
+
+func (l *testListener) WaitStop(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-l.stopped:
+		return errors.New("listener stopped")
+	}
+}
+
+func (l *testListener) Close(context.Context) error {
+	l.closed.Store(true)
+
+	return nil
+}
+
+type testHandler struct {
+	setup, cleanup atomic.Int32
+	messages       chan *topicreader.Message
+	consumeErr     error
+}
+
+func (h *testHandler) Setup(*Session) error {
+	h.setup.Add(1)
+
+	return nil
+}
+
+func (h *testHandler) Cleanup(*Session) error {
+	h.cleanup.Add(1)
+
+	return nil
+}
+
+func (h *testHandler) ConsumeClaim(session *Session, claim *Claim) error {
+	for message := range claim.Messages() {
+		if h.consumeErr != nil {
+			return h.consumeErr
+		}
+		h.messages <- message
+		session.MarkMessage(message)
+	}
+
+	return nil
+}
+
+type testGroup struct {
+	group    *ConsumerGroup
+	listener *testListener
+	handlers chan *listenerHandler
+}
+
+func newTestGroup() *testGroup {
+	res := &testGroup{
+		listener: &testListener{stopped: make(empty.Chan)},
+		handlers: make(chan *listenerHandler, 1),
+	}
+	res.group = newConsumerGroup(func(handler topiclistener.EventHandler, topics []string) (groupListener, error) {
+		res.handlers <- handler.(*listenerHandler)
+
+		return res.listener, nil
+	})
+
+	return res
+}
+
+func TestConsumerGroup(t *testing.T) {
+	partitionSession := topiclistener.PartitionSession{PartitionSessionID: 1, TopicPath: "topic", PartitionID: 2}
+
+	t.Run("ClaimLifecycle", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(xtest.Context(t))
+		g := newTestGroup()
+		handler := &testHandler{messages: make(chan *topicreader.Message)}
+
+		consumeResult := make(chan error, 1)
+		go func() {
+			consumeResult <- g.group.Consume(ctx, []string{"topic"}, handler)
+		}()
+		h := <-g.handlers
+		require.Equal(t, int32(1), handler.setup.Load())
+
+		require.NoError(t, h.OnStartPartitionSessionRequest(ctx, topiclistenerinternal.NewPublicStartPartitionSessionEvent(
+			partitionSession, 10, topiclistener.OffsetsRange{Start: 0, End: 20},
+		)))
+		require.Equal(t, map[string][]int64{"topic": {2}}, h.session.Claims())
+
+		claim := h.session.claim(partitionSession.PartitionSessionID)
+		require.Equal(t, int64(10), claim.InitialOffset())
+		require.Equal(t, int64(20), claim.HighWaterMarkOffset())
+
+		var confirmed atomic.Int32
+		pushed := make(empty.Chan)
+		go func() {
+			claim.push(ctx, topicreadercommon.NewPublicBatchBuilder("topic", 2).Offsets(10, 11).Build(), func() { confirmed.Add(1) })
+			close(pushed)
+		}()
+
+		require.Equal(t, int64(10), (<-handler.messages).Offset)
+		require.Equal(t, int32(0), confirmed.Load())
+		require.Equal(t, int64(11), (<-handler.messages).Offset)
+		xtest.WaitChannelClosed(t, pushed)
+		xtest.SpinWaitCondition(t, nil, func() bool {
+			return confirmed.Load() == 1
+		})
+
+		require.NoError(t, h.OnStopPartitionSessionRequest(ctx, topiclistenerinternal.NewPublicStopPartitionSessionEvent(
+			partitionSession, true, 12,
+		)))
+		xtest.WaitChannelClosed(t, claim.done)
+		require.Empty(t, h.session.Claims())
+
+		cancel()
+		require.NoError(t, <-consumeResult)
+		require.True(t, g.listener.closed.Load())
+		require.Equal(t, int32(1), handler.cleanup.Load())
+	})
+	t.Run("ConsumeClaimError", func(t *testing.T) {
+		ctx := xtest.Context(t)
+		g := newTestGroup()
+		testErr := errors.New("test")
+		handler := &testHandler{consumeErr: testErr}
+
+		consumeResult := make(chan error, 1)
+		go func() {
+			consumeResult <- g.group.Consume(ctx, []string{"topic"}, handler)
+		}()
+		h := <-g.handlers
+
+		require.NoError(t, h.OnStartPartitionSessionRequest(ctx, topiclistenerinternal.NewPublicStartPartitionSessionEvent(
+			partitionSession, 0, topiclistener.OffsetsRange{},
+		)))
+		h.session.claim(partitionSession.PartitionSessionID).push(ctx, topicreadercommon.NewPublicBatchBuilder("topic", 2).Offsets(0).Build(), func() {})
+
+		require.ErrorIs(t, <-consumeResult, testErr)
+		require.Equal(t, int32(1), handler.cleanup.Load())
+	})
+	t.Run("ListenerStopped", func(t *testing.T) {
+		g := newTestGroup()
+		handler := &testHandler{}
+
+		close(g.listener.stopped)
+		require.Error(t, g.group.Consume(xtest.Context(t), []string{"topic"}, handler))
+		require.Equal(t, int32(1), handler.cleanup.Load())
+	})
+	t.Run("Close", func(t *testing.T) {
+		g := newTestGroup()
+		handler := &testHandler{}
+
+		consumeResult := make(chan error, 1)
+		go func() {
+			consumeResult <- g.group.Consume(xtest.Context(t), []string{"topic"}, handler)
+		}()
+		<-g.handlers
+
+		require.NoError(t, g.group.Close())
+		require.NoError(t, <-consumeResult)
+		require.Error(t, g.group.Consume(xtest.Context(t), []string{"topic"}, handler))
+	})
+}
@@ -0,0 +1,253 @@
+package topicconsumergroup
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/empty"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topiclistener"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicreader"
+)
+
+// Session is one run of Consume for the handler
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type Session struct {
+	ctx    context.Context //nolint:containedctx
+	cancel context.CancelCauseFunc
+
+	claimsWg sync.WaitGroup
+
+	m      sync.Mutex
+	claims map[int64]*Claim
+}
+
+func newSession(ctx context.Context, cancel context.CancelCauseFunc) *Session {
+	return &Session{
+		ctx:    ctx,
+		cancel: cancel,
+		claims: make(map[int64]*Claim),
+	}
+}
+
+// Context is cancelled at end of the session
+func (s *Session) Context() context.Context {
+	return s.ctx
+}
+
+// Claims returns partitions of current claims of the session by topics
+func (s *Session) Claims() map[string][]int64 {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	res := make(map[string][]int64)
+	for _, claim := range s.claims {
+		res[claim.topic] = append(res[claim.topic], claim.partition)
+	}
+
+	return res
+}
+
+// MarkMessage marks the message and all previous messages of the partition as processed
+func (s *Session) MarkMessage(message *topicreader.Message) {
+	s.MarkOffset(message.Topic(), message.PartitionID(), message.Offset+1)
+}
+
+// MarkOffset marks messages of the partition before offset as processed, offset is offset of next message
+// for read like in kafka. Processed messages are committed in background when all messages
+// of received batch are marked. Marks for partitions without claim in the session are ignored.
+func (s *Session) MarkOffset(topic string, partition, offset int64) {
+	s.m.Lock()
+	var claim *Claim
+	for _, c := range s.claims {
+		if c.topic == topic && c.partition == partition {
+			claim = c
+
+			break
+		}
+	}
+	s.m.Unlock()
+
+	if claim != nil {
+		claim.mark(offset)
+	}
+}
+
+// startClaim adds claim of the partition session and starts ConsumeClaim of the handler for it.
+// Error of ConsumeClaim cancels the session.
+func (s *Session) startClaim(
+	handler Handler,
+	partitionSession topiclistener.PartitionSession,
+	initialOffset, highWaterMarkOffset int64,
+) {
+	claim := &Claim{
+		topic:               partitionSession.TopicPath,
+		partition:           partitionSession.PartitionID,
+		initialOffset:       initialOffset,
+		highWaterMarkOffset: highWaterMarkOffset,
+		messages:            make(chan *topicreader.Message),
+		stopped:             make(empty.Chan),
+		done:                make(empty.Chan),
+	}
+
+	s.m.Lock()
+	s.claims[partitionSession.PartitionSessionID] = claim
+	s.m.Unlock()
+
+	s.claimsWg.Add(1)
+	go func() {
+		defer s.claimsWg.Done()
+		defer close(claim.done)
+
+		if err := handler.ConsumeClaim(s, claim); err != nil {
+			s.cancel(xerrors.WithStackTrace(fmt.Errorf(
+				"ydb: consume claim of topic %q partition %v failed: %w", claim.topic, claim.partition, err,
+			)))
+		}
+	}()
+}
+
+func (s *Session) claim(partitionSessionID int64) *Claim {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return s.claims[partitionSessionID]
+}
+
+func (s *Session) removeClaim(partitionSessionID int64) *Claim {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	claim := s.claims[partitionSessionID]
+	delete(s.claims, partitionSessionID)
+
+	return claim
+}
+
+// stop closes all claims and waits for finish of ConsumeClaim calls
+func (s *Session) stop() {
+	s.m.Lock()
+	claims := s.claims
+	s.claims = make(map[int64]*Claim)
+	s.m.Unlock()
+
+	for _, claim := range claims {
+		claim.close()
+	}
+	s.claimsWg.Wait()
+}
+
+type pendingBatch struct {
+	endOffset int64
+	confirm   func()
+}
+
+// Claim is partition session of the consumer group session
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type Claim struct {
+	topic               string
+	partition           int64
+	initialOffset       int64
+	highWaterMarkOffset int64
+
+	done      empty.Chan // closed after ConsumeClaim returns
+	stopped   empty.Chan
+	closeOnce sync.Once
+
+	// sendM prevents close of the messages channel while send
+	sendM    sync.Mutex
+	messages chan *topicreader.Message
+	closed   bool
+
+	m       sync.Mutex
+	marked  int64
+	pending []pendingBatch
+}
+
+// Topic of the claim
+func (c *Claim) Topic() string {
+	return c.topic
+}
+
+// Partition of the claim
+func (c *Claim) Partition() int64 {
+	return c.partition
+}
+
+// InitialOffset is committed offset of the partition at start of the claim
+func (c *Claim) InitialOffset() int64 {
+	return c.initialOffset
+}
+
+// HighWaterMarkOffset is offset of next message of the partition at start of the claim
+func (c *Claim) HighWaterMarkOffset() int64 {
+	return c.highWaterMarkOffset
+}
+
+// Messages returns channel of messages of the claim, the channel is closed at end of the claim
+func (c *Claim) Messages() <-chan *topicreader.Message {
+	return c.messages
+}
+
+// push sends messages of the batch to the channel, confirm called after all messages of the batch are marked.
+// Not sent messages will not be committed and will be read again by other claim.
+func (c *Claim) push(ctx context.Context, batch *topicreader.Batch, confirm func()) {
+	if len(batch.Messages) == 0 {
+		return
+	}
+
+	c.m.Lock()
+	c.pending = append(c.pending, pendingBatch{
+		endOffset: batch.Messages[len(batch.Messages)-1].Offset + 1,
+		confirm:   confirm,
+	})
+	c.m.Unlock()
+
+	c.sendM.Lock()
+	defer c.sendM.Unlock()
+
+	if c.closed {
+		return
+	}
+	for _, message := range batch.Messages {
+		select {
+		case c.messages <- message:
+		case <-c.stopped:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *Claim) mark(offset int64) {
+	c.m.Lock()
+	if offset > c.marked {
+		c.marked = offset
+	}
+
+	var confirms []func()
+	for len(c.pending) > 0 && c.pending[0].endOffset <= c.marked {
+		confirms = append(confirms, c.pending[0].confirm)
+		c.pending = c.pending[1:]
+	}
+	c.m.Unlock()
+
+	for _, confirm := range confirms {
+		confirm()
+	}
+}
+
+func (c *Claim) close() {
+	c.closeOnce.Do(func() {
+		close(c.stopped)
+
+		c.sendM.Lock()
+		c.closed = true
+		close(c.messages)
+		c.sendM.Unlock()
+	})
+}
@@ -3,6 +3,7 @@ package topicoptions
 import (
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/grpcwrapper/rawtopic/rawtopiccommon"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/topic/topiclistenerinternal"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/topic/topicreadercommon"
 	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topictypes"
 )
 
@@ -20,3 +21,60 @@ func WithListenerAddDecoder(codec topictypes.Codec, decoderCreate CreateDecoderF
 		cfg.Decoders.AddDecoder(rawtopiccommon.Codec(codec), decoderCreate)
 	}
 }
+
+// WithListenerCodecLz4 add lz4 decoder for the custom codec id.
+// Lz4 has no code in the protocol, the id must be same as id of writers, see topictypes.CodecCustomerFirst.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithListenerCodecLz4(codec topictypes.Codec) ListenerOption {
+	return WithListenerAddDecoder(codec, topicreadercommon.NewLz4Decoder)
+}
+
+// WithListenerDeadLetter enable dead letter topic for the listener.
+// If OnReadMessages handler returns error for a batch, the batch will be redelivered to the handler with backoff
+// until maxAttempts failures, then messages of the batch are written to the dead letter topic
+// with original payload and error metadata (see topicreader.DeadLetterMetadataError and others)
+// and committed, the listener continues to work.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithListenerDeadLetter(topicPath string, maxAttempts int) ListenerOption {
+	return func(cfg *topiclistenerinternal.StreamListenerConfig) {
+		cfg.DeadLetter.Topic = topicPath
+		cfg.DeadLetter.MaxAttempts = maxAttempts
+	}
+}
+
+// WithListenerMessageKey enable concurrent processing of messages within partition.
+// OnReadMessages called for every message separately: messages with different keys are processed
+// concurrently, messages with the same key - one by one in order of offsets.
+// Confirm of the message marks it as processed, offsets committed up to first not processed message only.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithListenerMessageKey(messageKey func(message *topicreadercommon.PublicMessage) string) ListenerOption {
+	return func(cfg *topiclistenerinternal.StreamListenerConfig) {
+		cfg.KeyedProcessing.MessageKey = messageKey
+	}
+}
+
+// WithListenerMaxInFlightMessages set max count of messages of one partition, which are received but not committed,
+// with WithListenerMessageKey. Message is committed after confirm of the message and all messages before it,
+// so read of the partition waits when the count of not confirmed messages reaches the limit. Default: 100
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithListenerMaxInFlightMessages(count int) ListenerOption {
+	return func(cfg *topiclistenerinternal.StreamListenerConfig) {
+		cfg.KeyedProcessing.MaxInFlightMessages = count
+	}
+}
+
+// WithListenerMiddleware add middlewares for messages of the listener. Middlewares called in order of add
+// for every message after receive and before the message will be passed to OnReadMessages handler.
+// Messages, dropped by middleware, committed by the reader. Middlewares called from receive goroutine
+// and must not block, see topicreader.Middleware.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithListenerMiddleware(middlewares ...ReaderMiddleware) ListenerOption {
+	return func(cfg *topiclistenerinternal.StreamListenerConfig) {
+		cfg.Middlewares = append(cfg.Middlewares, middlewares...)
+	}
+}
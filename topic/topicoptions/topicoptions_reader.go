@@ -26,6 +26,19 @@ func ReadTopic(path string) ReadSelectors {
 // ReaderOption options for topic reader
 type ReaderOption = topicreaderinternal.PublicReaderOption
 
+// WithReaderDeadLetter enable dead letter topic for the reader.
+// Report failed processing of messages by Reader.ReportFailure or Reader.ReportBatchFailure.
+// After maxAttempts failures messages are written to the dead letter topic with original payload
+// and error metadata (see topicreader.DeadLetterMetadataError and others) and committed.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithReaderDeadLetter(topicPath string, maxAttempts int) ReaderOption {
+	return func(cfg *topicreaderinternal.ReaderConfig) {
+		cfg.DeadLetter.Topic = topicPath
+		cfg.DeadLetter.MaxAttempts = maxAttempts
+	}
+}
+
 // WithReaderOperationTimeout
 //
 // Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
@@ -172,6 +185,31 @@ func WithAddDecoder(codec topictypes.Codec, decoderCreate CreateDecoderFunc) Rea
 	}
 }
 
+// WithReaderCodecLz4 add lz4 decoder for the custom codec id.
+// Lz4 has no code in the protocol, the id must be same as id of writers, see topictypes.CodecCustomerFirst.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithReaderCodecLz4(codec topictypes.Codec) ReaderOption {
+	return WithAddDecoder(codec, topicreadercommon.NewLz4Decoder)
+}
+
+// ReaderMiddleware handles messages before they are returned from reader, see topicreader.Middleware
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type ReaderMiddleware = topicreadercommon.PublicMiddleware
+
+// WithReaderMiddleware add middlewares for messages of the reader. Middlewares called in order of add
+// for every message after receive and before the message will be returned by ReadMessage or ReadMessagesBatch.
+// Messages, dropped by middleware, committed by the reader. Middlewares called from receive goroutine
+// and must not block, see topicreader.Middleware.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithReaderMiddleware(middlewares ...ReaderMiddleware) ReaderOption {
+	return func(cfg *topicreaderinternal.ReaderConfig) {
+		cfg.Middlewares = append(cfg.Middlewares, middlewares...)
+	}
+}
+
 // CommitMode variants of commit mode of the reader
 type CommitMode = topicreadercommon.PublicCommitMode
 
@@ -32,6 +32,15 @@ func WithWriterAddEncoder(codec topictypes.Codec, f CreateEncoderFunc) WriterOpt
 	return topicwriterinternal.WithAddEncoder(rawtopiccommon.Codec(codec), f)
 }
 
+// WithWriterCodecLz4 add lz4 encoder for the custom codec id.
+// Lz4 has no code in the protocol, readers of the topic must use lz4 decoder with same codec id,
+// see topictypes.CodecCustomerFirst.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithWriterCodecLz4(codec topictypes.Codec) WriterOption {
+	return topicwriterinternal.WithAddEncoder(rawtopiccommon.Codec(codec), topicwriterinternal.NewLz4Encoder)
+}
+
 // WithWriterCheckRetryErrorFunction can override default error retry policy
 // use CheckErrorRetryDecisionDefault for use default behavior for the error
 // callback func must be fast and deterministic: always result same result for same error - it can be called
@@ -57,14 +66,100 @@ func WithWriterMaxQueueLen(num int) WriterOption {
 	return topicwriterinternal.WithMaxQueueLen(num)
 }
 
-// WithWriterMessageMaxBytesSize set max body size of one message in bytes.
-// Writer will return error in message will be more than the size.
+// WithWriterLinger set max duration of wait new messages for add them to batch before send.
+// Linger increase latency of writes, but improve compression and decrease count of requests
+// for high-volume producers of small messages. Flush, Close and writes with wait server ack
+// send messages without wait linger. Zero (default) mean send messages immediately.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithWriterLinger(linger time.Duration) WriterOption {
+	return topicwriterinternal.WithLinger(linger)
+}
+
+// WithWriterMaxBatchMessages set max count of messages in one batch for send to server.
+// Zero (default) mean no limit.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithWriterMaxBatchMessages(num int) WriterOption {
+	return topicwriterinternal.WithMaxBatchMessages(num)
+}
+
+// WithWriterMaxBatchBytes set max uncompressed size of messages in one batch for send to server.
+// Batch contains one message at least, even if the message is bigger than the limit.
+// Zero (default) mean no limit.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithWriterMaxBatchBytes(size int) WriterOption {
+	return topicwriterinternal.WithMaxBatchBytes(size)
+}
+
+// WithWriterSpillDir enable write-ahead spill of messages to local directory.
+// Write returns after save messages to the spill, messages sent to server in background
+// and removed from the spill after ack from server. Messages, which was not acked before stop
+// of the process, will be sent by writer with the same spill dir after restart.
+//
+// Use stable producer id (WithWriterProducerID) with the spill: deduplication of messages,
+// resent after restart, based on seqno of the producer. The directory must be used by one writer only.
+// The spill doesn't support writes within transactions.
+//
+// Write doesn't wait connection to server if writer sets seqno manually or the spill has messages
+// from previous run: seqno continues from last message of the spill.
+// If the spill is empty and writer sets seqno automatically, Write waits first connect for receive
+// last seqno of the producer: messages with less seqno are skipped by server as duplicates.
+// Messages of the producer must be written through the spill only.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithWriterSpillDir(dir string) WriterOption {
+	return topicwriterinternal.WithSpillDir(dir)
+}
+
+// WithWriterSpillMaxBytes set disk quota of the spill in bytes, zero (default) mean unlimited.
+// Write returns topicwriter.ErrSpillQuotaExceeded if messages doesn't fit to the quota.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithWriterSpillMaxBytes(maxBytes int64) WriterOption {
+	return topicwriterinternal.WithSpillMaxBytes(maxBytes)
+}
+
+// WithWriterSpillSyncInterval set interval of fsync spill files.
+// By default (zero interval) spill files synced on every Write.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithWriterSpillSyncInterval(interval time.Duration) WriterOption {
+	return topicwriterinternal.WithSpillSyncInterval(interval)
+}
+
+// WithWriterSpillNoSync disable fsync of spill files. Messages can be lost on crash of OS,
+// but survive restart of the process.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithWriterSpillNoSync(noSync bool) WriterOption {
+	return topicwriterinternal.WithSpillNoSync(noSync)
+}
+
+// WithWriterMessageMaxBytesSize set max body size of one message in bytes, size of message metadata
+// is included to the size. Writer will return error in message will be more than the size.
 func WithWriterMessageMaxBytesSize(size int) WriterOption {
 	return func(cfg *topicwriterinternal.WriterReconnectorConfig) {
 		cfg.MaxMessageSize = size
 	}
 }
 
+// WithWriterMetadataLimits set max count of metadata items and max total size of metadata keys and values
+// of one message, zero means no limit. Write returns error for message with larger metadata.
+// Metadata items from trace handlers (for example traceparent) are not added to messages, which reach the limits.
+// Default: no separate limits for metadata, Write checks total size of message data and metadata
+// by WithWriterMessageMaxBytesSize. Server limits of metadata items count and size are not published
+// by the protocol, server checks them itself.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithWriterMetadataLimits(maxItems, maxBytesSize int) WriterOption {
+	return func(cfg *topicwriterinternal.WriterReconnectorConfig) {
+		cfg.MaxMetadataItems = maxItems
+		cfg.MaxMetadataSize = maxBytesSize
+	}
+}
+
 // WithWriteSessionMeta
 //
 // Deprecated: was experimental and not actual now.
@@ -182,7 +277,9 @@ func WithCodecAutoSelect() WriterOption {
 // WithWriterCodecAutoSelect - auto select best codec for messages stream
 // enabled by default
 // if option enabled - send a batch of messages for every allowed codec (for prevent delayed bad codec accident)
-// then from time to time measure all codecs and select codec with the smallest result messages size
+// then from time to time measure all codecs and select codec with the smallest result messages size.
+// Allowed codecs are codecs of the topic, supported by the writer: raw, gzip, zstd and added by
+// WithWriterAddEncoder or WithWriterCodecLz4. If the topic has no codecs list - raw and gzip only.
 func WithWriterCodecAutoSelect() WriterOption {
 	return topicwriterinternal.WithAutoCodec()
 }
@@ -223,3 +320,79 @@ func WithWriterLogContext(ctx context.Context) WriterOption {
 		cfg.LogContext = ctx
 	}
 }
+
+// KeyedWriterOption options for a topic keyed writer
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type KeyedWriterOption = topicwriterinternal.PublicKeyedWriterOption
+
+// KeyedPartitioner is a strategy of map message key to partition
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type KeyedPartitioner = topicwriterinternal.PublicKeyedPartitioner
+
+const (
+	// KeyedPartitionerAuto use key ranges of partitions for autopartitioned topics and hash of key otherwise
+	KeyedPartitionerAuto = topicwriterinternal.PublicKeyedPartitionerAuto
+
+	// KeyedPartitionerHash map key to partition by hash of key modulo count of active partitions
+	KeyedPartitionerHash = topicwriterinternal.PublicKeyedPartitionerHash
+
+	// KeyedPartitionerKeyRange map hash of key into key ranges of autopartitioned topic partitions
+	KeyedPartitionerKeyRange = topicwriterinternal.PublicKeyedPartitionerKeyRange
+)
+
+// WithKeyedWriterPartitioner set strategy of map message key to partition
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithKeyedWriterPartitioner(partitioner KeyedPartitioner) KeyedWriterOption {
+	return topicwriterinternal.WithKeyedWriterPartitioner(partitioner)
+}
+
+// WithKeyedWriterRefreshInterval set interval of background refresh topic partitions
+// default: 1 minute
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithKeyedWriterRefreshInterval(interval time.Duration) KeyedWriterOption {
+	return topicwriterinternal.WithKeyedWriterRefreshInterval(interval)
+}
+
+// WithKeyedWriterOptions set options for writers of each partition
+// Partition options (WithWriterPartitionID, WithPartitioning) are ignored
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithKeyedWriterOptions(opts ...WriterOption) KeyedWriterOption {
+	return topicwriterinternal.WithKeyedWriterWriterOptions(opts...)
+}
+
+// WriterPoolOption options for a topic writer pool
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type WriterPoolOption = topicwriterinternal.PublicWriterPoolOption
+
+// WithWriterPoolSize set count of writers in the pool
+// default: 4
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithWriterPoolSize(size int) WriterPoolOption {
+	return topicwriterinternal.WithWriterPoolSize(size)
+}
+
+// WithWriterPoolProducerIDPrefix set prefix of producer ids of writers: "<prefix>-<writer index>".
+// The prefix must be stable between restarts of the process for deduplication of messages by server,
+// and it is required with spill (WithWriterSpillDir): spill of each writer is found by its producer id.
+// Don't change size of the pool with spill, else spill of removed writers will not be sent.
+// default: random uuid, it is allowed without spill only
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithWriterPoolProducerIDPrefix(prefix string) WriterPoolOption {
+	return topicwriterinternal.WithWriterPoolProducerIDPrefix(prefix)
+}
+
+// WithWriterPoolWriterOptions set options for each writer of the pool
+// WithWriterProducerID is ignored, spill of each writer placed to subdirectory with name of its producer id.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithWriterPoolWriterOptions(opts ...WriterOption) WriterPoolOption {
+	return topicwriterinternal.WithWriterPoolWriterOptions(opts...)
+}
@@ -0,0 +1,62 @@
+package topicreader
+
+import (
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/topic/topicreadercommon"
+)
+
+// Middleware called for every message after receive and before the message will be returned to user code.
+// It can change metadata or content (see SetMessageData) of the message, validate it or drop it (keep=false).
+// Dropped messages are committed together with previous message of the batch or by the reader itself.
+// Error of the middleware stops the reader without reconnect, because the message fails again after reconnect:
+// middleware must retry temporary errors itself.
+//
+// Middlewares called from goroutine, which receives messages from server: slow middleware delays receive
+// for all partitions. Avoid blocking calls for every message, cache results of them.
+//
+// Content of the message can be read once, middleware which reads the content must set it back by SetMessageData.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type Middleware = topicreadercommon.PublicMiddleware
+
+// EnvelopeKeyProvider returns key for decrypt messages by id of the key
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type EnvelopeKeyProvider = topicreadercommon.PublicEnvelopeKeyProvider
+
+// EnvelopeKeyIDMetadataKey is metadata key with id of the key for decrypt the message
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+const EnvelopeKeyIDMetadataKey = topicreadercommon.EnvelopeKeyIDMetadataKey
+
+// MetadataFilterMiddleware keeps messages with metadata value of the key equal to one of values,
+// other messages are dropped.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func MetadataFilterMiddleware(key string, values ...string) Middleware {
+	return topicreadercommon.MetadataFilter(key, values...)
+}
+
+// EnvelopeDecryptMiddleware decrypts content of messages with EnvelopeKeyIDMetadataKey metadata
+// by key from the provider. Content of encrypted message is AES-GCM nonce, followed by ciphertext
+// (see EnvelopeSeal). Messages without the metadata are passed without changes.
+// Keys are cached by key id, the provider is called once for every key id.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func EnvelopeDecryptMiddleware(keys EnvelopeKeyProvider) Middleware {
+	return topicreadercommon.EnvelopeDecrypt(keys)
+}
+
+// EnvelopeSeal encrypts data by AES-GCM for EnvelopeDecryptMiddleware. The nonce must be unique for the key.
+// Write result with id of the key in EnvelopeKeyIDMetadataKey metadata.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func EnvelopeSeal(key, nonce, data []byte) ([]byte, error) {
+	return topicreadercommon.EnvelopeSeal(key, nonce, data)
+}
+
+// SetMessageData replaces content of the message, for use in middlewares
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func SetMessageData(message *Message, data []byte) {
+	topicreadercommon.MessageSetData(message, data)
+}
@@ -3,6 +3,7 @@ package topicreader
 import (
 	"context"
 	"sync/atomic"
+	"time"
 
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/topic/topicreadercommon"
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/topic/topicreaderinternal"
@@ -54,6 +55,18 @@ func (r *Reader) ReadMessage(ctx context.Context) (*Message, error) {
 // Message contains data and metadata, readed from the server
 type Message = topicreadercommon.PublicMessage
 
+// Metadata keys of messages, written to dead letter topic
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+const (
+	DeadLetterMetadataError           = topicreadercommon.DeadLetterMetadataError
+	DeadLetterMetadataSourceTopic     = topicreadercommon.DeadLetterMetadataSourceTopic
+	DeadLetterMetadataSourcePartition = topicreadercommon.DeadLetterMetadataSourcePartition
+	DeadLetterMetadataSourceOffset    = topicreadercommon.DeadLetterMetadataSourceOffset
+	DeadLetterMetadataConsumer        = topicreadercommon.DeadLetterMetadataConsumer
+	DeadLetterMetadataAttempts        = topicreadercommon.DeadLetterMetadataAttempts
+)
+
 // MessageContentUnmarshaler is interface for unmarshal message content to own struct
 type MessageContentUnmarshaler = topicreadercommon.PublicMessageContentUnmarshaler
 
@@ -75,6 +88,58 @@ func (r *Reader) Commit(ctx context.Context, obj CommitRangeGetter) error {
 	return r.reader.Commit(ctx, obj)
 }
 
+// SeekPartition restarts read the partition from the offset within active reader,
+// for example for replay messages after fix a bug or skip bad messages.
+// Seek forward from messages, which were not read by user code yet, skips buffered messages of the partition
+// without reconnect, skipped messages are committed if commits enabled.
+// Other seek reconnects the reader to the server: buffered messages of all partitions are discarded and commits
+// of messages, received before seek, are skipped. New read position is committed if commits enabled.
+// The seek is applied once by next connection, it is forgotten if the partition is read by other reader.
+// The seek applies to partition with the id in all topics of the reader.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func (r *Reader) SeekPartition(ctx context.Context, partitionID, offset int64) error {
+	return r.reader.SeekPartition(ctx, partitionID, offset)
+}
+
+// SeekToTime restarts read all partitions from messages, written since t.
+// The reader reconnects to the server, buffered messages are discarded and commits of messages,
+// received before seek, are skipped. The seek is applied to partitions, started by next connection only.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func (r *Reader) SeekToTime(ctx context.Context, t time.Time) error {
+	return r.reader.SeekToTime(ctx, t)
+}
+
+// ReportFailure registers failed processing of the message.
+// The reader must be started with topicoptions.WithReaderDeadLetter.
+// After max attempts failures the message is written to the dead letter topic with original payload
+// and error metadata, then committed, and ReportFailure returns true.
+// Until then the message is not committed and will be received again after reconnect.
+// Failure counters are stored in the reader and survive reconnects.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func (r *Reader) ReportFailure(ctx context.Context, msg *Message, cause error) (deadLettered bool, _ error) {
+	if err := r.inCall(&r.commitInFlyght); err != nil {
+		return false, err
+	}
+	defer r.outCall(&r.commitInFlyght)
+
+	return r.reader.ReportFailure(ctx, []*Message{msg}, msg, cause)
+}
+
+// ReportBatchFailure registers failed processing of the batch, same as ReportFailure for the message.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func (r *Reader) ReportBatchFailure(ctx context.Context, batch *Batch, cause error) (deadLettered bool, _ error) {
+	if err := r.inCall(&r.commitInFlyght); err != nil {
+		return false, err
+	}
+	defer r.outCall(&r.commitInFlyght)
+
+	return r.reader.ReportFailure(ctx, batch.Messages, batch, cause)
+}
+
 // PopMessagesBatchTx read messages batch and commit them within tx.
 // If tx failed - the batch will be received again.
 //
@@ -0,0 +1,524 @@
+//go:build go1.23
+
+package topicsugar
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/empty"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+	"github.com/ydb-platform/ydb-go-sdk/v3/query"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicreader"
+)
+
+var (
+	errCDCMaterializerNoKey       = xerrors.Wrap(errors.New("ydb: cdc materializer key function is not set"))
+	errCDCMaterializerNoScan      = xerrors.Wrap(errors.New("ydb: cdc materializer needs scan for non pointer items"))
+	errCDCMaterializerRunTwice    = xerrors.Wrap(errors.New("ydb: cdc materializer already started"))
+	errCDCMaterializerBadResolved = xerrors.Wrap(errors.New("ydb: bad resolved timestamp in cdc message"))
+	errCDCMaterializerPartitions  = xerrors.Wrap(errors.New(
+		"ydb: cdc materializer needs count of changefeed partitions for consistent reads and resolved timestamps",
+	))
+)
+
+// CDCMessageReader reads and commits changefeed messages, it is implemented by topicreader.Reader
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type CDCMessageReader interface {
+	TopicMessageReader
+	Commit(ctx context.Context, obj topicreader.CommitRangeGetter) error
+}
+
+// CDCMaterializerConfig is settings of CDCMaterializer
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type CDCMaterializerConfig[K comparable, V YDBCDCItem[K]] struct {
+	// Key returns primary key of the item from snapshot of the table, required
+	Key func(item V) K
+
+	// Scan creates item from row of the table snapshot.
+	// If nil - row scanned by ScanStruct into new item, V must be pointer to struct in the case.
+	Scan func(row query.Row) (V, error)
+
+	// ConsistentReads enables buffering of changes until resolved timestamp of changefeed pass them.
+	// WaitReady waits until resolved timestamp pass end of the snapshot load (by clock of the client),
+	// after that Get and Range see state of the table at the resolved timestamp.
+	// The changefeed must have enabled virtual timestamps and resolved timestamps.
+	ConsistentReads bool
+
+	// ChangefeedPartitions is count of partitions of the changefeed topic, resolved timestamp of
+	// materializer is minimal resolved timestamp of all the partitions. Required with ConsistentReads
+	// and for Resolved and WaitBarrier, the count can be taken from Describe of the changefeed topic.
+	ChangefeedPartitions int
+}
+
+// CDCMaterializerChange is applied change of the materialized table
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type CDCMaterializerChange[K comparable, V any] struct {
+	Key K
+
+	// Value is new value of the item, zero for erase
+	Value V
+
+	// OldValue is previous value of the item, zero if the item was not exist
+	OldValue V
+
+	Erase bool
+}
+
+// CDCMaterializer keeps in memory copy of a table: it loads snapshot of the table and then applies
+// upserts and erases from changefeed of the table.
+//
+// Reader of the changefeed must be started before Run, with ReadFrom before start of the snapshot load
+// (for example time.Now()), else changes during the load will be lost. The snapshot is not bound to a
+// timestamp of the changefeed: changes before the snapshot are applied again on top of the snapshot,
+// so Get and Range may return values older than the snapshot while the changefeed replays them.
+// State of items converges to actual after read of the changes, use WaitBarrier with time after start
+// of Run for wait it (ChangefeedPartitions required) or ConsistentReads.
+//
+// Items of changes taken from NewImage (if exists) or Update field of cdc message, so changefeed
+// must be in NEW_IMAGE or NEW_AND_OLD_IMAGES mode, or in UPDATES mode if every update writes all columns.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type CDCMaterializer[K comparable, V YDBCDCItem[K]] struct {
+	db        query.Executor
+	tablePath string
+	reader    CDCMessageReader
+	cfg       CDCMaterializerConfig[K, V]
+
+	ready empty.Chan
+	now   func() time.Time
+
+	m                  sync.RWMutex
+	started            bool
+	snapshotLoaded     cdcTimestamp
+	isReady            bool
+	values             map[K]V
+	pending            []pendingCDCChange[K, V]
+	partitionsResolved map[int64]cdcTimestamp
+	resolved           cdcTimestamp
+	resolvedChanged    empty.Chan
+	lastSubscriberID   int
+	subscribers        map[int]func(change CDCMaterializerChange[K, V])
+}
+
+type cdcTimestamp struct {
+	step uint64 // milliseconds of plan step
+	txID uint64
+}
+
+func newCDCTimestamp(ts []uint64) (res cdcTimestamp, ok bool) {
+	if len(ts) != 2 { //nolint:mnd
+		return res, false
+	}
+
+	return cdcTimestamp{step: ts[0], txID: ts[1]}, true
+}
+
+func (ts cdcTimestamp) less(other cdcTimestamp) bool {
+	if ts.step != other.step {
+		return ts.step < other.step
+	}
+
+	return ts.txID < other.txID
+}
+
+type pendingCDCChange[K comparable, V YDBCDCItem[K]] struct {
+	ts      cdcTimestamp
+	message *YDBCDCMessage[V, K]
+}
+
+// NewCDCMaterializer creates materializer of table tablePath, db is usually query.Client,
+// reader reads changefeed of the table. Call Run for load and update the items.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func NewCDCMaterializer[K comparable, V YDBCDCItem[K]](
+	db query.Executor,
+	tablePath string,
+	reader CDCMessageReader,
+	cfg CDCMaterializerConfig[K, V],
+) *CDCMaterializer[K, V] {
+	return &CDCMaterializer[K, V]{
+		db:                 db,
+		tablePath:          tablePath,
+		reader:             reader,
+		cfg:                cfg,
+		ready:              make(empty.Chan),
+		now:                time.Now,
+		values:             make(map[K]V),
+		partitionsResolved: make(map[int64]cdcTimestamp),
+		resolvedChanged:    make(empty.Chan),
+		subscribers:        make(map[int]func(change CDCMaterializerChange[K, V])),
+	}
+}
+
+// Run loads snapshot of the table and applies changes from the changefeed until ctx cancelled or error
+func (m *CDCMaterializer[K, V]) Run(ctx context.Context) error {
+	if m.cfg.Key == nil {
+		return xerrors.WithStackTrace(errCDCMaterializerNoKey)
+	}
+	if m.cfg.ConsistentReads && m.cfg.ChangefeedPartitions <= 0 {
+		return xerrors.WithStackTrace(errCDCMaterializerPartitions)
+	}
+
+	m.m.Lock()
+	started := m.started
+	m.started = true
+	m.m.Unlock()
+	if started {
+		return xerrors.WithStackTrace(errCDCMaterializerRunTwice)
+	}
+
+	if err := m.loadSnapshot(ctx); err != nil {
+		return err
+	}
+	m.m.Lock()
+	// the snapshot is taken before end of the load, consistent state of items is after apply changes
+	// up to the time: older changes replay on top of the snapshot
+	m.snapshotLoaded = cdcTimestamp{step: uint64(m.now().UnixMilli())}
+	if !m.cfg.ConsistentReads {
+		m.setReadyNeedLock()
+	}
+	m.m.Unlock()
+
+	for {
+		mess, err := m.reader.ReadMessage(ctx)
+		if err != nil {
+			return err
+		}
+		if err = m.handleMessage(mess); err != nil {
+			return err
+		}
+		if err = m.reader.Commit(ctx, mess); err != nil {
+			return err
+		}
+	}
+}
+
+func (m *CDCMaterializer[K, V]) loadSnapshot(ctx context.Context) error {
+	res, err := m.db.Query(ctx, fmt.Sprintf("SELECT * FROM `%s`", m.tablePath),
+		query.WithTxControl(query.SnapshotReadOnlyTxControl()),
+	)
+	if err != nil {
+		return xerrors.WithStackTrace(fmt.Errorf("ydb: cdc materializer failed to read table snapshot: %w", err))
+	}
+	defer func() {
+		_ = res.Close(ctx)
+	}()
+
+	values := make(map[K]V)
+	for rs, err := range res.ResultSets(ctx) {
+		if err != nil {
+			return xerrors.WithStackTrace(fmt.Errorf("ydb: cdc materializer failed to read table snapshot: %w", err))
+		}
+		for {
+			row, err := rs.NextRow(ctx)
+			if err != nil {
+				if xerrors.Is(err, io.EOF) {
+					break
+				}
+
+				return xerrors.WithStackTrace(fmt.Errorf("ydb: cdc materializer failed to read table snapshot: %w", err))
+			}
+			item, err := m.scan(row)
+			if err != nil {
+				return err
+			}
+			values[m.cfg.Key(item)] = item
+		}
+	}
+
+	m.m.Lock()
+	m.values = values
+	m.m.Unlock()
+
+	return nil
+}
+
+func (m *CDCMaterializer[K, V]) scan(row query.Row) (item V, _ error) {
+	if m.cfg.Scan != nil {
+		return m.cfg.Scan(row)
+	}
+
+	itemType := reflect.TypeOf(item)
+	if itemType == nil || itemType.Kind() != reflect.Pointer {
+		return item, xerrors.WithStackTrace(errCDCMaterializerNoScan)
+	}
+	item = reflect.New(itemType.Elem()).Interface().(V) //nolint:forcetypeassert
+	if err := row.ScanStruct(item); err != nil {
+		return item, xerrors.WithStackTrace(fmt.Errorf("ydb: cdc materializer failed to scan row: %w", err))
+	}
+
+	return item, nil
+}
+
+func (m *CDCMaterializer[K, V]) handleMessage(mess *topicreader.Message) error {
+	var (
+		resolved struct {
+			Resolved []uint64 `json:"resolved"`
+		}
+		change YDBCDCMessage[V, K]
+	)
+	err := ReadMessageDataWithCallback(mess, func(data []byte) error {
+		if err := json.Unmarshal(data, &resolved); err != nil {
+			return xerrors.WithStackTrace(fmt.Errorf("ydb: cdc materializer failed to unmarshal message: %w", err))
+		}
+		if resolved.Resolved != nil {
+			return nil
+		}
+
+		return json.Unmarshal(data, &change)
+	})
+	if err != nil {
+		return err
+	}
+
+	if resolved.Resolved != nil {
+		ts, ok := newCDCTimestamp(resolved.Resolved)
+		if !ok {
+			return xerrors.WithStackTrace(fmt.Errorf("%w: %v", errCDCMaterializerBadResolved, resolved.Resolved))
+		}
+		m.onResolved(mess.PartitionID(), ts)
+
+		return nil
+	}
+
+	ts, hasTS := newCDCTimestamp(change.TS)
+	if !m.cfg.ConsistentReads || !hasTS {
+		m.notify(m.apply(&change))
+
+		return nil
+	}
+
+	m.m.Lock()
+	index := sort.Search(len(m.pending), func(i int) bool {
+		return ts.less(m.pending[i].ts)
+	})
+	m.pending = append(m.pending, pendingCDCChange[K, V]{})
+	copy(m.pending[index+1:], m.pending[index:])
+	m.pending[index] = pendingCDCChange[K, V]{ts: ts, message: &change}
+	m.m.Unlock()
+
+	return nil
+}
+
+func (m *CDCMaterializer[K, V]) onResolved(partitionID int64, ts cdcTimestamp) {
+	changes := m.updateResolved(partitionID, ts)
+	m.notify(changes...)
+}
+
+// updateResolved saves resolved timestamp of the partition and applies pending changes
+// before new resolved timestamp of the materializer
+func (m *CDCMaterializer[K, V]) updateResolved(partitionID int64, ts cdcTimestamp) (
+	changes []CDCMaterializerChange[K, V],
+) {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	if prev, ok := m.partitionsResolved[partitionID]; ok && !prev.less(ts) {
+		return nil
+	}
+	// without count of partitions the resolved timestamp of materializer is unknown: partitions, which
+	// have not sent resolved timestamp yet, may have older changes
+	if m.cfg.ChangefeedPartitions <= 0 {
+		return nil
+	}
+	m.partitionsResolved[partitionID] = ts
+	if len(m.partitionsResolved) < m.cfg.ChangefeedPartitions {
+		return nil
+	}
+
+	resolved := ts
+	for _, partitionResolved := range m.partitionsResolved {
+		if partitionResolved.less(resolved) {
+			resolved = partitionResolved
+		}
+	}
+	if !m.resolved.less(resolved) {
+		return nil
+	}
+
+	for len(m.pending) > 0 && !resolved.less(m.pending[0].ts) {
+		changes = append(changes, m.applyNeedLock(m.pending[0].message))
+		m.pending = m.pending[1:]
+	}
+	m.resolved = resolved
+	close(m.resolvedChanged)
+	m.resolvedChanged = make(empty.Chan)
+	if m.cfg.ConsistentReads && !m.resolved.less(m.snapshotLoaded) {
+		m.setReadyNeedLock()
+	}
+
+	return changes
+}
+
+func (m *CDCMaterializer[K, V]) setReadyNeedLock() {
+	if !m.isReady {
+		m.isReady = true
+		close(m.ready)
+	}
+}
+
+func (m *CDCMaterializer[K, V]) apply(message *YDBCDCMessage[V, K]) CDCMaterializerChange[K, V] {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	return m.applyNeedLock(message)
+}
+
+func (m *CDCMaterializer[K, V]) applyNeedLock(message *YDBCDCMessage[V, K]) CDCMaterializerChange[K, V] {
+	change := CDCMaterializerChange[K, V]{
+		Key:      message.Key,
+		OldValue: m.values[message.Key],
+		Erase:    message.IsErase(),
+	}
+	if change.Erase {
+		delete(m.values, message.Key)
+
+		return change
+	}
+
+	var zero V
+	change.Value = message.NewImage
+	if change.Value == zero {
+		change.Value = message.Update
+	}
+	if change.Value == zero {
+		change.Value = change.OldValue
+	}
+	m.values[message.Key] = change.Value
+
+	return change
+}
+
+func (m *CDCMaterializer[K, V]) notify(changes ...CDCMaterializerChange[K, V]) {
+	m.m.RLock()
+	subscribers := make([]func(change CDCMaterializerChange[K, V]), 0, len(m.subscribers))
+	for _, f := range m.subscribers {
+		subscribers = append(subscribers, f)
+	}
+	m.m.RUnlock()
+
+	for i := range changes {
+		for _, f := range subscribers {
+			f(changes[i])
+		}
+	}
+}
+
+// Get returns item by key. Until the changefeed is read up to the snapshot the item may be older than
+// in the snapshot, see CDCMaterializer.
+func (m *CDCMaterializer[K, V]) Get(key K) (value V, ok bool) {
+	m.m.RLock()
+	defer m.m.RUnlock()
+
+	value, ok = m.values[key]
+
+	return value, ok
+}
+
+// Range calls f for every item until f returns false. Range iterates over copy of items,
+// so it sees consistent state of items and f can call other methods of the materializer.
+func (m *CDCMaterializer[K, V]) Range(f func(key K, value V) bool) {
+	m.m.RLock()
+	values := make(map[K]V, len(m.values))
+	for k, v := range m.values {
+		values[k] = v
+	}
+	m.m.RUnlock()
+
+	for k, v := range values {
+		if !f(k, v) {
+			return
+		}
+	}
+}
+
+// Len returns count of items
+func (m *CDCMaterializer[K, V]) Len() int {
+	m.m.RLock()
+	defer m.m.RUnlock()
+
+	return len(m.values)
+}
+
+// Subscribe registers f for receive applied changes. f is called from Run goroutine in order of apply changes,
+// it must not block for long time. Call unsubscribe for stop receive changes.
+func (m *CDCMaterializer[K, V]) Subscribe(f func(change CDCMaterializerChange[K, V])) (unsubscribe func()) {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	m.lastSubscriberID++
+	id := m.lastSubscriberID
+	m.subscribers[id] = f
+
+	return func() {
+		m.m.Lock()
+		defer m.m.Unlock()
+
+		delete(m.subscribers, id)
+	}
+}
+
+// WaitReady waits for load snapshot of the table. With ConsistentReads it waits also apply of changes
+// up to end of the load.
+func (m *CDCMaterializer[K, V]) WaitReady(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-m.ready:
+		return nil
+	}
+}
+
+// Resolved returns resolved timestamp of the changefeed: all changes of the table before the timestamp
+// are applied to items. Zero if the changefeed has not sent resolved timestamps yet
+// or ChangefeedPartitions is not set.
+func (m *CDCMaterializer[K, V]) Resolved() time.Time {
+	m.m.RLock()
+	defer m.m.RUnlock()
+
+	if m.resolved.step == 0 {
+		return time.Time{}
+	}
+
+	return time.UnixMilli(int64(m.resolved.step))
+}
+
+// WaitBarrier waits until all changes of the table before t are applied to items (resolved timestamp of
+// the changefeed pass t). It is read barrier: call it with time after commit of write to the table
+// for read the write from the materializer. WaitBarrier needs ChangefeedPartitions.
+func (m *CDCMaterializer[K, V]) WaitBarrier(ctx context.Context, t time.Time) error {
+	if m.cfg.ChangefeedPartitions <= 0 {
+		return xerrors.WithStackTrace(errCDCMaterializerPartitions)
+	}
+	if err := m.WaitReady(ctx); err != nil {
+		return err
+	}
+
+	for {
+		m.m.RLock()
+		resolvedChanged := m.resolvedChanged
+		passed := m.resolved.step > 0 && !time.UnixMilli(int64(m.resolved.step)).Before(t)
+		m.m.RUnlock()
+
+		if passed {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-resolvedChanged:
+		}
+	}
+}
@@ -0,0 +1,283 @@
+//go:build go1.23
+
+package topicsugar
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/empty"
+	internalquery "github.com/ydb-platform/ydb-go-sdk/v3/internal/query"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/topic/topicreadercommon"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xiter"
+	"github.com/ydb-platform/ydb-go-sdk/v3/pkg/xtest"
+	"github.com/ydb-platform/ydb-go-sdk/v3/query"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicreader"
+)
+
+type cdcTestItem struct {
+	ID   int64  `sql:"id" json:"-"`
+	Name string `sql:"name" json:"name"`
+}
+
+func (*cdcTestItem) ParseCDCKey(keyFields []json.RawMessage) (int64, error) {
+	var id int64
+	err := json.Unmarshal(keyFields[0], &id)
+
+	return id, err
+}
+
+func (item *cdcTestItem) SetPrimaryKey(key int64) {
+	item.ID = key
+}
+
+// fakeSnapshotExecutor returns rows of table snapshot
+type fakeSnapshotExecutor struct {
+	query.Executor
+
+	items []cdcTestItem
+}
+
+func (e *fakeSnapshotExecutor) Query(context.Context, string, ...query.ExecuteOption) (query.Result, error) {
+	columns := []*Ydb.Column{
+		{Name: "id", Type: &Ydb.Type{Type: &Ydb.Type_TypeId{TypeId: Ydb.Type_INT64}}},
+		{Name: "name", Type: &Ydb.Type{Type: &Ydb.Type_TypeId{TypeId: Ydb.Type_UTF8}}},
+	}
+	rows := make([]query.Row, len(e.items))
+	for i, item := range e.items {
+		rows[i] = internalquery.NewRow(columns, &Ydb.Value{Items: []*Ydb.Value{
+			{Value: &Ydb.Value_Int64Value{Int64Value: item.ID}},
+			{Value: &Ydb.Value_TextValue{TextValue: item.Name}},
+		}})
+	}
+
+	return &fakeSnapshotResult{resultSet: internalquery.MaterializedResultSet(0, nil, nil, rows)}, nil
+}
+
+type fakeSnapshotResult struct {
+	query.Result
+
+	resultSet query.ResultSet
+}
+
+func (r *fakeSnapshotResult) ResultSets(context.Context) xiter.Seq2[query.ResultSet, error] {
+	return func(yield func(query.ResultSet, error) bool) {
+		yield(r.resultSet, nil)
+	}
+}
+
+func (r *fakeSnapshotResult) Close(context.Context) error {
+	return nil
+}
+
+type fakeCDCReader struct {
+	messages  chan *topicreader.Message
+	committed atomic.Int32
+}
+
+func (r *fakeCDCReader) ReadMessage(ctx context.Context) (*topicreader.Message, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case mess := <-r.messages:
+		return mess, nil
+	}
+}
+
+func (r *fakeCDCReader) Commit(context.Context, topicreader.CommitRangeGetter) error {
+	r.committed.Add(1)
+
+	return nil
+}
+
+func (r *fakeCDCReader) push(t *testing.T, partitionID int64, data string) {
+	committed := r.committed.Load()
+	session := topicreadercommon.NewPartitionSession(context.Background(), "feed", partitionID, 0, "", 0, 0, 0)
+	r.messages <- topicreadercommon.NewPublicMessageBuilder().
+		PartitionSession(session).
+		DataAndUncompressedSize([]byte(data)).
+		Build()
+
+	xtest.SpinWaitCondition(t, nil, func() bool {
+		return r.committed.Load() > committed
+	})
+}
+
+func startTestMaterializer(
+	t *testing.T,
+	cfg CDCMaterializerConfig[int64, *cdcTestItem],
+) (*CDCMaterializer[int64, *cdcTestItem], *fakeCDCReader) {
+	ctx, cancel := context.WithCancel(xtest.Context(t))
+	reader := &fakeCDCReader{messages: make(chan *topicreader.Message)}
+	cfg.Key = func(item *cdcTestItem) int64 { return item.ID }
+
+	m := NewCDCMaterializer[int64, *cdcTestItem](&fakeSnapshotExecutor{items: []cdcTestItem{
+		{ID: 1, Name: "a"},
+		{ID: 2, Name: "b"},
+	}}, "table", reader, cfg)
+	m.now = func() time.Time {
+		return time.UnixMilli(12)
+	}
+
+	stopped := make(empty.Chan)
+	go func() {
+		defer close(stopped)
+
+		_ = m.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-stopped
+	})
+	if !cfg.ConsistentReads {
+		require.NoError(t, m.WaitReady(ctx))
+	}
+
+	return m, reader
+}
+
+func isClosed(ch empty.Chan) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestCDCMaterializer(t *testing.T) {
+	t.Run("Snapshot", func(t *testing.T) {
+		m, _ := startTestMaterializer(t, CDCMaterializerConfig[int64, *cdcTestItem]{})
+
+		require.Equal(t, 2, m.Len())
+		item, ok := m.Get(1)
+		require.True(t, ok)
+		require.Equal(t, &cdcTestItem{ID: 1, Name: "a"}, item)
+
+		names := map[int64]string{}
+		m.Range(func(key int64, value *cdcTestItem) bool {
+			names[key] = value.Name
+
+			return true
+		})
+		require.Equal(t, map[int64]string{1: "a", 2: "b"}, names)
+	})
+	t.Run("ApplyChanges", func(t *testing.T) {
+		m, reader := startTestMaterializer(t, CDCMaterializerConfig[int64, *cdcTestItem]{})
+
+		var changes []CDCMaterializerChange[int64, *cdcTestItem]
+		unsubscribe := m.Subscribe(func(change CDCMaterializerChange[int64, *cdcTestItem]) {
+			changes = append(changes, change)
+		})
+
+		reader.push(t, 0, `{"key":[1],"update":{"name":"a2"}}`)
+		reader.push(t, 0, `{"key":[2],"erase":{}}`)
+		reader.push(t, 1, `{"key":[3],"newImage":{"name":"c"}}`)
+		unsubscribe()
+		reader.push(t, 1, `{"key":[3],"erase":{}}`)
+
+		item, _ := m.Get(1)
+		require.Equal(t, &cdcTestItem{ID: 1, Name: "a2"}, item)
+		_, ok := m.Get(2)
+		require.False(t, ok)
+		_, ok = m.Get(3)
+		require.False(t, ok)
+
+		require.Len(t, changes, 3)
+		require.Equal(t, "a", changes[0].OldValue.Name)
+		require.Equal(t, "a2", changes[0].Value.Name)
+		require.True(t, changes[1].Erase)
+		require.Equal(t, int64(2), changes[1].Key)
+		require.Equal(t, &cdcTestItem{ID: 3, Name: "c"}, changes[2].Value)
+	})
+	t.Run("ConsistentReads", func(t *testing.T) {
+		ctx := xtest.Context(t)
+		m, reader := startTestMaterializer(t, CDCMaterializerConfig[int64, *cdcTestItem]{
+			ConsistentReads:      true,
+			ChangefeedPartitions: 2,
+		})
+
+		reader.push(t, 1, `{"key":[1],"update":{"name":"second"},"ts":[20,1]}`)
+		reader.push(t, 0, `{"key":[1],"update":{"name":"first"},"ts":[10,1]}`)
+		reader.push(t, 0, `{"resolved":[15,0]}`)
+
+		item, _ := m.Get(1)
+		require.Equal(t, "a", item.Name, "changes must wait resolved timestamps of all partitions")
+		require.True(t, m.Resolved().IsZero())
+		require.False(t, isClosed(m.ready), "ready must wait resolved timestamp after the snapshot")
+
+		reader.push(t, 1, `{"resolved":[15,0]}`)
+		require.NoError(t, m.WaitReady(ctx))
+		item, _ = m.Get(1)
+		require.Equal(t, "first", item.Name)
+		require.Equal(t, time.UnixMilli(15), m.Resolved())
+		require.NoError(t, m.WaitBarrier(ctx, time.UnixMilli(15)))
+
+		barrierPassed := make(empty.Chan)
+		go func() {
+			defer close(barrierPassed)
+
+			_ = m.WaitBarrier(ctx, time.UnixMilli(20))
+		}()
+		reader.push(t, 0, `{"resolved":[25,0]}`)
+		reader.push(t, 1, `{"resolved":[21,0]}`)
+		xtest.WaitChannelClosed(t, barrierPassed)
+
+		item, _ = m.Get(1)
+		require.Equal(t, "second", item.Name)
+	})
+	t.Run("ConsistentReadsWaitSnapshot", func(t *testing.T) {
+		m, reader := startTestMaterializer(t, CDCMaterializerConfig[int64, *cdcTestItem]{
+			ConsistentReads:      true,
+			ChangefeedPartitions: 1,
+		})
+
+		reader.push(t, 0, `{"key":[1],"update":{"name":"before snapshot"},"ts":[5,1]}`)
+		reader.push(t, 0, `{"resolved":[10,0]}`)
+		require.Equal(t, time.UnixMilli(10), m.Resolved())
+		require.False(t, isClosed(m.ready), "snapshot is loaded at 12")
+
+		reader.push(t, 0, `{"key":[1],"update":{"name":"after snapshot"},"ts":[11,1]}`)
+		reader.push(t, 0, `{"resolved":[12,0]}`)
+		require.NoError(t, m.WaitReady(xtest.Context(t)))
+		item, _ := m.Get(1)
+		require.Equal(t, "after snapshot", item.Name)
+	})
+	t.Run("ResolvedNeedPartitions", func(t *testing.T) {
+		m, reader := startTestMaterializer(t, CDCMaterializerConfig[int64, *cdcTestItem]{})
+
+		reader.push(t, 0, `{"resolved":[15,0]}`)
+		require.True(t, m.Resolved().IsZero(), "other partitions may have older changes")
+		require.ErrorIs(t, m.WaitBarrier(xtest.Context(t), time.UnixMilli(10)), errCDCMaterializerPartitions)
+	})
+	t.Run("BadMessage", func(t *testing.T) {
+		reader := &fakeCDCReader{messages: make(chan *topicreader.Message, 1)}
+		m := NewCDCMaterializer[int64, *cdcTestItem](&fakeSnapshotExecutor{}, "table", reader,
+			CDCMaterializerConfig[int64, *cdcTestItem]{Key: func(item *cdcTestItem) int64 { return item.ID }},
+		)
+		session := topicreadercommon.NewPartitionSession(context.Background(), "feed", 0, 0, "", 0, 0, 0)
+		reader.messages <- topicreadercommon.NewPublicMessageBuilder().
+			PartitionSession(session).
+			DataAndUncompressedSize([]byte(`{"resolved":[1]}`)).
+			Build()
+
+		require.ErrorIs(t, m.Run(xtest.Context(t)), errCDCMaterializerBadResolved)
+		require.ErrorIs(t, m.Run(xtest.Context(t)), errCDCMaterializerRunTwice)
+	})
+	t.Run("ConsistentReadsNeedPartitions", func(t *testing.T) {
+		m := NewCDCMaterializer[int64, *cdcTestItem](&fakeSnapshotExecutor{}, "table", &fakeCDCReader{},
+			CDCMaterializerConfig[int64, *cdcTestItem]{
+				Key:             func(item *cdcTestItem) int64 { return item.ID },
+				ConsistentReads: true,
+			},
+		)
+
+		require.ErrorIs(t, m.Run(xtest.Context(t)), errCDCMaterializerPartitions)
+	})
+}
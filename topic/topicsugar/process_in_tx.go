@@ -0,0 +1,174 @@
+package topicsugar
+
+import (
+	"context"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/tx"
+	"github.com/ydb-platform/ydb-go-sdk/v3/query"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicoptions"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicreader"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicwriter"
+)
+
+// TxDoer is interface for query.Client
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type TxDoer interface {
+	DoTx(ctx context.Context, op query.TxOperation, opts ...query.DoTxOption) error
+}
+
+// TopicTxBatchReader is interface for topicreader.Reader
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type TopicTxBatchReader interface {
+	PopMessagesBatchTx(
+		ctx context.Context,
+		transaction tx.Identifier,
+		opts ...topicreader.ReadBatchOption,
+	) (*topicreader.Batch, error)
+}
+
+// TopicTxWriterStarter is interface for topic.Client
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type TopicTxWriterStarter interface {
+	StartTransactionalWriter(
+		transaction tx.Identifier,
+		topicpath string,
+		opts ...topicoptions.WriterOption,
+	) (*topicwriter.TxWriter, error)
+}
+
+// TxBatchHandler processes batch of messages within transaction
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type TxBatchHandler func(ctx context.Context, tx query.TxActor, batch *topicreader.Batch) error
+
+// TxBatchWithWriterHandler processes batch of messages within transaction and writes results
+// to output topic with transactional writer
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type TxBatchWithWriterHandler func(
+	ctx context.Context,
+	tx query.TxActor,
+	batch *topicreader.Batch,
+	writer *topicwriter.TxWriter,
+) error
+
+// ProcessInTxOption is option for ProcessInTx
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type ProcessInTxOption func(cfg *processInTxConfig)
+
+type processInTxConfig struct {
+	readOptions   []topicreader.ReadBatchOption
+	txOptions     []query.DoTxOption
+	writerOptions []topicoptions.WriterOption
+}
+
+// WithProcessInTxReadOptions set options for read batches
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithProcessInTxReadOptions(opts ...topicreader.ReadBatchOption) ProcessInTxOption {
+	return func(cfg *processInTxConfig) {
+		cfg.readOptions = append(cfg.readOptions, opts...)
+	}
+}
+
+// WithProcessInTxOptions set options for transactions, for example isolation level
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithProcessInTxOptions(opts ...query.DoTxOption) ProcessInTxOption {
+	return func(cfg *processInTxConfig) {
+		cfg.txOptions = append(cfg.txOptions, opts...)
+	}
+}
+
+// WithProcessInTxWriterOptions set options for transactional writer of ProcessInTxWithWriter
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func WithProcessInTxWriterOptions(opts ...topicoptions.WriterOption) ProcessInTxOption {
+	return func(cfg *processInTxConfig) {
+		cfg.writerOptions = append(cfg.writerOptions, opts...)
+	}
+}
+
+// ProcessInTx reads batches from the reader and processes them with exactly once semantic:
+// every batch read, processed and committed within one transaction. Table changes of the handler
+// and commit of the batch offsets applied together or not applied at all.
+//
+// The transaction retried on retryable errors. The reader re-read the batch from the server
+// after failed transaction, so handler receive the same messages again. Handler must not keep
+// batch or results of its processing between calls - only changes within transaction are applied.
+//
+// ProcessInTx works until context cancelled or handler return non retryable error.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func ProcessInTx(
+	ctx context.Context,
+	db TxDoer,
+	reader TopicTxBatchReader,
+	handler TxBatchHandler,
+	opts ...ProcessInTxOption,
+) error {
+	cfg := newProcessInTxConfig(opts)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		err := db.DoTx(ctx, func(ctx context.Context, tx query.TxActor) error {
+			batch, err := reader.PopMessagesBatchTx(ctx, tx, cfg.readOptions...)
+			if err != nil {
+				return err
+			}
+
+			return handler(ctx, tx, batch)
+		}, cfg.txOptions...)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// ProcessInTxWithWriter same as ProcessInTx, but handler receive transactional writer to topicPath
+// for write results of processing. Messages of the writer will be written to the topic
+// on commit of the transaction only.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func ProcessInTxWithWriter(
+	ctx context.Context,
+	db TxDoer,
+	reader TopicTxBatchReader,
+	writers TopicTxWriterStarter,
+	topicPath string,
+	handler TxBatchWithWriterHandler,
+	opts ...ProcessInTxOption,
+) error {
+	cfg := newProcessInTxConfig(opts)
+
+	return ProcessInTx(ctx, db, reader, func(ctx context.Context, tx query.TxActor, batch *topicreader.Batch) error {
+		writer, err := writers.StartTransactionalWriter(tx, topicPath, cfg.writerOptions...)
+		if err != nil {
+			return err
+		}
+
+		return handler(ctx, tx, batch, writer)
+	}, opts...)
+}
+
+func newProcessInTxConfig(opts []ProcessInTxOption) processInTxConfig {
+	cfg := processInTxConfig{
+		// the batch offsets are committed with the transaction, then retry after undetermined commit
+		// result doesn't process messages twice
+		txOptions: []query.DoTxOption{query.WithIdempotent()},
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+
+	return cfg
+}
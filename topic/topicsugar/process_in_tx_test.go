@@ -0,0 +1,244 @@
+package topicsugar
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/topic/topicreadercommon"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/tx"
+	"github.com/ydb-platform/ydb-go-sdk/v3/pkg/xtest"
+	"github.com/ydb-platform/ydb-go-sdk/v3/query"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicoptions"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicreader"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicwriter"
+)
+
+var (
+	_ TxDoer               = query.Client(nil)
+	_ TopicTxBatchReader   = (*topicreader.Reader)(nil)
+	_ TopicTxWriterStarter = topic.Client(nil)
+)
+
+var (
+	errTestTransient = errors.New("test transient error")
+	errTestCommit    = errors.New("test commit error")
+)
+
+type fakeTx struct {
+	query.TxActor
+
+	id          string
+	rows        []int64
+	onCompleted []func(err error)
+}
+
+func (tx *fakeTx) ID() string {
+	return tx.id
+}
+
+func (tx *fakeTx) complete(err error) {
+	for _, f := range tx.onCompleted {
+		f(err)
+	}
+}
+
+// fakeTxDB executes transactions with retries and fails every failCommitEvery commit
+type fakeTxDB struct {
+	table           map[int64]int
+	commits         int
+	failCommitEvery int
+	onCommitted     func()
+}
+
+func (db *fakeTxDB) DoTx(ctx context.Context, op query.TxOperation, _ ...query.DoTxOption) error {
+	for attempt := 0; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		tx := &fakeTx{id: "tx-" + strconv.Itoa(attempt)}
+		if err := op(ctx, tx); err != nil {
+			tx.complete(err)
+			if errors.Is(err, errTestTransient) {
+				continue
+			}
+
+			return err
+		}
+
+		db.commits++
+		if db.failCommitEvery > 0 && db.commits%db.failCommitEvery == 0 {
+			tx.complete(errTestCommit)
+
+			continue
+		}
+
+		for _, row := range tx.rows {
+			db.table[row]++
+		}
+		tx.complete(nil)
+		if db.onCommitted != nil {
+			db.onCommitted()
+		}
+
+		return nil
+	}
+}
+
+// fakeTxReader reads messages from committed offset, as topic reader after reconnect on failed transaction
+type fakeTxReader struct {
+	session        *topicreadercommon.PartitionSession
+	messagesCount  int64
+	batchSize      int64
+	committed      int64
+	popInFlightTxs int
+}
+
+func (r *fakeTxReader) PopMessagesBatchTx(
+	ctx context.Context,
+	transaction tx.Identifier,
+	_ ...topicreader.ReadBatchOption,
+) (*topicreader.Batch, error) {
+	if r.committed >= r.messagesCount {
+		<-ctx.Done()
+
+		return nil, ctx.Err()
+	}
+
+	end := r.committed + r.batchSize
+	if end > r.messagesCount {
+		end = r.messagesCount
+	}
+	messages := make([]*topicreader.Message, 0, end-r.committed)
+	for offset := r.committed; offset < end; offset++ {
+		messages = append(messages, topicreadercommon.NewPublicMessageBuilder().
+			Offset(offset).
+			PartitionSession(r.session).
+			Build(),
+		)
+	}
+
+	r.popInFlightTxs++
+	tx := transaction.(*fakeTx) //nolint:forcetypeassert
+	tx.onCompleted = append(tx.onCompleted, func(err error) {
+		r.popInFlightTxs--
+		if err == nil {
+			r.committed = end
+		}
+	})
+
+	return topicreadercommon.NewBatch(r.session, messages)
+}
+
+func newTestFakeTxReader(messagesCount, batchSize int64) *fakeTxReader {
+	return &fakeTxReader{
+		session:       topicreadercommon.NewPartitionSession(context.Background(), "topic", 0, 0, "", 0, 0, 0),
+		messagesCount: messagesCount,
+		batchSize:     batchSize,
+	}
+}
+
+func TestProcessInTx(t *testing.T) {
+	t.Run("ExactlyOnceWithFaults", func(t *testing.T) {
+		const messagesCount = 50
+
+		ctx, cancel := context.WithCancel(xtest.Context(t))
+		defer cancel()
+
+		reader := newTestFakeTxReader(messagesCount, 3)
+		db := &fakeTxDB{
+			table:           make(map[int64]int),
+			failCommitEvery: 3,
+		}
+		db.onCommitted = func() {
+			if len(db.table) == messagesCount {
+				cancel()
+			}
+		}
+
+		handlerCalls := 0
+		err := ProcessInTx(ctx, db, reader, func(ctx context.Context, tx query.TxActor, batch *topicreader.Batch) error {
+			handlerCalls++
+			for _, mess := range batch.Messages {
+				tx.(*fakeTx).rows = append(tx.(*fakeTx).rows, mess.Offset) //nolint:forcetypeassert
+			}
+			if handlerCalls%4 == 0 {
+				return errTestTransient
+			}
+
+			return nil
+		})
+		require.ErrorIs(t, err, context.Canceled)
+
+		require.Len(t, db.table, messagesCount)
+		for offset, count := range db.table {
+			require.Equal(t, 1, count, offset)
+		}
+		require.Equal(t, int64(messagesCount), reader.committed)
+		require.Zero(t, reader.popInFlightTxs)
+	})
+	t.Run("HandlerError", func(t *testing.T) {
+		ctx := xtest.Context(t)
+		reader := newTestFakeTxReader(10, 5)
+		db := &fakeTxDB{table: make(map[int64]int)}
+
+		testErr := errors.New("test")
+		err := ProcessInTx(ctx, db, reader, func(ctx context.Context, tx query.TxActor, batch *topicreader.Batch) error {
+			tx.(*fakeTx).rows = append(tx.(*fakeTx).rows, 1) //nolint:forcetypeassert
+
+			return testErr
+		})
+		require.ErrorIs(t, err, testErr)
+		require.Empty(t, db.table)
+		require.Zero(t, reader.committed)
+	})
+	t.Run("WithWriter", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(xtest.Context(t))
+		defer cancel()
+
+		reader := newTestFakeTxReader(4, 2)
+		db := &fakeTxDB{table: make(map[int64]int), failCommitEvery: 2}
+		db.onCommitted = func() {
+			if reader.committed == reader.messagesCount {
+				cancel()
+			}
+		}
+		writers := &fakeTxWriterStarter{}
+
+		err := ProcessInTxWithWriter(ctx, db, reader, writers, "output",
+			func(ctx context.Context, tx query.TxActor, batch *topicreader.Batch, _ *topicwriter.TxWriter) error {
+				require.Equal(t, tx, writers.lastTx)
+
+				return nil
+			},
+		)
+		require.ErrorIs(t, err, context.Canceled)
+
+		// new writer for every transaction attempt: 2 batches and retry of the second batch after failed commit
+		require.Equal(t, 3, writers.started)
+	})
+}
+
+type fakeTxWriterStarter struct {
+	started int
+	lastTx  tx.Identifier
+}
+
+func (s *fakeTxWriterStarter) StartTransactionalWriter(
+	transaction tx.Identifier,
+	topicpath string,
+	_ ...topicoptions.WriterOption,
+) (*topicwriter.TxWriter, error) {
+	if topicpath != "output" {
+		return nil, errors.New("unexpected topic")
+	}
+	s.started++
+	s.lastTx = transaction
+
+	return nil, nil //nolint:nilnil
+}
@@ -0,0 +1,174 @@
+package topicsugar
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+)
+
+const (
+	// MetadataSchemaName is a message metadata key for name of the message schema
+	MetadataSchemaName = "ydb-schema-name"
+
+	// MetadataSchemaVersion is a message metadata key for version of the message schema
+	MetadataSchemaVersion = "ydb-schema-version"
+)
+
+var (
+	errSchemaMismatch       = xerrors.Wrap(errors.New("ydb: message schema mismatch"))
+	errProtobufSerdeNilType = xerrors.Wrap(errors.New("ydb: protobuf serde needs pointer to message type"))
+)
+
+// Schema describes format of messages in topic.
+// Name and Version are written into message metadata by Writer and checked by Reader.
+// Empty Name mean no schema: Writer doesn't write metadata and Reader doesn't check it.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type Schema struct {
+	Name    string
+	Version string
+}
+
+// Serde is a serializer and deserializer of messages content
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type Serde[T any] interface {
+	Marshal(v T) ([]byte, error)
+	Unmarshal(data []byte, dst *T) error
+	Schema() Schema
+}
+
+// SerdeFunc makes Serde from marshal and unmarshal functions
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func SerdeFunc[T any](
+	schema Schema,
+	marshal func(v T) ([]byte, error),
+	unmarshal func(data []byte, dst *T) error,
+) Serde[T] {
+	return funcSerde[T]{
+		schema:    schema,
+		marshal:   marshal,
+		unmarshal: unmarshal,
+	}
+}
+
+// JSONSerde makes Serde with encoding/json format
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func JSONSerde[T any](schema Schema) Serde[T] {
+	return SerdeFunc(schema,
+		func(v T) ([]byte, error) {
+			return json.Marshal(v)
+		},
+		func(data []byte, dst *T) error {
+			return json.Unmarshal(data, dst)
+		},
+	)
+}
+
+// GobSerde makes Serde with encoding/gob format. Every message contains gob type description.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func GobSerde[T any](schema Schema) Serde[T] {
+	return SerdeFunc(schema,
+		func(v T) ([]byte, error) {
+			var buf bytes.Buffer
+			if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+				return nil, err
+			}
+
+			return buf.Bytes(), nil
+		},
+		func(data []byte, dst *T) error {
+			return gob.NewDecoder(bytes.NewReader(data)).Decode(dst)
+		},
+	)
+}
+
+// ProtobufSerde makes Serde with protobuf format. T must be pointer to generated protobuf message type,
+// for example ProtobufSerde[*pb.Event](schema)
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func ProtobufSerde[T proto.Message](schema Schema) Serde[T] {
+	return SerdeFunc(schema,
+		func(v T) ([]byte, error) {
+			return proto.Marshal(v)
+		},
+		func(data []byte, dst *T) error {
+			if v := reflect.ValueOf(*dst); !v.IsValid() || v.IsNil() {
+				t := reflect.TypeOf(dst).Elem()
+				if t.Kind() != reflect.Pointer {
+					return xerrors.WithStackTrace(fmt.Errorf("%w: %v", errProtobufSerdeNilType, t))
+				}
+				*dst = reflect.New(t.Elem()).Interface().(T) //nolint:forcetypeassert
+			}
+
+			return proto.Unmarshal(data, *dst)
+		},
+	)
+}
+
+type funcSerde[T any] struct {
+	schema    Schema
+	marshal   func(v T) ([]byte, error)
+	unmarshal func(data []byte, dst *T) error
+}
+
+func (s funcSerde[T]) Marshal(v T) ([]byte, error) {
+	return s.marshal(v)
+}
+
+func (s funcSerde[T]) Unmarshal(data []byte, dst *T) error {
+	return s.unmarshal(data, dst)
+}
+
+func (s funcSerde[T]) Schema() Schema {
+	return s.schema
+}
+
+// SchemaFromMetadata returns schema of message from its metadata
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func SchemaFromMetadata(metadata map[string][]byte) Schema {
+	return Schema{
+		Name:    string(metadata[MetadataSchemaName]),
+		Version: string(metadata[MetadataSchemaVersion]),
+	}
+}
+
+func (s Schema) writeToMetadata(metadata map[string][]byte) map[string][]byte {
+	if s.Name == "" {
+		return metadata
+	}
+	if metadata == nil {
+		metadata = make(map[string][]byte, 2) //nolint:mnd
+	}
+	metadata[MetadataSchemaName] = []byte(s.Name)
+	if s.Version != "" {
+		metadata[MetadataSchemaVersion] = []byte(s.Version)
+	}
+
+	return metadata
+}
+
+// check returns error if message has schema name other than s
+func (s Schema) check(metadata map[string][]byte) error {
+	if s.Name == "" {
+		return nil
+	}
+
+	actual := SchemaFromMetadata(metadata)
+	if actual.Name != "" && actual.Name != s.Name {
+		return xerrors.WithStackTrace(fmt.Errorf("%w: expected %q, actual %q", errSchemaMismatch, s.Name, actual.Name))
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package topicsugar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/params"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/topic/topicreadercommon"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+	"github.com/ydb-platform/ydb-go-sdk/v3/query"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicoptions"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicreader"
+)
+
+var errNoPartitionSessionForCommit = xerrors.Wrap(errors.New("ydb: commit range without partition session"))
+
+// TableOffsetStore keeps read progress of topic partitions in a YDB table instead of server-side consumer.
+// Offsets stored per topic, partition and group - every group reads the topic independently.
+//
+// Use ReaderOptions for start reader without consumer from stored offsets, then save progress with Commit
+// or with CommitTx in the same transaction as results of messages processing.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type TableOffsetStore struct {
+	db        query.Executor
+	tablePath string
+	group     string
+}
+
+// NewTableOffsetStore create offset store for the group in table tablePath, db is usually query.Client.
+// The table can be created by CreateTable.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func NewTableOffsetStore(db query.Executor, tablePath, group string) *TableOffsetStore {
+	return &TableOffsetStore{
+		db:        db,
+		tablePath: tablePath,
+		group:     group,
+	}
+}
+
+// CreateTable creates table for store offsets, if the table not exists
+func (s *TableOffsetStore) CreateTable(ctx context.Context) error {
+	err := s.db.Exec(ctx, fmt.Sprintf("CREATE TABLE IF NOT EXISTS `%s` (\n"+
+		"	consumer_group Utf8 NOT NULL,\n"+
+		"	topic Utf8 NOT NULL,\n"+
+		"	partition_id Int64 NOT NULL,\n"+
+		"	committed_offset Int64 NOT NULL,\n"+
+		"	updated_at Timestamp,\n"+
+		"	PRIMARY KEY (consumer_group, topic, partition_id)\n"+
+		")", s.tablePath))
+	if err != nil {
+		return xerrors.WithStackTrace(fmt.Errorf("ydb: failed to create offsets table: %w", err))
+	}
+
+	return nil
+}
+
+// ReaderOptions returns options for read topic without consumer from offsets of the store
+func (s *TableOffsetStore) ReaderOptions() []topicoptions.ReaderOption {
+	return []topicoptions.ReaderOption{
+		topicoptions.WithReaderWithoutConsumer(false),
+		topicoptions.WithReaderGetPartitionStartOffset(s.GetPartitionStartOffset),
+	}
+}
+
+// GetPartitionStartOffset is topicoptions.GetPartitionStartOffsetFunc, it starts read partition
+// from stored offset. Partitions without stored offset read from server defaults.
+func (s *TableOffsetStore) GetPartitionStartOffset(
+	ctx context.Context,
+	req topicoptions.GetPartitionStartOffsetRequest,
+) (res topicoptions.GetPartitionStartOffsetResponse, err error) {
+	offset, ok, err := s.Offset(ctx, req.Topic, req.PartitionID)
+	if err != nil {
+		return res, err
+	}
+	if ok {
+		res.StartFrom(offset)
+	}
+
+	return res, nil
+}
+
+// Offset returns stored offset of the partition - offset of next message for read.
+// ok is false if the store has no offset for the partition.
+func (s *TableOffsetStore) Offset(ctx context.Context, topic string, partitionID int64) (
+	offset int64,
+	ok bool,
+	_ error,
+) {
+	row, err := s.db.QueryRow(ctx, fmt.Sprintf(`
+		SELECT committed_offset
+		FROM `+"`%s`"+`
+		WHERE consumer_group = $consumer_group AND topic = $topic AND partition_id = $partition_id
+	`, s.tablePath), query.WithParameters(s.partitionParams(topic, partitionID).Build()))
+	if err != nil {
+		if xerrors.Is(err, io.EOF) {
+			return 0, false, nil
+		}
+
+		return 0, false, xerrors.WithStackTrace(fmt.Errorf("ydb: failed to read offset from table: %w", err))
+	}
+
+	if err = row.Scan(&offset); err != nil {
+		return 0, false, xerrors.WithStackTrace(fmt.Errorf("ydb: failed to scan offset from table: %w", err))
+	}
+
+	return offset, true, nil
+}
+
+// Commit saves offset after the messages to the store, the stored offset is never moved back
+func (s *TableOffsetStore) Commit(ctx context.Context, obj topicreader.CommitRangeGetter) error {
+	return s.commit(ctx, s.db, obj)
+}
+
+// CommitTx saves offset after the messages within the transaction.
+// The offset will be saved atomically with other changes of the transaction.
+func (s *TableOffsetStore) CommitTx(ctx context.Context, tx query.TxActor, obj topicreader.CommitRangeGetter) error {
+	return s.commit(ctx, tx, obj)
+}
+
+func (s *TableOffsetStore) commit(
+	ctx context.Context,
+	executor query.Executor,
+	obj topicreader.CommitRangeGetter,
+) error {
+	commitRange := topicreadercommon.GetCommitRange(obj)
+	session := commitRange.PartitionSession
+	if session == nil {
+		return xerrors.WithStackTrace(errNoPartitionSessionForCommit)
+	}
+
+	// offset moves forward only: commit of old messages (for example after retry of transaction
+	// or from reader, which lost the partition) doesn't rewind read progress
+	err := executor.Exec(ctx, fmt.Sprintf(`
+		$current = (
+			SELECT committed_offset
+			FROM `+"`%[1]s`"+`
+			WHERE consumer_group = $consumer_group AND topic = $topic AND partition_id = $partition_id
+		);
+
+		UPSERT INTO `+"`%[1]s`"+`
+		SELECT * FROM AS_TABLE(AsList(AsStruct(
+			$consumer_group AS consumer_group,
+			$topic AS topic,
+			$partition_id AS partition_id,
+			$committed_offset AS committed_offset,
+			CurrentUtcTimestamp() AS updated_at
+		)))
+		WHERE $current IS NULL OR $current <= $committed_offset;
+	`, s.tablePath), query.WithParameters(
+		s.partitionParams(session.Topic, session.PartitionID).
+			Param("$committed_offset").Int64(commitRange.CommitOffsetEnd.ToInt64()).
+			Build(),
+	))
+	if err != nil {
+		return xerrors.WithStackTrace(fmt.Errorf("ydb: failed to save offset to table: %w", err))
+	}
+
+	return nil
+}
+
+func (s *TableOffsetStore) partitionParams(topic string, partitionID int64) params.Builder {
+	return params.Builder{}.
+		Param("$consumer_group").Text(s.group).
+		Param("$topic").Text(topic).
+		Param("$partition_id").Int64(partitionID)
+}
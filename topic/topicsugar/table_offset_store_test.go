@@ -0,0 +1,176 @@
+package topicsugar
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb"
+
+	internalquery "github.com/ydb-platform/ydb-go-sdk/v3/internal/query"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/query/options"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/topic/topicreadercommon"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+	"github.com/ydb-platform/ydb-go-sdk/v3/pkg/xtest"
+	"github.com/ydb-platform/ydb-go-sdk/v3/query"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicoptions"
+)
+
+var _ query.Executor = query.Client(nil)
+
+// fakeOffsetsExecutor emulates offsets table, writes of transaction applied on commit only
+type fakeOffsetsExecutor struct {
+	query.Executor
+
+	tableCreated bool
+	offsets      map[string]int64
+	txWrites     map[string]int64
+}
+
+func newFakeOffsetsExecutor() *fakeOffsetsExecutor {
+	return &fakeOffsetsExecutor{offsets: make(map[string]int64)}
+}
+
+func fakeOffsetsKey(parameters map[string]*Ydb.TypedValue) string {
+	return fmt.Sprintf("%v/%v/%v",
+		parameters["$consumer_group"].GetValue().GetTextValue(),
+		parameters["$topic"].GetValue().GetTextValue(),
+		parameters["$partition_id"].GetValue().GetInt64Value(),
+	)
+}
+
+func fakeOffsetsParams(opts []query.ExecuteOption) map[string]*Ydb.TypedValue {
+	parameters, err := options.ExecuteSettings(opts...).Params().ToYDB()
+	if err != nil {
+		panic(err)
+	}
+
+	return parameters
+}
+
+func (e *fakeOffsetsExecutor) Exec(_ context.Context, sql string, opts ...query.ExecuteOption) error {
+	if strings.Contains(sql, "CREATE TABLE") {
+		e.tableCreated = true
+
+		return nil
+	}
+
+	fakeOffsetsUpsert(e.offsets, opts)
+
+	return nil
+}
+
+// fakeOffsetsUpsert writes offset from parameters of the query, semantic of the query
+// (offset moves forward only) is checked by integration test
+func fakeOffsetsUpsert(dst map[string]int64, opts []query.ExecuteOption) {
+	parameters := fakeOffsetsParams(opts)
+	dst[fakeOffsetsKey(parameters)] = parameters["$committed_offset"].GetValue().GetInt64Value()
+}
+
+func (e *fakeOffsetsExecutor) QueryRow(_ context.Context, _ string, opts ...query.ExecuteOption) (query.Row, error) {
+	offset, ok := e.offsets[fakeOffsetsKey(fakeOffsetsParams(opts))]
+	if !ok {
+		return nil, xerrors.WithStackTrace(io.EOF)
+	}
+
+	columns := []*Ydb.Column{{
+		Name: "committed_offset",
+		Type: &Ydb.Type{Type: &Ydb.Type_TypeId{TypeId: Ydb.Type_INT64}},
+	}}
+	val := &Ydb.Value{Value: &Ydb.Value_Int64Value{Int64Value: offset}}
+
+	return internalquery.NewRow(columns, &Ydb.Value{Items: []*Ydb.Value{val}}), nil
+}
+
+type fakeOffsetsTx struct {
+	query.TxActor
+
+	executor *fakeOffsetsExecutor
+}
+
+func (tx *fakeOffsetsTx) Exec(_ context.Context, _ string, opts ...query.ExecuteOption) error {
+	fakeOffsetsUpsert(tx.executor.txWrites, opts)
+
+	return nil
+}
+
+func (e *fakeOffsetsExecutor) doTx(commit bool, f func(tx query.TxActor) error) error {
+	e.txWrites = make(map[string]int64)
+
+	if err := f(&fakeOffsetsTx{executor: e}); err != nil {
+		return err
+	}
+	if commit {
+		for k, v := range e.txWrites {
+			e.offsets[k] = v
+		}
+	}
+
+	return nil
+}
+
+func TestTableOffsetStore(t *testing.T) {
+	t.Run("CommitAndStartOffset", func(t *testing.T) {
+		ctx := xtest.Context(t)
+		db := newFakeOffsetsExecutor()
+		store := NewTableOffsetStore(db, "offsets", "group")
+		require.NoError(t, store.CreateTable(ctx))
+		require.True(t, db.tableCreated)
+		require.Len(t, store.ReaderOptions(), 2)
+
+		_, ok, err := store.Offset(ctx, "topic", 1)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		require.NoError(t, store.Commit(ctx, topicreadercommon.NewPublicBatchBuilder("topic", 1).Offsets(10, 11).Build()))
+
+		offset, ok, err := store.Offset(ctx, "topic", 1)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, int64(12), offset)
+
+		// other group and partition are independent
+		_, ok, err = NewTableOffsetStore(db, "offsets", "other").Offset(ctx, "topic", 1)
+		require.NoError(t, err)
+		require.False(t, ok)
+		_, ok, err = store.Offset(ctx, "topic", 2)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		res, err := store.GetPartitionStartOffset(ctx, topicoptions.GetPartitionStartOffsetRequest{
+			Topic:       "topic",
+			PartitionID: 1,
+		})
+		require.NoError(t, err)
+		require.Equal(t, func() topicoptions.GetPartitionStartOffsetResponse {
+			var expected topicoptions.GetPartitionStartOffsetResponse
+			expected.StartFrom(12)
+
+			return expected
+		}(), res)
+	})
+	t.Run("CommitTx", func(t *testing.T) {
+		ctx := xtest.Context(t)
+		db := newFakeOffsetsExecutor()
+		store := NewTableOffsetStore(db, "offsets", "group")
+
+		// rolled back transaction doesn't move offset
+		require.NoError(t, db.doTx(false, func(tx query.TxActor) error {
+			return store.CommitTx(ctx, tx, topicreadercommon.NewPublicBatchBuilder("topic", 0).Offsets(5).Build())
+		}))
+		_, ok, err := store.Offset(ctx, "topic", 0)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		require.NoError(t, db.doTx(true, func(tx query.TxActor) error {
+			return store.CommitTx(ctx, tx, topicreadercommon.NewPublicBatchBuilder("topic", 0).Offsets(5).Build())
+		}))
+		offset, ok, err := store.Offset(ctx, "topic", 0)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, int64(6), offset)
+	})
+}
@@ -0,0 +1,96 @@
+package topicsugar
+
+import (
+	"context"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicreader"
+)
+
+// TopicBatchReader is interface for topicreader.Reader
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type TopicBatchReader interface {
+	ReadMessagesBatch(ctx context.Context, opts ...topicreader.ReadBatchOption) (*topicreader.Batch, error)
+}
+
+// DecodedMessage is a message with content unmarshalled by Serde.
+// Err is not nil if the message can't be unmarshalled or has other schema, Data is zero value in the case.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type DecodedMessage[T any] struct {
+	*topicreader.Message
+	Data T
+	Err  error
+}
+
+// Schema returns schema of the message from its metadata
+func (m *DecodedMessage[T]) Schema() Schema {
+	return SchemaFromMetadata(m.Metadata)
+}
+
+// DecodedBatch is a batch of decoded messages.
+// It may be committed as topicreader.Batch, for example reader.Commit(ctx, batch)
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type DecodedBatch[T any] struct {
+	*topicreader.Batch
+	Messages []*DecodedMessage[T]
+}
+
+// Reader reads values of type T from topic, unmarshalled with Serde.
+// Error of unmarshal a message doesn't break reading, it is returned in DecodedMessage.Err
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type Reader[T any] struct {
+	r     TopicBatchReader
+	serde Serde[T]
+}
+
+// NewReader makes typed reader over topic reader
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func NewReader[T any](r TopicBatchReader, serde Serde[T]) *Reader[T] {
+	return &Reader[T]{
+		r:     r,
+		serde: serde,
+	}
+}
+
+// ReadMessagesBatch read batch of messages and unmarshal its content
+func (r *Reader[T]) ReadMessagesBatch(
+	ctx context.Context,
+	opts ...topicreader.ReadBatchOption,
+) (*DecodedBatch[T], error) {
+	batch, err := r.r.ReadMessagesBatch(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &DecodedBatch[T]{
+		Batch:    batch,
+		Messages: make([]*DecodedMessage[T], len(batch.Messages)),
+	}
+	for i, mess := range batch.Messages {
+		res.Messages[i] = r.decode(mess)
+	}
+
+	return res, nil
+}
+
+func (r *Reader[T]) decode(mess *topicreader.Message) *DecodedMessage[T] {
+	res := &DecodedMessage[T]{Message: mess}
+
+	if res.Err = r.serde.Schema().check(mess.Metadata); res.Err != nil {
+		return res
+	}
+
+	res.Err = ReadMessageDataWithCallback(mess, func(data []byte) error {
+		return r.serde.Unmarshal(data, &res.Data)
+	})
+	if res.Err != nil {
+		var zero T
+		res.Data = zero
+	}
+
+	return res
+}
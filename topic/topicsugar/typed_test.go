@@ -0,0 +1,134 @@
+package topicsugar
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Topic"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/topic/topicreadercommon"
+	"github.com/ydb-platform/ydb-go-sdk/v3/pkg/xtest"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicreader"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicwriter"
+)
+
+type testEvent struct {
+	ID   int
+	Name string
+}
+
+type fakeTopic struct {
+	session  *topicreadercommon.PartitionSession
+	messages []*topicreader.Message
+}
+
+func (f *fakeTopic) Write(_ context.Context, messages ...topicwriter.Message) error {
+	if f.session == nil {
+		f.session = topicreadercommon.NewPartitionSession(context.Background(), "topic", 0, 0, "", 0, 0, 0)
+	}
+	for _, mess := range messages {
+		data, err := io.ReadAll(mess.Data)
+		if err != nil {
+			return err
+		}
+		f.messages = append(f.messages, topicreadercommon.NewPublicMessageBuilder().
+			DataAndUncompressedSize(data).
+			Metadata(mess.Metadata).
+			PartitionSession(f.session).
+			Build(),
+		)
+	}
+
+	return nil
+}
+
+func (f *fakeTopic) ReadMessagesBatch(context.Context, ...topicreader.ReadBatchOption) (*topicreader.Batch, error) {
+	batch, err := topicreadercommon.NewBatch(f.session, f.messages)
+	f.messages = nil
+
+	return batch, err
+}
+
+func TestTypedWriterReader(t *testing.T) {
+	ctx := xtest.Context(t)
+	schema := Schema{Name: "event", Version: "2"}
+
+	for name, serde := range map[string]Serde[testEvent]{
+		"JSON": JSONSerde[testEvent](schema),
+		"Gob":  GobSerde[testEvent](schema),
+	} {
+		t.Run(name, func(t *testing.T) {
+			topic := &fakeTopic{}
+			w := NewWriter[testEvent](topic, serde)
+			require.NoError(t, w.Write(ctx, testEvent{ID: 1, Name: "a"}, testEvent{ID: 2, Name: "b"}))
+			require.NoError(t, w.WriteMessages(ctx, TypedMessageToWrite[testEvent]{
+				Data:     testEvent{ID: 3},
+				Metadata: map[string][]byte{"key": []byte("val")},
+			}))
+
+			batch, err := NewReader[testEvent](topic, serde).ReadMessagesBatch(ctx)
+			require.NoError(t, err)
+			require.Len(t, batch.Messages, 3)
+			for i, mess := range batch.Messages {
+				require.NoError(t, mess.Err)
+				require.Equal(t, i+1, mess.Data.ID)
+				require.Equal(t, schema, mess.Schema())
+			}
+			require.Equal(t, "b", batch.Messages[1].Data.Name)
+			require.Equal(t, []byte("val"), batch.Messages[2].Metadata["key"])
+		})
+	}
+}
+
+func TestTypedReaderPerMessageErrors(t *testing.T) {
+	ctx := xtest.Context(t)
+	topic := &fakeTopic{}
+
+	require.NoError(t, NewWriter[string](topic, SerdeFunc(Schema{},
+		func(v string) ([]byte, error) {
+			return []byte(v), nil
+		}, nil,
+	)).Write(ctx, `{"ID":1}`, `broken`))
+	require.NoError(t, NewWriter[testEvent](topic, JSONSerde[testEvent](Schema{Name: "other"})).
+		Write(ctx, testEvent{ID: 3}),
+	)
+	require.NoError(t, NewWriter[testEvent](topic, JSONSerde[testEvent](Schema{Name: "event"})).
+		Write(ctx, testEvent{ID: 4}),
+	)
+
+	batch, err := NewReader[testEvent](topic, JSONSerde[testEvent](Schema{Name: "event"})).ReadMessagesBatch(ctx)
+	require.NoError(t, err)
+	require.Len(t, batch.Messages, 4)
+
+	require.NoError(t, batch.Messages[0].Err)
+	require.Equal(t, 1, batch.Messages[0].Data.ID)
+
+	require.Error(t, batch.Messages[1].Err)
+	require.Equal(t, testEvent{}, batch.Messages[1].Data)
+
+	require.ErrorIs(t, batch.Messages[2].Err, errSchemaMismatch)
+
+	require.NoError(t, batch.Messages[3].Err)
+	require.Equal(t, 4, batch.Messages[3].Data.ID)
+}
+
+func TestProtobufSerde(t *testing.T) {
+	ctx := xtest.Context(t)
+	topic := &fakeTopic{}
+	serde := ProtobufSerde[*Ydb_Topic.MetadataItem](Schema{Name: "metadata-item"})
+
+	item := &Ydb_Topic.MetadataItem{Key: "k", Value: []byte("v")}
+	require.NoError(t, NewWriter(topic, serde).Write(ctx, item))
+
+	batch, err := NewReader(topic, serde).ReadMessagesBatch(ctx)
+	require.NoError(t, err)
+	require.Len(t, batch.Messages, 1)
+	require.NoError(t, batch.Messages[0].Err)
+	require.True(t, proto.Equal(item, batch.Messages[0].Data))
+
+	var dst proto.Message
+	require.ErrorIs(t, ProtobufSerde[proto.Message](Schema{}).Unmarshal(nil, &dst), errProtobufSerdeNilType)
+}
@@ -0,0 +1,84 @@
+package topicsugar
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicwriter"
+)
+
+// TopicMessageWriter is interface for topicwriter.Writer
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type TopicMessageWriter interface {
+	Write(ctx context.Context, messages ...topicwriter.Message) error
+}
+
+// TypedMessageToWrite is a message for write with Writer
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type TypedMessageToWrite[T any] struct {
+	Data      T
+	SeqNo     int64
+	CreatedAt time.Time
+	Metadata  map[string][]byte
+}
+
+// Writer writes values of type T into topic, marshalled with Serde
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type Writer[T any] struct {
+	w     TopicMessageWriter
+	serde Serde[T]
+}
+
+// NewWriter makes typed writer over topic writer
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func NewWriter[T any](w TopicMessageWriter, serde Serde[T]) *Writer[T] {
+	return &Writer[T]{
+		w:     w,
+		serde: serde,
+	}
+}
+
+// Write marshal values and write it to the topic with one call of underlying writer
+func (w *Writer[T]) Write(ctx context.Context, values ...T) error {
+	messages := make([]TypedMessageToWrite[T], len(values))
+	for i := range values {
+		messages[i].Data = values[i]
+	}
+
+	return w.WriteMessages(ctx, messages...)
+}
+
+// WriteMessages marshal messages and write it to the topic with one call of underlying writer
+func (w *Writer[T]) WriteMessages(ctx context.Context, messages ...TypedMessageToWrite[T]) error {
+	schema := w.serde.Schema()
+
+	res := make([]topicwriter.Message, len(messages))
+	for i := range messages {
+		data, err := w.serde.Marshal(messages[i].Data)
+		if err != nil {
+			return err
+		}
+
+		var metadata map[string][]byte
+		if len(messages[i].Metadata) > 0 {
+			metadata = make(map[string][]byte, len(messages[i].Metadata)+2) //nolint:mnd
+			for k, v := range messages[i].Metadata {
+				metadata[k] = v
+			}
+		}
+
+		res[i] = topicwriter.Message{
+			SeqNo:     messages[i].SeqNo,
+			CreatedAt: messages[i].CreatedAt,
+			Data:      bytes.NewReader(data),
+			Metadata:  schema.writeToMetadata(metadata),
+		}
+	}
+
+	return w.w.Write(ctx, res...)
+}
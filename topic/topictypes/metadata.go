@@ -0,0 +1,77 @@
+package topictypes
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/grpcwrapper/rawtopic/rawtopiccommon"
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+)
+
+// Metadata gives typed access to metadata of topic messages: topicwriter.Message.Metadata
+// and topicreader.Message.Metadata can be converted to the type.
+// Typed values stored as text, for read them by other clients of the topic.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type Metadata map[string][]byte
+
+// SetString sets string value of the key
+func (m Metadata) SetString(key, value string) {
+	m[key] = []byte(value)
+}
+
+// SetInt64 sets integer value of the key in decimal format
+func (m Metadata) SetInt64(key string, value int64) {
+	m[key] = strconv.AppendInt(nil, value, 10) //nolint:mnd
+}
+
+// SetTime sets time value of the key in RFC3339 format with nanoseconds
+func (m Metadata) SetTime(key string, value time.Time) {
+	m[key] = value.AppendFormat(nil, time.RFC3339Nano)
+}
+
+// GetString returns value of the key as string, ok is false if the key not exists
+func (m Metadata) GetString(key string) (value string, ok bool) {
+	raw, ok := m[key]
+	if !ok {
+		return "", false
+	}
+
+	return string(raw), true
+}
+
+// GetInt64 returns integer value of the key, set by SetInt64. ok is false if the key not exists.
+func (m Metadata) GetInt64(key string) (value int64, ok bool, _ error) {
+	raw, ok := m[key]
+	if !ok {
+		return 0, false, nil
+	}
+
+	value, err := strconv.ParseInt(string(raw), 10, 64) //nolint:mnd
+	if err != nil {
+		return 0, true, xerrors.WithStackTrace(fmt.Errorf("ydb: failed to parse metadata %q as integer: %w", key, err))
+	}
+
+	return value, true, nil
+}
+
+// GetTime returns time value of the key, set by SetTime. ok is false if the key not exists.
+func (m Metadata) GetTime(key string) (value time.Time, ok bool, _ error) {
+	raw, ok := m[key]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+
+	value, err := time.Parse(time.RFC3339Nano, string(raw))
+	if err != nil {
+		return time.Time{}, true, xerrors.WithStackTrace(fmt.Errorf("ydb: failed to parse metadata %q as time: %w", key, err))
+	}
+
+	return value, true, nil
+}
+
+// Size returns total size of keys and values, it is checked by writer for metadata limits
+func (m Metadata) Size() int {
+	return rawtopiccommon.MetadataSize(m)
+}
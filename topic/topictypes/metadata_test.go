@@ -0,0 +1,52 @@
+package topictypes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetadata(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 10, time.UTC)
+
+	metadata := Metadata{}
+	metadata.SetString("str", "value")
+	metadata.SetInt64("int", -42)
+	metadata.SetTime("time", ts)
+
+	// values are stored as text
+	require.Equal(t, []byte("-42"), metadata["int"])
+	require.Equal(t, []byte("2024-05-06T07:08:09.00000001Z"), metadata["time"])
+	require.Equal(t, len("str")+len("value")+len("int")+len("-42")+len("time")+len(metadata["time"]), metadata.Size())
+
+	str, ok := metadata.GetString("str")
+	require.True(t, ok)
+	require.Equal(t, "value", str)
+
+	intVal, ok, err := metadata.GetInt64("int")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, int64(-42), intVal)
+
+	timeVal, ok, err := metadata.GetTime("time")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.True(t, ts.Equal(timeVal))
+
+	_, ok = metadata.GetString("unknown")
+	require.False(t, ok)
+	_, ok, err = metadata.GetInt64("unknown")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, ok, err = metadata.GetInt64("str")
+	require.Error(t, err)
+	require.True(t, ok)
+	_, _, err = metadata.GetTime("str")
+	require.Error(t, err)
+
+	// converted from message metadata
+	var messageMetadata map[string][]byte = metadata
+	require.Equal(t, []byte("value"), messageMetadata["str"])
+}
@@ -1,6 +1,7 @@
 package topictypes
 
 import (
+	"bytes"
 	"time"
 
 	"github.com/ydb-platform/ydb-go-sdk/v3/internal/clone"
@@ -21,9 +22,14 @@ const (
 	// CodecLzop not supported by default, customer need provide own codec library
 	CodecLzop = Codec(rawtopiccommon.CodecLzop)
 
-	// CodecZstd not supported by default, customer need provide own codec library
 	CodecZstd = Codec(rawtopiccommon.CodecZstd)
 
+	// CodecCustomerFirst and CodecCustomerEnd is interval of custom codecs.
+	// Lz4 has no code in the protocol: it may be registered as custom codec with own id
+	// (see topicoptions.WithWriterCodecLz4 and topicoptions.WithReaderCodecLz4).
+	// Id of the codec isn't standard: other sdk and tools can't decode the messages,
+	// all writers and readers of the topic must use same id and the id must be added to supported codecs
+	// of the topic explicitly.
 	CodecCustomerFirst = Codec(rawtopiccommon.CodecCustomerFirst)
 	CodecCustomerEnd   = Codec(rawtopiccommon.CodecCustomerEnd) // last allowed custom codec id is CodecCustomerEnd-1
 )
@@ -231,9 +237,17 @@ type PartitionInfo struct {
 	Active             bool
 	ChildPartitionIDs  []int64
 	ParentPartitionIDs []int64
+	KeyRange           PartitionKeyRange
 	PartitionStats     PartitionStats
 }
 
+// PartitionKeyRange is a range of keys [FromBound, ToBound) of the partition in autopartitioned topic.
+// Empty FromBound mean unbounded start of range, empty ToBound mean unbounded end of range.
+type PartitionKeyRange struct {
+	FromBound []byte
+	ToBound   []byte
+}
+
 // FromRaw convert from internal format to public. Used internally only.
 func (p *PartitionInfo) FromRaw(raw *rawtopic.PartitionInfo) {
 	p.PartitionID = raw.PartitionID
@@ -241,6 +255,10 @@ func (p *PartitionInfo) FromRaw(raw *rawtopic.PartitionInfo) {
 
 	p.ChildPartitionIDs = clone.Int64Slice(raw.ChildPartitionIDs)
 	p.ParentPartitionIDs = clone.Int64Slice(raw.ParentPartitionIDs)
+	p.KeyRange = PartitionKeyRange{
+		FromBound: bytes.Clone(raw.KeyRange.FromBound),
+		ToBound:   bytes.Clone(raw.KeyRange.ToBound),
+	}
 	p.PartitionStats.FromRaw(&raw.PartitionStats)
 }
 
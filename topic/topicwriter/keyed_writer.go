@@ -0,0 +1,56 @@
+package topicwriter
+
+import (
+	"context"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/topic/topicwriterinternal"
+)
+
+// KeyedMessage is a message with key. Messages with same key are written to same partition
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type KeyedMessage = topicwriterinternal.PublicKeyedMessage
+
+// KeyedWriter routes messages to partitions of topic by message key and keeps order of messages with same key.
+// It holds one write session per partition, follows autopartitioning split/merge of partitions
+// and handles reconnects of write sessions.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type KeyedWriter struct {
+	inner *topicwriterinternal.KeyedWriter
+}
+
+// NewKeyedWriter create new keyed writer from internal type. Used internally only.
+func NewKeyedWriter(writer *topicwriterinternal.KeyedWriter) *KeyedWriter {
+	return &KeyedWriter{
+		inner: writer,
+	}
+}
+
+// Write send messages to partitions of topic by messages keys.
+// Messages with same key are written to same partition in order of Write calls.
+func (w *KeyedWriter) Write(ctx context.Context, messages ...KeyedMessage) error {
+	return w.inner.Write(ctx, messages)
+}
+
+// PartitionForKey returns partition id, which messages with the key are written to now
+func (w *KeyedWriter) PartitionForKey(key string) int64 {
+	return w.inner.PartitionForKey(key)
+}
+
+// Refresh describes topic partitions and rebalance writers if active partitions were changed.
+// Refresh is called in background periodically, call it explicitly if you know about partitions change.
+func (w *KeyedWriter) Refresh(ctx context.Context) error {
+	return w.inner.Refresh(ctx)
+}
+
+// Flush waits till all in-flight messages of all partitions are acknowledged.
+func (w *KeyedWriter) Flush(ctx context.Context) error {
+	return w.inner.Flush(ctx)
+}
+
+// Close will flush rested messages from buffers and close the writer.
+// You can't write new messages after call Close
+func (w *KeyedWriter) Close(ctx context.Context) error {
+	return w.inner.Close(ctx)
+}
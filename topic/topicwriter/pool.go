@@ -0,0 +1,54 @@
+package topicwriter
+
+import (
+	"context"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/topic/topicwriterinternal"
+)
+
+// Pool holds several writers with distinct producer ids for high-throughput parallel write to topic.
+// Write calls are balanced between writers by count of not acked messages, so reconnect of one writer
+// doesn't stall write with others. Messages with same key are always written by same writer in order.
+//
+// Messages of different writers have independent seqno and order, use WriteKeyed for keep order of
+// related messages.
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+type Pool struct {
+	inner *topicwriterinternal.WriterPool
+}
+
+// NewPool create new writer pool from internal type. Used internally only.
+func NewPool(pool *topicwriterinternal.WriterPool) *Pool {
+	return &Pool{
+		inner: pool,
+	}
+}
+
+// Write send messages with the least loaded writer of the pool.
+// Messages of one call are written by one writer in order of the messages.
+func (p *Pool) Write(ctx context.Context, messages ...Message) error {
+	return p.inner.Write(ctx, messages)
+}
+
+// WriteKeyed send messages with same key by same writer in order of Write calls.
+// Messages with empty key are written by the least loaded writer.
+func (p *Pool) WriteKeyed(ctx context.Context, messages ...KeyedMessage) error {
+	return p.inner.WriteKeyed(ctx, messages)
+}
+
+// ProducerIDForKey returns producer id of writer, which writes messages with the key
+func (p *Pool) ProducerIDForKey(key string) string {
+	return p.inner.ProducerIDForKey(key)
+}
+
+// Flush waits till all in-flight messages of all writers are acknowledged.
+func (p *Pool) Flush(ctx context.Context) error {
+	return p.inner.Flush(ctx)
+}
+
+// Close will flush rested messages from buffers and close all writers of the pool.
+// You can't write new messages after call Close
+func (p *Pool) Close(ctx context.Context) error {
+	return p.inner.Close(ctx)
+}
@@ -16,6 +16,12 @@ var (
 	// Read about versioning policy: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#deprecated
 	ErrQueueLimitExceed                      = topicwriterinternal.ErrPublicQueueIsFull
 	ErrMessagesPutToInternalQueueBeforeError = topicwriterinternal.ErrPublicMessagesPutToInternalQueueBeforeError
+
+	// ErrSpillQuotaExceeded returned by Write if messages doesn't fit to quota of the spill
+	// (topicoptions.WithWriterSpillMaxBytes)
+	//
+	// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+	ErrSpillQuotaExceeded = topicwriterinternal.ErrPublicSpillQuotaExceeded
 )
 
 // Writer represent write session to topic
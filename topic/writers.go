@@ -0,0 +1,58 @@
+package topic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/internal/xerrors"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicoptions"
+	"github.com/ydb-platform/ydb-go-sdk/v3/topic/topicwriter"
+)
+
+var errUnsupportedClient = xerrors.Wrap(errors.New("ydb: topic client doesn't support the writer"))
+
+// StartKeyedWriter start writer, which routes messages to partitions by message key.
+// It describes topic partitions before return.
+// Client must be created by driver: db.Topic().
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func StartKeyedWriter(
+	ctx context.Context,
+	client Client,
+	topicPath string,
+	opts ...topicoptions.KeyedWriterOption,
+) (*topicwriter.KeyedWriter, error) {
+	starter, ok := client.(interface {
+		StartKeyedWriter(
+			ctx context.Context,
+			topicPath string,
+			opts ...topicoptions.KeyedWriterOption,
+		) (*topicwriter.KeyedWriter, error)
+	})
+	if !ok {
+		return nil, xerrors.WithStackTrace(fmt.Errorf("%w: %T", errUnsupportedClient, client))
+	}
+
+	return starter.StartKeyedWriter(ctx, topicPath, opts...)
+}
+
+// StartWriterPool start pool of writers with distinct producer ids for parallel write to topic.
+// It is fast non block call, connections start in background.
+// Client must be created by driver: db.Topic().
+//
+// Experimental: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#experimental
+func StartWriterPool(
+	client Client,
+	topicPath string,
+	opts ...topicoptions.WriterPoolOption,
+) (*topicwriter.Pool, error) {
+	starter, ok := client.(interface {
+		StartWriterPool(topicPath string, opts ...topicoptions.WriterPoolOption) (*topicwriter.Pool, error)
+	})
+	if !ok {
+		return nil, xerrors.WithStackTrace(fmt.Errorf("%w: %T", errUnsupportedClient, client))
+	}
+
+	return starter.StartWriterPool(topicPath, opts...)
+}
@@ -136,6 +136,8 @@ type (
 
 		// TopicWriterStreamEvents
 
+		// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+		OnWriterWriteMessages func(TopicWriterWriteMessagesStartInfo) func(TopicWriterWriteMessagesDoneInfo)
 		// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
 		OnWriterCompressMessages func(TopicWriterCompressMessagesStartInfo) func(TopicWriterCompressMessagesDoneInfo)
 		// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
@@ -326,6 +328,8 @@ type (
 	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
 	TopicReaderReadMessagesDoneInfo struct {
 		MessagesCount      int
+		BytesSize          int
+		Metadata           TopicReaderMessagesMetadata
 		Topic              string
 		PartitionID        int64
 		PartitionSessionID int64
@@ -335,6 +339,17 @@ type (
 		Error              error
 	}
 
+	// TopicReaderMessagesMetadata is metadata of read messages
+	//
+	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+	TopicReaderMessagesMetadata interface {
+		// MetadataValues returns values of the metadata key from messages, which have the key
+		MetadataValues(key string) [][]byte
+
+		// MetadataSize returns total size of metadata keys and values of the messages
+		MetadataSize() int
+	}
+
 	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
 	OnReadUnknownGrpcMessageInfo struct {
 		Context            *context.Context
@@ -561,6 +576,36 @@ type (
 		Error error
 	}
 
+	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+	TopicWriterWriteMessagesStartInfo struct {
+		Context          *context.Context
+		WriterInstanceID string
+		Topic            string
+		ProducerID       string
+		MessagesCount    int
+
+		// Metadata allow to add metadata items to the messages before write, for example for propagate trace context
+		Metadata TopicWriterMessagesMetadata
+	}
+
+	// TopicWriterMessagesMetadata is metadata of messages, written by the writer
+	//
+	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+	TopicWriterMessagesMetadata interface {
+		// SetMetadata sets the metadata item to messages, which have no the key yet
+		SetMetadata(key string, value []byte)
+
+		// MetadataSize returns total size of metadata keys and values of the messages
+		MetadataSize() int
+	}
+
+	// TopicWriterWriteMessagesDoneInfo called after server acked all messages of the write or on write error
+	//
+	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+	TopicWriterWriteMessagesDoneInfo struct {
+		Error error
+	}
+
 	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
 	TopicWriterCompressMessagesStartInfo struct {
 		Context          *context.Context
@@ -585,6 +630,12 @@ type (
 		Codec            int32
 		FirstSeqNo       int64
 		MessagesCount    int
+
+		// UncompressedBytes is size of messages content before compression
+		UncompressedBytes int
+
+		// EncodedBytes is size of messages content after compression, sent to server
+		EncodedBytes int
 	}
 
 	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
@@ -807,6 +858,7 @@ type (
 		Topic              string
 		HandlerType        string // "OnReadMessages", "OnStartPartition", "OnStopPartition"
 		MessagesCount      int
+		Metadata           TopicReaderMessagesMetadata
 	}
 
 	// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
@@ -941,6 +941,41 @@ func (t *Topic) Compose(x *Topic, opts ...TopicComposeOption) *Topic {
 			}
 		}
 	}
+	{
+		h1 := t.OnWriterWriteMessages
+		h2 := x.OnWriterWriteMessages
+		ret.OnWriterWriteMessages = func(t TopicWriterWriteMessagesStartInfo) func(TopicWriterWriteMessagesDoneInfo) {
+			if options.panicCallback != nil {
+				defer func() {
+					if e := recover(); e != nil {
+						options.panicCallback(e)
+					}
+				}()
+			}
+			var r, r1 func(TopicWriterWriteMessagesDoneInfo)
+			if h1 != nil {
+				r = h1(t)
+			}
+			if h2 != nil {
+				r1 = h2(t)
+			}
+			return func(t TopicWriterWriteMessagesDoneInfo) {
+				if options.panicCallback != nil {
+					defer func() {
+						if e := recover(); e != nil {
+							options.panicCallback(e)
+						}
+					}()
+				}
+				if r != nil {
+					r(t)
+				}
+				if r1 != nil {
+					r1(t)
+				}
+			}
+		}
+	}
 	{
 		h1 := t.OnWriterCompressMessages
 		h2 := x.OnWriterCompressMessages
@@ -1803,6 +1838,21 @@ func (t *Topic) onWriterAfterFinishTransaction(t1 TopicOnWriterAfterFinishTransa
 	}
 	return res
 }
+func (t *Topic) onWriterWriteMessages(t1 TopicWriterWriteMessagesStartInfo) func(TopicWriterWriteMessagesDoneInfo) {
+	fn := t.OnWriterWriteMessages
+	if fn == nil {
+		return func(TopicWriterWriteMessagesDoneInfo) {
+			return
+		}
+	}
+	res := fn(t1)
+	if res == nil {
+		return func(TopicWriterWriteMessagesDoneInfo) {
+			return
+		}
+	}
+	return res
+}
 func (t *Topic) onWriterCompressMessages(t1 TopicWriterCompressMessagesStartInfo) func(TopicWriterCompressMessagesDoneInfo) {
 	fn := t.OnWriterCompressMessages
 	if fn == nil {
@@ -2281,16 +2331,18 @@ func TopicOnReaderReceiveDataResponse(t *Topic, c *context.Context, readerConnec
 	}
 }
 // Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
-func TopicOnReaderReadMessages(t *Topic, c *context.Context, minCount int, maxCount int, freeBufferCapacity int) func(messagesCount int, topic string, partitionID int64, partitionSessionID int64, offsetStart int64, offsetEnd int64, freeBufferCapacity int, _ error) {
+func TopicOnReaderReadMessages(t *Topic, c *context.Context, minCount int, maxCount int, freeBufferCapacity int) func(messagesCount int, bytesSize int, metadata TopicReaderMessagesMetadata, topic string, partitionID int64, partitionSessionID int64, offsetStart int64, offsetEnd int64, freeBufferCapacity int, _ error) {
 	var p TopicReaderReadMessagesStartInfo
 	p.Context = c
 	p.MinCount = minCount
 	p.MaxCount = maxCount
 	p.FreeBufferCapacity = freeBufferCapacity
 	res := t.onReaderReadMessages(p)
-	return func(messagesCount int, topic string, partitionID int64, partitionSessionID int64, offsetStart int64, offsetEnd int64, freeBufferCapacity int, e error) {
+	return func(messagesCount int, bytesSize int, metadata TopicReaderMessagesMetadata, topic string, partitionID int64, partitionSessionID int64, offsetStart int64, offsetEnd int64, freeBufferCapacity int, e error) {
 		var p TopicReaderReadMessagesDoneInfo
 		p.MessagesCount = messagesCount
+		p.BytesSize = bytesSize
+		p.Metadata = metadata
 		p.Topic = topic
 		p.PartitionID = partitionID
 		p.PartitionSessionID = partitionSessionID
@@ -2387,6 +2439,22 @@ func TopicOnWriterAfterFinishTransaction(t *Topic, c *context.Context, e error,
 	}
 }
 // Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
+func TopicOnWriterWriteMessages(t *Topic, c *context.Context, writerInstanceID string, topic string, producerID string, messagesCount int, metadata TopicWriterMessagesMetadata) func(error) {
+	var p TopicWriterWriteMessagesStartInfo
+	p.Context = c
+	p.WriterInstanceID = writerInstanceID
+	p.Topic = topic
+	p.ProducerID = producerID
+	p.MessagesCount = messagesCount
+	p.Metadata = metadata
+	res := t.onWriterWriteMessages(p)
+	return func(e error) {
+		var p TopicWriterWriteMessagesDoneInfo
+		p.Error = e
+		res(p)
+	}
+}
+// Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
 func TopicOnWriterCompressMessages(t *Topic, c *context.Context, writerInstanceID string, sessionID string, codec int32, firstSeqNo int64, messagesCount int, reason TopicWriterCompressMessagesReason) func(error) {
 	var p TopicWriterCompressMessagesStartInfo
 	p.Context = c
@@ -2404,7 +2472,7 @@ func TopicOnWriterCompressMessages(t *Topic, c *context.Context, writerInstanceI
 	}
 }
 // Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
-func TopicOnWriterSendMessages(t *Topic, c *context.Context, writerInstanceID string, sessionID string, codec int32, firstSeqNo int64, messagesCount int) func(error) {
+func TopicOnWriterSendMessages(t *Topic, c *context.Context, writerInstanceID string, sessionID string, codec int32, firstSeqNo int64, messagesCount int, uncompressedBytes int, encodedBytes int) func(error) {
 	var p TopicWriterSendMessagesStartInfo
 	p.Context = c
 	p.WriterInstanceID = writerInstanceID
@@ -2412,6 +2480,8 @@ func TopicOnWriterSendMessages(t *Topic, c *context.Context, writerInstanceID st
 	p.Codec = codec
 	p.FirstSeqNo = firstSeqNo
 	p.MessagesCount = messagesCount
+	p.UncompressedBytes = uncompressedBytes
+	p.EncodedBytes = encodedBytes
 	res := t.onWriterSendMessages(p)
 	return func(e error) {
 		var p TopicWriterSendMessagesDoneInfo
@@ -2576,7 +2646,7 @@ func TopicOnPartitionWorkerProcessMessage(t *Topic, c *context.Context, listener
 	}
 }
 // Internals: https://github.com/ydb-platform/ydb-go-sdk/blob/master/VERSIONING.md#internals
-func TopicOnPartitionWorkerHandlerCall(t *Topic, c *context.Context, listenerID string, sessionID string, partitionSessionID int64, partitionID int64, topic string, handlerType string, messagesCount int) func(error) {
+func TopicOnPartitionWorkerHandlerCall(t *Topic, c *context.Context, listenerID string, sessionID string, partitionSessionID int64, partitionID int64, topic string, handlerType string, messagesCount int, metadata TopicReaderMessagesMetadata) func(error) {
 	var p TopicPartitionWorkerHandlerCallStartInfo
 	p.Context = c
 	p.ListenerID = listenerID
@@ -2586,6 +2656,7 @@ func TopicOnPartitionWorkerHandlerCall(t *Topic, c *context.Context, listenerID
 	p.Topic = topic
 	p.HandlerType = handlerType
 	p.MessagesCount = messagesCount
+	p.Metadata = metadata
 	res := t.onPartitionWorkerHandlerCall(p)
 	return func(e error) {
 		var p TopicPartitionWorkerHandlerCallDoneInfo